@@ -16,6 +16,7 @@ import (
 	"github.com/loft-sh/devpod/pkg/dockercredentials"
 	"github.com/loft-sh/devpod/pkg/gitcredentials"
 	"github.com/loft-sh/devpod/pkg/gitsshsigning"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/devpod/pkg/netstat"
 	portpkg "github.com/loft-sh/devpod/pkg/port"
 	"github.com/loft-sh/log"
@@ -99,8 +100,11 @@ func (cmd *CredentialsServerCmd) Run(ctx context.Context, port int) error {
 		return nil
 	}
 
+	// on the host, the docker and git config of the user is left untouched
+	onHost := hostdir.Dir() != ""
+
 	// configure docker credential helper
-	if cmd.ConfigureDockerHelper {
+	if cmd.ConfigureDockerHelper && !onHost {
 		err = dockercredentials.ConfigureCredentialsContainer(cmd.User, port, log)
 		if err != nil {
 			return err
@@ -108,10 +112,12 @@ func (cmd *CredentialsServerCmd) Run(ctx context.Context, port int) error {
 	}
 
 	// configure git user
-	err = configureGitUserLocally(ctx, cmd.User, tunnelClient)
-	if err != nil {
-		log.Debugf("Error configuring git user: %v", err)
-		return err
+	if !onHost {
+		err = configureGitUserLocally(ctx, cmd.User, tunnelClient)
+		if err != nil {
+			log.Debugf("Error configuring git user: %v", err)
+			return err
+		}
 	}
 
 	// configure git credential helper
@@ -47,6 +47,8 @@ func NewDaemonCmd() *cobra.Command {
 		RunE:  cmd.Run,
 	}
 	daemonCmd.Flags().StringVar(&cmd.Config.Timeout, "timeout", "", "The timeout to stop the container after")
+	daemonCmd.Flags().StringVar(&cmd.Config.Shutdown.Action, "shutdown-action", "", "The shutdownAction to apply once the last session disconnected")
+	daemonCmd.Flags().StringVar(&cmd.Config.Shutdown.GracePeriod, "shutdown-grace-period", "", "The duration to wait after the last session disconnected before the shutdownAction is applied")
 	return daemonCmd
 }
 
@@ -99,6 +101,13 @@ func (cmd *DaemonCmd) Run(c *cobra.Command, args []string) error {
 		go runTimeoutMonitor(ctx, timeoutDuration, errChan, &wg)
 	}
 
+	// Start shutdown monitor.
+	if cmd.shouldRunShutdownMonitor() {
+		tasksStarted = true
+		wg.Add(1)
+		go runShutdownMonitor(ctx, cmd.Config.Shutdown, cmd.Log, errChan, &wg)
+	}
+
 	// Start ssh server.
 	if cmd.shouldRunSsh() {
 		tasksStarted = true
@@ -132,7 +141,7 @@ func (cmd *DaemonCmd) Run(c *cobra.Command, args []string) error {
 }
 
 // loadConfig loads the daemon configuration from base64-encoded JSON.
-// If a CLI-provided timeout exists, it will override the timeout in the config.
+// If a CLI-provided timeout or shutdown action exists, it will override the one in the config.
 func (cmd *DaemonCmd) loadConfig() error {
 	// check local file
 	encodedCfg := ""
@@ -160,6 +169,9 @@ func (cmd *DaemonCmd) loadConfig() error {
 		if cmd.Config.Timeout != "" {
 			cfg.Timeout = cmd.Config.Timeout
 		}
+		if cmd.Config.Shutdown.Action != "" {
+			cfg.Shutdown = cmd.Config.Shutdown
+		}
 		cmd.Config = &cfg
 	}
 
@@ -178,6 +190,12 @@ func (cmd *DaemonCmd) shouldRunSsh() bool {
 	return cmd.Config.Ssh.Workdir != "" || cmd.Config.Ssh.User != ""
 }
 
+// shouldRunShutdownMonitor returns true if the container should be stopped once the last session disconnected.
+func (cmd *DaemonCmd) shouldRunShutdownMonitor() bool {
+	return cmd.Config.Shutdown.Action == config.ShutdownActionStopContainer ||
+		cmd.Config.Shutdown.Action == config.ShutdownActionStopCompose
+}
+
 // setupActivityFile creates and sets permissions on the container activity file.
 func setupActivityFile() error {
 	if err := os.WriteFile(agent.ContainerActivityFile, nil, 0777); err != nil {
@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/devpod/pkg/compress"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/setup"
+	"github.com/loft-sh/devpod/pkg/hostdir"
+	"github.com/loft-sh/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// LifecycleHooksCmd holds the cmd flags
+type LifecycleHooksCmd struct {
+	SetupInfo string
+
+	Phase string
+	Force bool
+	Only  string
+}
+
+// NewLifecycleHooksCmd creates a new command
+func NewLifecycleHooksCmd() *cobra.Command {
+	cmd := &LifecycleHooksCmd{}
+	lifecycleHooksCmd := &cobra.Command{
+		Use:   "lifecycle-hooks",
+		Short: "Runs the lifecycle hooks after waitFor in the background or a single lifecycle hook",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return cmd.Run(c.Context())
+		},
+	}
+	lifecycleHooksCmd.Flags().StringVar(&cmd.SetupInfo, "setup-info", "", "The container setup info, if empty the result of the last setup is used")
+	lifecycleHooksCmd.Flags().StringVar(&cmd.Phase, "phase", "", "If set, only the given lifecycle hook runs, e.g. postCreateCommand")
+	lifecycleHooksCmd.Flags().BoolVar(&cmd.Force, "force", false, "If enabled, runs the lifecycle hook even if it already ran in the container")
+	lifecycleHooksCmd.Flags().StringVar(&cmd.Only, "only", "", "If set, only runs the named command of an object-form lifecycle hook")
+	return lifecycleHooksCmd
+}
+
+// Run runs the command logic
+func (cmd *LifecycleHooksCmd) Run(ctx context.Context) error {
+	setupInfo, err := cmd.getSetupInfo()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Phase != "" {
+		return setup.RunLifecycleHook(ctx, setupInfo, cmd.Phase, cmd.Force, cmd.Only, log.Default)
+	}
+
+	return setup.RunBackgroundLifecycleHooks(ctx, setupInfo, log.Default)
+}
+
+func (cmd *LifecycleHooksCmd) getSetupInfo() (*config.Result, error) {
+	setupInfo := &config.Result{}
+	if cmd.SetupInfo == "" {
+		// the result is written after the container env was filled
+		out, err := os.ReadFile(hostdir.Path(setup.ResultLocation))
+		if err != nil {
+			return nil, fmt.Errorf("read setup result, make sure the workspace is running: %w", err)
+		}
+
+		err = json.Unmarshal(out, setupInfo)
+		if err != nil {
+			return nil, fmt.Errorf("parse setup result: %w", err)
+		}
+
+		return setupInfo, nil
+	}
+
+	decompressed, err := compress.Decompress(cmd.SetupInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(decompressed), setupInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fillContainerEnv(setupInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return setupInfo, nil
+}
+
+func fillContainerEnv(setupInfo *config.Result) error {
+	// set remote-env
+	if setupInfo.MergedConfig.RemoteEnv == nil {
+		setupInfo.MergedConfig.RemoteEnv = make(map[string]string)
+	}
+
+	if _, ok := setupInfo.MergedConfig.RemoteEnv["PATH"]; !ok {
+		setupInfo.MergedConfig.RemoteEnv["PATH"] = "${containerEnv:PATH}"
+	}
+
+	// merge config
+	newMergedConfig := &config.MergedDevContainerConfig{}
+	err := config.SubstituteContainerEnv(config.ListToObject(os.Environ()), setupInfo.MergedConfig, newMergedConfig)
+	if err != nil {
+		return errors.Wrap(err, "substitute container env")
+	}
+	setupInfo.MergedConfig = newMergedConfig
+	return nil
+}
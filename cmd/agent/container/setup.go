@@ -32,9 +32,11 @@ import (
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/devcontainer/setup"
 	"github.com/loft-sh/devpod/pkg/dockercredentials"
+	"github.com/loft-sh/devpod/pkg/driver/host"
 	"github.com/loft-sh/devpod/pkg/envfile"
 	"github.com/loft-sh/devpod/pkg/extract"
 	"github.com/loft-sh/devpod/pkg/git"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/devpod/pkg/ide/fleet"
 	"github.com/loft-sh/devpod/pkg/ide/jetbrains"
 	"github.com/loft-sh/devpod/pkg/ide/jupyter"
@@ -195,11 +197,19 @@ func (cmd *SetupContainerCmd) Run(ctx context.Context) error {
 	}
 
 	// setup container
-	err = setup.SetupContainer(ctx, setupInfo, workspaceInfo.CLIOptions.WorkspaceEnv, cmd.ChownWorkspace, &workspaceInfo.CLIOptions.Platform, tunnelClient, logger)
+	runHooksInBackground, err := setup.SetupContainer(ctx, setupInfo, workspaceInfo.CLIOptions.WorkspaceEnv, cmd.ChownWorkspace, &workspaceInfo.CLIOptions.Platform, tunnelClient, logger)
 	if err != nil {
 		return err
 	}
 
+	// run the lifecycle hooks after waitFor in the background
+	if runHooksInBackground {
+		err = cmd.runLifecycleHooksInBackground(setupInfo, logger)
+		if err != nil {
+			return err
+		}
+	}
+
 	// install IDE
 	err = cmd.installIDE(setupInfo, &workspaceInfo.IDE, logger)
 	if err != nil {
@@ -207,15 +217,33 @@ func (cmd *SetupContainerCmd) Run(ctx context.Context) error {
 	}
 
 	// start container daemon if necessary
-	if !workspaceInfo.CLIOptions.Platform.Enabled && !workspaceInfo.CLIOptions.DisableDaemon && workspaceInfo.ContainerTimeout != "" {
+	shutdownAction := ""
+	if setupInfo.MergedConfig != nil {
+		shutdownAction = setupInfo.MergedConfig.ShutdownAction
+	}
+	stopOnDisconnect := shutdownAction == config.ShutdownActionStopContainer || shutdownAction == config.ShutdownActionStopCompose
+	// the daemon stops the container, on the host the workspace is stopped with devpod stop
+	onHost := hostdir.Dir() != ""
+	if !workspaceInfo.CLIOptions.Platform.Enabled && !workspaceInfo.CLIOptions.DisableDaemon && !onHost && (workspaceInfo.ContainerTimeout != "" || stopOnDisconnect) {
 		err = single.Single("devpod.daemon.pid", func() (*exec.Cmd, error) {
-			logger.Debugf("Start DevPod Container Daemon with Inactivity Timeout %s", workspaceInfo.ContainerTimeout)
+			logger.Debugf("Start DevPod Container Daemon with Inactivity Timeout %s and shutdownAction %s", workspaceInfo.ContainerTimeout, shutdownAction)
 			binaryPath, err := os.Executable()
 			if err != nil {
 				return nil, err
 			}
 
-			return exec.Command(binaryPath, "agent", "container", "daemon", "--timeout", workspaceInfo.ContainerTimeout), nil
+			args := []string{"agent", "container", "daemon"}
+			if workspaceInfo.ContainerTimeout != "" {
+				args = append(args, "--timeout", workspaceInfo.ContainerTimeout)
+			}
+			if stopOnDisconnect {
+				args = append(args, "--shutdown-action", shutdownAction)
+				if workspaceInfo.ShutdownGracePeriod != "" {
+					args = append(args, "--shutdown-grace-period", workspaceInfo.ShutdownGracePeriod)
+				}
+			}
+
+			return exec.Command(binaryPath, args...), nil
 		})
 		if err != nil {
 			return err
@@ -235,26 +263,6 @@ func (cmd *SetupContainerCmd) Run(ctx context.Context) error {
 	return nil
 }
 
-func fillContainerEnv(setupInfo *config.Result) error {
-	// set remote-env
-	if setupInfo.MergedConfig.RemoteEnv == nil {
-		setupInfo.MergedConfig.RemoteEnv = make(map[string]string)
-	}
-
-	if _, ok := setupInfo.MergedConfig.RemoteEnv["PATH"]; !ok {
-		setupInfo.MergedConfig.RemoteEnv["PATH"] = "${containerEnv:PATH}"
-	}
-
-	// merge config
-	newMergedConfig := &config.MergedDevContainerConfig{}
-	err := config.SubstituteContainerEnv(config.ListToObject(os.Environ()), setupInfo.MergedConfig, newMergedConfig)
-	if err != nil {
-		return errors.Wrap(err, "substitute container env")
-	}
-	setupInfo.MergedConfig = newMergedConfig
-	return nil
-}
-
 func dockerlessBuild(
 	ctx context.Context,
 	setupInfo *config.Result,
@@ -471,6 +479,31 @@ func (cmd *SetupContainerCmd) installIDE(setupInfo *config.Result, ide *provider
 	return nil
 }
 
+func (cmd *SetupContainerCmd) runLifecycleHooksInBackground(setupInfo *config.Result, log log.Logger) error {
+	return single.Single("lifecycle-hooks-async.pid", func() (*exec.Cmd, error) {
+		log.Infof("Run remaining lifecycle hooks after %s in the background, check their progress with 'devpod status' and 'devpod logs'", setupInfo.MergedConfig.WaitFor)
+		binaryPath, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+
+		hooksCmd := exec.Command(binaryPath, "agent", "container", "lifecycle-hooks", "--setup-info", cmd.SetupInfo)
+
+		// write the output to the container logs if the container daemon is the init process
+		logFile, flags := "/proc/1/fd/1", os.O_WRONLY
+		if hostdir.Dir() != "" {
+			logFile, flags = filepath.Join(hostdir.Dir(), host.LogFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND
+		}
+		daemonOutput, err := os.OpenFile(logFile, flags, 0o644)
+		if err == nil {
+			hooksCmd.Stdout = daemonOutput
+			hooksCmd.Stderr = daemonOutput
+		}
+
+		return hooksCmd, nil
+	})
+}
+
 func (cmd *SetupContainerCmd) setupVSCode(setupInfo *config.Result, ideOptions map[string]config2.OptionValue, flavor vscode.Flavor, log log.Logger) error {
 	log.Debugf("Setup %s...", flavor.DisplayName())
 	vsCodeConfiguration := config.GetVSCodeConfiguration(setupInfo.MergedConfig)
@@ -0,0 +1,90 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/agent"
+	agentd "github.com/loft-sh/devpod/pkg/daemon/agent"
+	devssh "github.com/loft-sh/devpod/pkg/ssh"
+	"github.com/loft-sh/log"
+)
+
+const sessionPollInterval = 2 * time.Second
+
+// runShutdownMonitor watches the open SSH and IDE sessions and applies the shutdownAction once
+// the last session disconnected and no new session was opened within the grace period.
+func runShutdownMonitor(ctx context.Context, shutdown agentd.ShutdownConfig, log log.Logger, errChan chan<- error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	gracePeriod := time.Duration(0)
+	if shutdown.GracePeriod != "" {
+		var err error
+		gracePeriod, err = time.ParseDuration(shutdown.GracePeriod)
+		if err != nil {
+			errChan <- fmt.Errorf("parse shutdown grace period: %w", err)
+			return
+		}
+	}
+	// the connection counter needs a positive timeout to fire
+	if gracePeriod <= 0 {
+		gracePeriod = time.Millisecond
+	}
+
+	once := sync.Once{}
+	counter := devssh.NewConnectionCounter(ctx, gracePeriod, func() {
+		once.Do(func() {
+			go applyShutdownAction(shutdown, log, errChan)
+		})
+	}, "container", log)
+
+	ticker := time.NewTicker(sessionPollInterval)
+	defer ticker.Stop()
+
+	sessions := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := agent.CountSessions()
+			if err != nil {
+				log.Debugf("Error counting sessions: %v", err)
+				continue
+			}
+
+			for ; sessions < current; sessions++ {
+				counter.Add()
+			}
+			for ; sessions > current; sessions-- {
+				counter.Dec()
+			}
+		}
+	}
+}
+
+// applyShutdownAction terminates the container. For stopCompose, the other services of the compose project are
+// stopped from the host once the container stopped, see devcontainer.Runner.StopComposeOnExit.
+func applyShutdownAction(shutdown agentd.ShutdownConfig, log log.Logger, errChan chan<- error) {
+	log.Infof("Last session disconnected, applying shutdownAction %s", shutdown.Action)
+
+	// the dev container stops as soon as its init process exits
+	if os.Getpid() == 1 {
+		errChan <- nil
+		return
+	}
+
+	initProcess, err := os.FindProcess(1)
+	if err == nil {
+		err = initProcess.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		errChan <- fmt.Errorf("stop container: %w", err)
+		return
+	}
+	errChan <- nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/devcontainer/setup"
 	"github.com/loft-sh/devpod/pkg/encoding"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	provider2 "github.com/loft-sh/devpod/pkg/provider"
 	"github.com/loft-sh/log"
 	"github.com/spf13/cobra"
@@ -71,7 +72,7 @@ func (cmd *ContainerTunnelCmd) Run(ctx context.Context, log log.Logger) error {
 	}
 
 	// wait until devcontainer is started
-	err = startDevContainer(ctx, workspaceInfo, runner, log)
+	err = startDevContainer(ctx, cmd.WorkspaceInfo, workspaceInfo, runner, log)
 	if err != nil {
 		return err
 	}
@@ -104,7 +105,7 @@ func (cmd *ContainerTunnelCmd) Run(ctx context.Context, log log.Logger) error {
 	return nil
 }
 
-func startDevContainer(ctx context.Context, workspaceConfig *provider2.AgentWorkspaceInfo, runner devcontainer.Runner, log log.Logger) error {
+func startDevContainer(ctx context.Context, workspaceInfoEncoded string, workspaceConfig *provider2.AgentWorkspaceInfo, runner devcontainer.Runner, log log.Logger) error {
 	containerDetails, err := runner.Find(ctx)
 	if err != nil {
 		return err
@@ -113,14 +114,16 @@ func startDevContainer(ctx context.Context, workspaceConfig *provider2.AgentWork
 	// start container if necessary
 	if containerDetails == nil || containerDetails.State.Status != "running" {
 		// start container
-		_, err = StartContainer(ctx, runner, log, workspaceConfig)
+		result, err := StartContainer(ctx, runner, log, workspaceConfig)
 		if err != nil {
 			return err
 		}
+
+		workspace.StartStopComposeOnExit(workspaceInfoEncoded, workspaceConfig, result, log)
 	} else if encoding.IsLegacyUID(workspaceConfig.Workspace.UID) {
 		// make sure workspace result is in devcontainer
 		buf := &bytes.Buffer{}
-		err = runner.Command(ctx, "root", "cat "+setup.ResultLocation, nil, buf, buf)
+		err = runner.Command(ctx, "root", "cat "+hostdir.ShellPath(setup.ResultLocation), nil, buf, buf)
 		if err != nil {
 			// start container
 			_, err = StartContainer(ctx, runner, log, workspaceConfig)
@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd holds the cmd flags
+type DiffCmd struct {
+	*flags.GlobalFlags
+
+	WorkspaceInfo string
+}
+
+// NewDiffCmd creates a new command
+func NewDiffCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DiffCmd{
+		GlobalFlags: flags,
+	}
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compares the remote container with its current configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run(context.Background(), log.Default.ErrorStreamOnly())
+		},
+	}
+	diffCmd.Flags().StringVar(&cmd.WorkspaceInfo, "workspace-info", "", "The workspace info")
+	_ = diffCmd.MarkFlagRequired("workspace-info")
+	return diffCmd
+}
+
+func (cmd *DiffCmd) Run(ctx context.Context, log log.Logger) error {
+	// get workspace
+	shouldExit, workspaceInfo, err := agent.WorkspaceInfo(cmd.WorkspaceInfo, log)
+	if err != nil {
+		return fmt.Errorf("error parsing workspace info: %w", err)
+	} else if shouldExit {
+		return nil
+	}
+
+	// create runner
+	runner, err := CreateRunner(workspaceInfo, log)
+	if err != nil {
+		return err
+	}
+
+	result, err := runner.Diff(ctx, workspaceInfo.CLIOptions)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(out))
+	return nil
+}
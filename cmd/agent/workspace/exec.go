@@ -0,0 +1,129 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/devcontainer"
+	"github.com/loft-sh/devpod/pkg/driver"
+	devssh "github.com/loft-sh/devpod/pkg/ssh"
+	"github.com/loft-sh/log"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// ExecCmd holds the cmd flags
+type ExecCmd struct {
+	*flags.GlobalFlags
+
+	ID      string
+	User    string
+	Command string
+}
+
+// NewExecCmd creates a new command
+func NewExecCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ExecCmd{
+		GlobalFlags: flags,
+	}
+	c := &cobra.Command{
+		Use:   "exec",
+		Short: "Runs a command in the workspace container through the driver",
+		Long: `Runs a command in the workspace container through the driver. If stdin is a terminal and
+the driver supports it, the command gets a pseudo terminal that follows the size of the terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+	c.Flags().StringVar(&cmd.ID, "id", "", "The workspace id")
+	_ = c.MarkFlagRequired("id")
+	c.Flags().StringVar(&cmd.User, "user", "root", "The user to run the command as")
+	c.Flags().StringVar(&cmd.Command, "command", "sh", "The command to run")
+	return c
+}
+
+func (cmd *ExecCmd) Run(ctx context.Context) error {
+	// get workspace info
+	shouldExit, workspaceInfo, err := agent.ReadAgentWorkspaceInfo(cmd.AgentDir, cmd.Context, cmd.ID, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return err
+	} else if shouldExit {
+		return nil
+	}
+	logger := log.Default.ErrorStreamOnly()
+
+	// create new runner
+	runner, err := devcontainer.NewRunner(agent.ContainerDevPodHelperLocation, agent.DefaultAgentDownloadURL(), workspaceInfo, logger)
+	if err != nil {
+		return fmt.Errorf("create runner: %w", err)
+	}
+
+	options := &driver.ExecOptions{
+		User:    cmd.User,
+		Command: cmd.Command,
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		tty, resize, restore, err := watchTerminal(ctx, int(os.Stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		options.TTY = tty
+		options.Resize = resize
+	}
+
+	return runner.Exec(ctx, options)
+}
+
+// watchTerminal puts the terminal into raw mode and returns its size and a channel with its size changes
+func watchTerminal(ctx context.Context, fd int) (*driver.TTY, <-chan driver.TTY, func(), error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	getSize := func() driver.TTY {
+		tty := driver.TTY{Term: "xterm-256color", Width: 80, Height: 40}
+		if termEnv, ok := os.LookupEnv("TERM"); ok {
+			tty.Term = termEnv
+		}
+		if width, height, err := term.GetSize(fd); err == nil {
+			tty.Width, tty.Height = width, height
+		}
+
+		return tty
+	}
+
+	resize := make(chan driver.TTY)
+	windowChange := devssh.WatchWindowSize(ctx)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-windowChange:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case resize <- getSize():
+			}
+		}
+	}()
+
+	tty := getSize()
+	return &tty, resize, func() { _ = term.Restore(fd, state) }, nil
+}
@@ -0,0 +1,121 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/command"
+	"github.com/loft-sh/devpod/pkg/devcontainer/setup"
+	"github.com/loft-sh/devpod/pkg/hostdir"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// HooksCmd holds the cmd flags
+type HooksCmd struct {
+	*flags.GlobalFlags
+
+	WorkspaceInfo string
+
+	Force bool
+	Only  string
+}
+
+// NewHooksCmd creates a new command
+func NewHooksCmd(flags *flags.GlobalFlags) *cobra.Command {
+	hooksCmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the lifecycle hooks of a remote container",
+	}
+
+	hooksCmd.AddCommand(NewHooksStatusCmd(flags))
+	hooksCmd.AddCommand(NewHooksRunCmd(flags))
+	return hooksCmd
+}
+
+// NewHooksStatusCmd creates a new command
+func NewHooksStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &HooksCmd{
+		GlobalFlags: flags,
+	}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Prints the status of the lifecycle hooks running in the background",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Status(context.Background(), log.Default.ErrorStreamOnly())
+		},
+	}
+	statusCmd.Flags().StringVar(&cmd.WorkspaceInfo, "workspace-info", "", "The workspace info")
+	_ = statusCmd.MarkFlagRequired("workspace-info")
+	return statusCmd
+}
+
+// NewHooksRunCmd creates a new command
+func NewHooksRunCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &HooksCmd{
+		GlobalFlags: flags,
+	}
+	runCmd := &cobra.Command{
+		Use:   "run [phase]",
+		Short: "Runs a lifecycle hook in the remote container",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background(), args[0], log.Default.ErrorStreamOnly())
+		},
+	}
+	runCmd.Flags().StringVar(&cmd.WorkspaceInfo, "workspace-info", "", "The workspace info")
+	_ = runCmd.MarkFlagRequired("workspace-info")
+	runCmd.Flags().BoolVar(&cmd.Force, "force", false, "If enabled, runs the lifecycle hook even if it already ran in the container")
+	runCmd.Flags().StringVar(&cmd.Only, "only", "", "If set, only runs the named command of an object-form lifecycle hook")
+	return runCmd
+}
+
+// Run runs the lifecycle hook inside the dev container
+func (cmd *HooksCmd) Run(ctx context.Context, phase string, log log.Logger) error {
+	// get workspace
+	shouldExit, workspaceInfo, err := agent.WorkspaceInfo(cmd.WorkspaceInfo, log)
+	if err != nil {
+		return fmt.Errorf("error parsing workspace info: %w", err)
+	} else if shouldExit {
+		return nil
+	}
+
+	// create runner
+	runner, err := CreateRunner(workspaceInfo, log)
+	if err != nil {
+		return err
+	}
+
+	args := []string{agent.ContainerDevPodHelperLocation, "agent", "container", "lifecycle-hooks", "--phase", phase}
+	if cmd.Force {
+		args = append(args, "--force")
+	}
+	if cmd.Only != "" {
+		args = append(args, "--only", cmd.Only)
+	}
+
+	return runner.Command(ctx, "root", command.Quote(args), nil, os.Stdout, os.Stderr)
+}
+
+// Status prints the lifecycle hooks status file of the dev container, if there is one
+func (cmd *HooksCmd) Status(ctx context.Context, log log.Logger) error {
+	// get workspace
+	shouldExit, workspaceInfo, err := agent.WorkspaceInfo(cmd.WorkspaceInfo, log)
+	if err != nil {
+		return fmt.Errorf("error parsing workspace info: %w", err)
+	} else if shouldExit {
+		return nil
+	}
+
+	// create runner
+	runner, err := CreateRunner(workspaceInfo, log)
+	if err != nil {
+		return err
+	}
+
+	return runner.Command(ctx, "root", "cat "+hostdir.ShellPath(setup.LifecycleHooksStatusFile)+" 2>/dev/null || true", nil, os.Stdout, os.Stderr)
+}
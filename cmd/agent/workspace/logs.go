@@ -16,7 +16,8 @@ import (
 type LogsCmd struct {
 	*flags.GlobalFlags
 
-	ID string
+	ID     string
+	Follow bool
 }
 
 // NewLogsCmd creates a new command
@@ -33,6 +34,7 @@ func NewLogsCmd(flags *flags.GlobalFlags) *cobra.Command {
 		},
 	}
 	c.Flags().StringVar(&cmd.ID, "id", "", "The workspace id")
+	c.Flags().BoolVar(&cmd.Follow, "follow", false, "If true will stream the logs until the command is interrupted")
 	_ = c.MarkFlagRequired("id")
 
 	return c
@@ -55,5 +57,5 @@ func (cmd *LogsCmd) Run(ctx context.Context) error {
 	}
 
 	// write devcontainer logs to stdout
-	return runner.Logs(ctx, os.Stdout)
+	return runner.Logs(ctx, cmd.Follow, os.Stdout)
 }
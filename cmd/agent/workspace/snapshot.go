@@ -0,0 +1,97 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/devcontainer"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// SnapshotCmd holds the cmd flags
+type SnapshotCmd struct {
+	*flags.GlobalFlags
+
+	WorkspaceInfo string
+	Name          string
+}
+
+// NewSnapshotCmd creates a new command
+func NewSnapshotCmd(flags *flags.GlobalFlags) *cobra.Command {
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage snapshots of a remote container",
+	}
+
+	snapshotCmd.AddCommand(newSnapshotSubCmd(flags, "create", "Creates a snapshot of a remote container", true, func(ctx context.Context, runner devcontainer.Runner, name string) (interface{}, error) {
+		return runner.CreateSnapshot(ctx, name)
+	}))
+	snapshotCmd.AddCommand(newSnapshotSubCmd(flags, "list", "Lists the snapshots on the remote server", false, func(ctx context.Context, runner devcontainer.Runner, _ string) (interface{}, error) {
+		return runner.ListSnapshots(ctx)
+	}))
+	snapshotCmd.AddCommand(newSnapshotSubCmd(flags, "delete", "Deletes a snapshot on the remote server", true, func(ctx context.Context, runner devcontainer.Runner, name string) (interface{}, error) {
+		return nil, runner.DeleteSnapshot(ctx, name)
+	}))
+	return snapshotCmd
+}
+
+func newSnapshotSubCmd(
+	flags *flags.GlobalFlags,
+	use, short string,
+	requireName bool,
+	run func(ctx context.Context, runner devcontainer.Runner, name string) (interface{}, error),
+) *cobra.Command {
+	cmd := &SnapshotCmd{
+		GlobalFlags: flags,
+	}
+	subCmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run(context.Background(), run, log.Default.ErrorStreamOnly())
+		},
+	}
+	subCmd.Flags().StringVar(&cmd.WorkspaceInfo, "workspace-info", "", "The workspace info")
+	_ = subCmd.MarkFlagRequired("workspace-info")
+	if requireName {
+		subCmd.Flags().StringVar(&cmd.Name, "name", "", "The name of the snapshot")
+		_ = subCmd.MarkFlagRequired("name")
+	}
+	return subCmd
+}
+
+func (cmd *SnapshotCmd) Run(ctx context.Context, run func(ctx context.Context, runner devcontainer.Runner, name string) (interface{}, error), log log.Logger) error {
+	// get workspace
+	shouldExit, workspaceInfo, err := agent.WorkspaceInfo(cmd.WorkspaceInfo, log)
+	if err != nil {
+		return fmt.Errorf("error parsing workspace info: %w", err)
+	} else if shouldExit {
+		return nil
+	}
+
+	// create runner
+	runner, err := CreateRunner(workspaceInfo, log)
+	if err != nil {
+		return err
+	}
+
+	result, err := run(ctx, runner, cmd.Name)
+	if err != nil {
+		return err
+	} else if result == nil {
+		return nil
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(out))
+	return nil
+}
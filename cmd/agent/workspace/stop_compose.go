@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/compose"
+	config2 "github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/single"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// StopComposeOnExitCmd holds the cmd flags
+type StopComposeOnExitCmd struct {
+	*flags.GlobalFlags
+
+	WorkspaceInfo string
+}
+
+// NewStopComposeOnExitCmd creates a new command
+func NewStopComposeOnExitCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StopComposeOnExitCmd{
+		GlobalFlags: flags,
+	}
+	stopComposeOnExitCmd := &cobra.Command{
+		Use:    "stop-compose-on-exit",
+		Short:  "Stops the docker compose project once the dev container stopped",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run(context.Background(), log.Default.ErrorStreamOnly())
+		},
+	}
+	stopComposeOnExitCmd.Flags().StringVar(&cmd.WorkspaceInfo, "workspace-info", "", "The workspace info")
+	_ = stopComposeOnExitCmd.MarkFlagRequired("workspace-info")
+	return stopComposeOnExitCmd
+}
+
+func (cmd *StopComposeOnExitCmd) Run(ctx context.Context, log log.Logger) error {
+	// get workspace
+	shouldExit, workspaceInfo, err := agent.WorkspaceInfo(cmd.WorkspaceInfo, log)
+	if err != nil {
+		return err
+	} else if shouldExit {
+		return nil
+	}
+
+	// create runner
+	runner, err := CreateRunner(workspaceInfo, log)
+	if err != nil {
+		return err
+	}
+
+	return runner.StopComposeOnExit(ctx)
+}
+
+// StartStopComposeOnExit starts a background process that stops the docker compose project once the dev container
+// stopped, if the shutdownAction of the dev container is stopCompose.
+func StartStopComposeOnExit(workspaceInfoEncoded string, workspaceInfo *provider2.AgentWorkspaceInfo, result *config2.Result, log log.Logger) {
+	if workspaceInfo.CLIOptions.Platform.Enabled || workspaceInfo.CLIOptions.DisableDaemon ||
+		result == nil || result.MergedConfig == nil || result.MergedConfig.ShutdownAction != config2.ShutdownActionStopCompose ||
+		result.ContainerDetails == nil || result.ContainerDetails.Config.Labels[compose.ProjectLabel] == "" {
+		return
+	}
+
+	err := single.Single(fmt.Sprintf("devpod.stop-compose.%s.pid", workspaceInfo.Workspace.ID), func() (*exec.Cmd, error) {
+		log.Debugf("Start watching dev container to stop docker compose project on exit")
+		binaryPath, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+
+		return exec.Command(binaryPath, "agent", "workspace", "stop-compose-on-exit", "--workspace-info", workspaceInfoEncoded), nil
+	})
+	if err != nil {
+		log.Warnf("Error starting docker compose shutdown watcher: %v", err)
+	}
+}
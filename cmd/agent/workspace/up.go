@@ -136,6 +136,8 @@ func (cmd *UpCmd) devPodUp(ctx context.Context, workspaceInfo *provider2.AgentWo
 		return nil, err
 	}
 
+	// stop the compose project once the dev container stopped itself
+	StartStopComposeOnExit(cmd.WorkspaceInfo, workspaceInfo, result, log)
 	return result, nil
 }
 
@@ -22,5 +22,10 @@ func NewWorkspaceCmd(flags *flags.GlobalFlags) *cobra.Command {
 	workspaceCmd.AddCommand(NewInstallDotfilesCmd(flags))
 	workspaceCmd.AddCommand(NewSetupGPGCmd(flags))
 	workspaceCmd.AddCommand(NewLogsCmd(flags))
+	workspaceCmd.AddCommand(NewExecCmd(flags))
+	workspaceCmd.AddCommand(NewSnapshotCmd(flags))
+	workspaceCmd.AddCommand(NewDiffCmd(flags))
+	workspaceCmd.AddCommand(NewHooksCmd(flags))
+	workspaceCmd.AddCommand(NewStopComposeOnExitCmd(flags))
 	return workspaceCmd
 }
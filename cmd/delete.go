@@ -9,6 +9,7 @@ import (
 	client2 "github.com/loft-sh/devpod/pkg/client"
 	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
 	"github.com/loft-sh/devpod/pkg/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
 	"github.com/loft-sh/devpod/pkg/workspace"
 	"github.com/loft-sh/log"
 	"github.com/spf13/cobra"
@@ -18,6 +19,8 @@ import (
 type DeleteCmd struct {
 	*flags.GlobalFlags
 	client2.DeleteOptions
+
+	Selector string
 }
 
 // NewDeleteCmd creates a new command
@@ -57,11 +60,16 @@ If the workspace is not found, you can use the --ignore-not-found flag to treat
 	deleteCmd.Flags().BoolVar(&cmd.IgnoreNotFound, "ignore-not-found", false, "Treat \"workspace not found\" as a successful delete")
 	deleteCmd.Flags().StringVar(&cmd.GracePeriod, "grace-period", "", "The amount of time to give the command to delete the workspace")
 	deleteCmd.Flags().BoolVar(&cmd.Force, "force", false, "Delete workspace even if it is not found remotely anymore")
+	deleteCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Delete all workspaces whose labels match the selector, e.g. team=a,env!=prod")
 	return deleteCmd
 }
 
 // Run runs the command logic
 func (cmd *DeleteCmd) Run(ctx context.Context, devPodConfig *config.Config, args []string) error {
+	if cmd.Selector != "" {
+		return cmd.RunSelected(ctx, devPodConfig, args)
+	}
+
 	if len(args) == 0 {
 		workspaceName, err := workspace.Delete(ctx, devPodConfig, args, cmd.IgnoreNotFound, cmd.Force, cmd.DeleteOptions, cmd.Owner, log.Default)
 		if err != nil {
@@ -81,3 +89,20 @@ func (cmd *DeleteCmd) Run(ctx context.Context, devPodConfig *config.Config, args
 	}
 	return nil
 }
+
+// RunSelected deletes all workspaces matching the selector concurrently
+func (cmd *DeleteCmd) RunSelected(ctx context.Context, devPodConfig *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("cannot specify a workspace together with --selector")
+	}
+
+	results, err := runSelected(ctx, devPodConfig, cmd.Selector, cmd.Owner, log.Default, func(ctx context.Context, workspaceConfig *provider2.Workspace) error {
+		_, err := workspace.Delete(ctx, devPodConfig, []string{workspaceConfig.ID}, cmd.IgnoreNotFound, cmd.Force, cmd.DeleteOptions, cmd.Owner, log.Default)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return aggregateSelectorResults(results, "Successfully deleted workspace '%s'", log.Default)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	config2 "github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd holds the cmd flags
+type DiffCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+}
+
+// NewDiffCmd creates a new command
+func NewDiffCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DiffCmd{
+		GlobalFlags: flags,
+	}
+	diffCmd := &cobra.Command{
+		Use:   "diff [flags] [workspace-path|workspace-name]",
+		Short: "Shows the differences between a workspace container and its devcontainer.json",
+		Long: `Compares the configuration the workspace container was created with against
+the current devcontainer.json and shows what is needed to apply each difference:
+
+  needs rebuild   the image needs to be rebuilt, run devpod up --recreate
+  needs recreate  the container needs to be recreated, run devpod up --recreate
+  needs restart   the container needs to be restarted, run devpod stop and devpod up
+  applies live    applied on the next devpod up or ssh session
+
+The devcontainer.json is read from the workspace's copy of the source. For local folders
+on remote machines this copy is only updated by devpod up, so local changes that were
+not synced yet are not detected. Image changes are not detected for containers that were
+restored with --from-snapshot until they are recreated.`,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	diffCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	return diffCmd
+}
+
+// Run runs the command logic
+func (cmd *DiffCmd) Run(ctx context.Context, args []string) error {
+	if cmd.Output != "plain" && cmd.Output != "json" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	client, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	stdout := &bytes.Buffer{}
+	err = workspace2.ExecuteAgentWorkspaceCommand(ctx, client, provider2.CLIOptions{}, []string{"diff"}, stdout, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return fmt.Errorf("diff workspace: %w", err)
+	}
+
+	result := &config2.DiffResult{}
+	err = json.Unmarshal(stdout.Bytes(), result)
+	if err != nil {
+		return errors.Wrap(err, "parse diff")
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+
+	for _, warning := range result.Warnings {
+		log.Default.Warn(warning)
+	}
+	if len(result.Differences) == 0 {
+		log.Default.Done("Workspace is up to date")
+		return nil
+	}
+
+	tableEntries := [][]string{}
+	for _, diff := range result.Differences {
+		tableEntries = append(tableEntries, []string{
+			diff.Property,
+			string(diff.Action),
+			diffValue(diff.Current),
+			diffValue(diff.Desired),
+		})
+	}
+	table.PrintTable(log.Default, []string{
+		"Property",
+		"Action",
+		"Current",
+		"Desired",
+	}, tableEntries)
+
+	switch result.Action {
+	case config2.DiffActionRebuild, config2.DiffActionRecreate:
+		log.Default.Infof("Run 'devpod up --recreate %s' to apply all changes", client.Workspace())
+	case config2.DiffActionRestart:
+		log.Default.Infof("Run 'devpod stop %s' and 'devpod up %s' to apply all changes", client.Workspace(), client.Workspace())
+	case config2.DiffActionLive:
+		log.Default.Infof("Run 'devpod up %s' to apply all changes", client.Workspace())
+	}
+
+	return nil
+}
+
+func diffValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	} else if str, ok := value.(string); ok {
+		return str
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(out)
+}
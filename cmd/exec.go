@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/alessio/shellescape"
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/machine"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/log"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// ExecCmd holds the cmd flags
+type ExecCmd struct {
+	*flags.GlobalFlags
+
+	User    string
+	Command string
+}
+
+// NewExecCmd creates a new command
+func NewExecCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ExecCmd{
+		GlobalFlags: flags,
+	}
+	execCmd := &cobra.Command{
+		Use:   "exec [flags] [workspace-path|workspace-name]",
+		Short: "Runs a command in the workspace container through the driver",
+		Long: `Runs a command in the workspace container directly through the driver of the workspace,
+without the ssh server in the container. If the terminal is interactive and the driver
+supports it, the command gets a pseudo terminal that follows the size of the terminal.`,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	execCmd.Flags().StringVar(&cmd.User, "user", "root", "The user of the workspace container to run the command as")
+	execCmd.Flags().StringVar(&cmd.Command, "command", "sh", "The command to run within the workspace container")
+	return execCmd
+}
+
+// Run runs the command logic
+func (cmd *ExecCmd) Run(ctx context.Context, args []string) error {
+	devPodConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	client, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	agentCommand := fmt.Sprintf("'%s' agent workspace exec --context '%s' --id '%s' --user %s --command %s", client.AgentPath(), client.Context(), client.Workspace(), shellescape.Quote(cmd.User), shellescape.Quote(cmd.Command))
+	if log.Default.GetLevel() == logrus.DebugLevel {
+		agentCommand += " --debug"
+	}
+
+	// the session gets a pseudo terminal if the local terminal is interactive, which the agent passes on to the driver
+	return runAgentSSHCommand(ctx, devPodConfig, client, func(sshClient *ssh.Client) error {
+		return machine.RunSSHSession(ctx, sshClient, false, agentCommand, os.Stderr)
+	})
+}
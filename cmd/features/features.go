@@ -0,0 +1,39 @@
+package features
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/spf13/cobra"
+)
+
+// NewFeaturesCmd returns a new root command
+func NewFeaturesCmd(flags *flags.GlobalFlags) *cobra.Command {
+	featuresCmd := &cobra.Command{
+		Use:   "features",
+		Short: "DevPod Feature commands",
+		Long: `Manage the devcontainer-lock.json that pins the features of a devcontainer.json
+to an exact version and digest.`,
+	}
+
+	featuresCmd.AddCommand(NewLockCmd(flags))
+	featuresCmd.AddCommand(NewUpgradeCmd(flags))
+	return featuresCmd
+}
+
+func loadDevContainerConfig(args []string, devContainerPath string) (*config.DevContainerConfig, error) {
+	folder := "."
+	if len(args) > 0 {
+		folder = args[0]
+	}
+
+	devContainerConfig, err := config.ParseDevContainerJSON(folder, devContainerPath)
+	if err != nil {
+		return nil, err
+	} else if devContainerConfig == nil {
+		return nil, fmt.Errorf("couldn't find a devcontainer.json in %s", folder)
+	}
+
+	return devContainerConfig, nil
+}
@@ -0,0 +1,64 @@
+package features
+
+import (
+	"context"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// LockCmd holds the cmd flags
+type LockCmd struct {
+	*flags.GlobalFlags
+
+	DevContainerPath string
+}
+
+// NewLockCmd creates a new command
+func NewLockCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LockCmd{
+		GlobalFlags: flags,
+	}
+	lockCmd := &cobra.Command{
+		Use:   "lock [flags] [path]",
+		Short: "Creates or updates the devcontainer-lock.json",
+		Long: `Resolves all features of the devcontainer.json in the given folder and writes their version,
+digest and integrity hash into the devcontainer-lock.json. Features that are already locked keep their
+locked version, use 'devpod features upgrade' to update them.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	lockCmd.Flags().StringVar(&cmd.DevContainerPath, "devcontainer-path", "", "The path to the devcontainer.json relative to the project")
+	return lockCmd
+}
+
+// Run runs the command logic
+func (cmd *LockCmd) Run(ctx context.Context, args []string) error {
+	devContainerConfig, err := loadDevContainerConfig(args, cmd.DevContainerPath)
+	if err != nil {
+		return err
+	}
+
+	existing, err := feature.LoadLockfile(devContainerConfig)
+	if err != nil {
+		return err
+	}
+
+	lockfile, err := feature.Lock(devContainerConfig, existing, false, nil, log.Default)
+	if err != nil {
+		return err
+	}
+
+	lockfilePath, err := feature.SaveLockfile(devContainerConfig, lockfile)
+	if err != nil {
+		return err
+	}
+
+	log.Default.Donef("Locked %d features in %s", len(lockfile.Features), lockfilePath)
+	return nil
+}
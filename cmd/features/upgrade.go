@@ -0,0 +1,86 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// UpgradeCmd holds the cmd flags
+type UpgradeCmd struct {
+	*flags.GlobalFlags
+
+	DevContainerPath string
+	Features         []string
+}
+
+// NewUpgradeCmd creates a new command
+func NewUpgradeCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &UpgradeCmd{
+		GlobalFlags: flags,
+	}
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade [flags] [path]",
+		Short: "Upgrades the features in the devcontainer-lock.json",
+		Long: `Resolves the features of the devcontainer.json in the given folder again and updates the
+devcontainer-lock.json with their latest version matching the reference in the devcontainer.json.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	upgradeCmd.Flags().StringVar(&cmd.DevContainerPath, "devcontainer-path", "", "The path to the devcontainer.json relative to the project")
+	upgradeCmd.Flags().StringArrayVar(&cmd.Features, "feature", []string{}, "Only upgrade the given feature. Can be specified multiple times")
+	return upgradeCmd
+}
+
+// Run runs the command logic
+func (cmd *UpgradeCmd) Run(ctx context.Context, args []string) error {
+	devContainerConfig, err := loadDevContainerConfig(args, cmd.DevContainerPath)
+	if err != nil {
+		return err
+	}
+
+	existing, err := feature.LoadLockfile(devContainerConfig)
+	if err != nil {
+		return err
+	}
+	for _, featureID := range cmd.Features {
+		if _, ok := devContainerConfig.Features[featureID]; !ok {
+			return fmt.Errorf("feature %s is not part of %s", featureID, devContainerConfig.Origin)
+		}
+	}
+
+	lockfile, err := feature.Lock(devContainerConfig, existing, len(cmd.Features) == 0, cmd.Features, log.Default)
+	if err != nil {
+		return err
+	}
+
+	lockfilePath, err := feature.SaveLockfile(devContainerConfig, lockfile)
+	if err != nil {
+		return err
+	}
+
+	featureIDs := []string{}
+	for featureID := range lockfile.Features {
+		featureIDs = append(featureIDs, featureID)
+	}
+	sort.Strings(featureIDs)
+	for _, featureID := range featureIDs {
+		locked := lockfile.Features[featureID]
+		if existing == nil || existing.Features[featureID] == nil {
+			log.Default.Infof("Locked feature %s at %s", featureID, locked.Version)
+		} else if previous := existing.Features[featureID]; previous.Integrity != locked.Integrity {
+			log.Default.Infof("Upgraded feature %s from %s to %s", featureID, previous.Version, locked.Version)
+		}
+	}
+
+	log.Default.Donef("Updated %s", lockfilePath)
+	return nil
+}
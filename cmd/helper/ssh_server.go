@@ -100,6 +100,14 @@ func (cmd *SSHServerCmd) Run(_ *cobra.Command, _ []string) error {
 	// should we listen on stdout & stdin?
 	if cmd.Stdio {
 		if cmd.TrackActivity {
+			// register the session, so the container daemon can apply the shutdownAction once the last session is gone
+			untrack, err := agent.TrackSession()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error tracking session: %v\n", err)
+			} else {
+				defer untrack()
+			}
+
 			go func() {
 				_, err = os.Stat(agent.ContainerActivityFile)
 				if err != nil {
@@ -0,0 +1,17 @@
+package hooks
+
+import (
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewHooksCmd returns a new root command
+func NewHooksCmd(flags *flags.GlobalFlags) *cobra.Command {
+	hooksCmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "DevPod Lifecycle Hooks commands",
+	}
+
+	hooksCmd.AddCommand(NewRunCmd(flags))
+	return hooksCmd
+}
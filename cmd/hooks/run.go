@@ -0,0 +1,109 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	"github.com/loft-sh/devpod/pkg/client"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// RunCmd holds the configuration
+type RunCmd struct {
+	*flags.GlobalFlags
+
+	Force bool
+	Only  string
+}
+
+// NewRunCmd creates a new command
+func NewRunCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RunCmd{
+		GlobalFlags: flags,
+	}
+	runCmd := &cobra.Command{
+		Use:   "run [flags] phase [workspace-path|workspace-name]",
+		Short: "Runs a lifecycle hook in a running workspace",
+		Long: `Runs a lifecycle hook in a running workspace, e.g. 'devpod hooks run postCreate my-workspace'.
+The phase is one of onCreate, updateContent, postCreate, postStart or postAttach.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args[0], args[1:])
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return phases(), cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args[1:], toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	runCmd.Flags().BoolVar(&cmd.Force, "force", false, "If enabled, runs the lifecycle hook even if it already ran in the workspace")
+	runCmd.Flags().StringVar(&cmd.Only, "only", "", "If set, only runs the named command of an object-form lifecycle hook, even if the hook already ran in the workspace")
+	return runCmd
+}
+
+// Run runs the command logic
+func (cmd *RunCmd) Run(ctx context.Context, phase string, args []string) error {
+	hook, err := parsePhase(phase)
+	if err != nil {
+		return err
+	}
+
+	workspaceClient, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	status, err := workspaceClient.Status(ctx, client.StatusOptions{ContainerStatus: true})
+	if err != nil {
+		return err
+	} else if status != client.StatusRunning {
+		return fmt.Errorf("workspace %s is %s, start it with 'devpod up %s' first", workspaceClient.Workspace(), status, workspaceClient.Workspace())
+	}
+
+	agentArgs := []string{"hooks", "run", hook}
+	if cmd.Force {
+		agentArgs = append(agentArgs, "--force")
+	}
+	if cmd.Only != "" {
+		agentArgs = append(agentArgs, "--only", cmd.Only)
+	}
+
+	err = workspace.ExecuteAgentWorkspaceCommand(ctx, workspaceClient, provider2.CLIOptions{}, agentArgs, os.Stdout, log.Default)
+	if err != nil {
+		return fmt.Errorf("run %s: %w", hook, err)
+	}
+
+	return nil
+}
+
+// parsePhase accepts the phase with or without the Command suffix, e.g. postCreate or postCreateCommand
+func parsePhase(phase string) (string, error) {
+	hook := strings.TrimSuffix(phase, "Command") + "Command"
+	if !slices.Contains(config.LifecycleHookNames, hook) {
+		return "", fmt.Errorf("unknown phase %s, must be one of %s", phase, strings.Join(phases(), ", "))
+	}
+
+	return hook, nil
+}
+
+func phases() []string {
+	phases := []string{}
+	for _, hook := range config.LifecycleHookNames {
+		phases = append(phases, strings.TrimSuffix(hook, "Command"))
+	}
+
+	return phases
+}
@@ -0,0 +1,24 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"gotest.tools/assert"
+)
+
+func TestParsePhase(t *testing.T) {
+	hook, err := parsePhase("postCreate")
+	assert.NilError(t, err)
+	assert.Equal(t, hook, config.PostCreateCommand)
+
+	hook, err = parsePhase("postCreateCommand")
+	assert.NilError(t, err)
+	assert.Equal(t, hook, config.PostCreateCommand)
+
+	_, err = parsePhase("initialize")
+	assert.ErrorContains(t, err, "unknown phase initialize")
+
+	_, err = parsePhase("")
+	assert.ErrorContains(t, err, "unknown phase")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	client2 "github.com/loft-sh/devpod/pkg/client"
+	"github.com/loft-sh/devpod/pkg/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+)
+
+// warnIdleWorkspaces prints a warning for every workspace of the current context that is about to expire or
+// already expired. Expired workspaces are stopped or deleted by the next devpod up or devpod prune.
+func warnIdleWorkspaces(devPodConfig *config.Config, log log.Logger) {
+	policy, err := workspace2.GetIdlePolicy(devPodConfig)
+	if err != nil {
+		log.Warnf("Error reading idle policy: %v", err)
+		return
+	} else if policy == nil {
+		return
+	}
+
+	idleWorkspaces, err := workspace2.ListIdle(devPodConfig, policy, time.Now(), log)
+	if err != nil {
+		log.Debugf("Error listing idle workspaces: %v", err)
+		return
+	}
+
+	for _, idleWorkspace := range idleWorkspaces {
+		if idleWorkspace.State == workspace2.IdleStateWarning {
+			log.Warnf("Workspace '%s' wasn't used for a while and will be %s in %s", idleWorkspace.Workspace.ID, idleActionPastTense(policy.Action), time.Until(idleWorkspace.ExpiresAt).Round(time.Minute))
+		} else if idleWorkspace.State == workspace2.IdleStateExpired {
+			log.Warnf("Workspace '%s' expired and will be %s by the next devpod up or devpod prune", idleWorkspace.Workspace.ID, idleActionPastTense(policy.Action))
+		}
+	}
+}
+
+// expireIdleWorkspaces applies the idle policy of the current context. Workspaces within the warning window are
+// reported, expired workspaces are stopped or deleted concurrently. The workspace skipID is never touched.
+func expireIdleWorkspaces(ctx context.Context, devPodConfig *config.Config, globalFlags *flags.GlobalFlags, skipID string, dryRun bool, log log.Logger) error {
+	policy, err := workspace2.GetIdlePolicy(devPodConfig)
+	if err != nil {
+		return err
+	} else if policy == nil {
+		return nil
+	}
+
+	idleWorkspaces, err := workspace2.ListIdle(devPodConfig, policy, time.Now(), log)
+	if err != nil {
+		return err
+	}
+
+	results := []SelectorResult{}
+	resultsChan := make(chan SelectorResult)
+	for _, idleWorkspace := range idleWorkspaces {
+		if idleWorkspace.Workspace.ID == skipID {
+			continue
+		} else if idleWorkspace.State == workspace2.IdleStateWarning {
+			log.Warnf("Workspace '%s' wasn't used for a while and will be %s in %s", idleWorkspace.Workspace.ID, idleActionPastTense(policy.Action), time.Until(idleWorkspace.ExpiresAt).Round(time.Minute))
+			continue
+		} else if dryRun {
+			log.Infof("Would %s expired workspace '%s'", policy.Action, idleWorkspace.Workspace.ID)
+			continue
+		}
+
+		results = append(results, SelectorResult{})
+		go func(workspace *provider2.Workspace) {
+			resultsChan <- SelectorResult{
+				Workspace: workspace,
+				Err:       expireWorkspace(ctx, devPodConfig, globalFlags, workspace, policy.Action, log),
+			}
+		}(idleWorkspace.Workspace)
+	}
+	for i := range results {
+		results[i] = <-resultsChan
+	}
+
+	return aggregateSelectorResults(results, "Successfully "+idleActionPastTense(policy.Action)+" expired workspace '%s'", log)
+}
+
+func expireWorkspace(ctx context.Context, devPodConfig *config.Config, globalFlags *flags.GlobalFlags, workspace *provider2.Workspace, action string, log log.Logger) error {
+	if action == config.IdleActionDelete {
+		_, err := workspace2.Delete(ctx, devPodConfig, []string{workspace.ID}, true, false, client2.DeleteOptions{}, globalFlags.Owner, log)
+		return err
+	}
+
+	client, err := workspace2.Get(ctx, devPodConfig, []string{workspace.ID}, false, globalFlags.Owner, false, log)
+	if err != nil {
+		return err
+	}
+
+	// expired workspaces keep their last used timestamp, so only stop workspaces that are still running
+	status, err := client.Status(ctx, client2.StatusOptions{})
+	if err != nil {
+		return err
+	} else if status != client2.StatusRunning {
+		return nil
+	}
+
+	return (&StopCmd{GlobalFlags: globalFlags}).Run(ctx, devPodConfig, client)
+}
+
+func idleActionPastTense(action string) string {
+	if action == config.IdleActionDelete {
+		return "deleted"
+	}
+
+	return "stopped"
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/config"
+	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LabelCmd holds the cmd flags
+type LabelCmd struct {
+	*flags.GlobalFlags
+}
+
+// NewLabelCmd creates a new command
+func NewLabelCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LabelCmd{
+		GlobalFlags: flags,
+	}
+	labelCmd := &cobra.Command{
+		Use:   "label [flags] workspace-name [key=value...] [key-...]",
+		Short: "Updates the labels of a workspace",
+		Long: `Adds, updates or removes labels of a workspace. Labels can be used to select workspaces, e.g. with devpod list --selector.
+
+Examples:
+  devpod label my-workspace team=backend ticket=ENG-123
+  devpod label my-workspace ticket-`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	return labelCmd
+}
+
+// Run runs the command logic
+func (cmd *LabelCmd) Run(ctx context.Context, args []string) error {
+	set, remove, err := workspace2.ParseLabels(args[1:], true)
+	if err != nil {
+		return err
+	}
+
+	devPodConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	workspaceID := workspace2.Exists(ctx, devPodConfig, args[:1], "", cmd.Owner, log.Default)
+	if workspaceID == "" {
+		return fmt.Errorf("couldn't find workspace %s", args[0])
+	}
+
+	workspaceConfig, err := workspace2.UpdateLabels(devPodConfig, workspaceID, set, remove)
+	if err != nil {
+		return err
+	}
+
+	log.Default.Donef("Workspace '%s' has labels: %s", workspaceConfig.ID, labels.Set(workspaceConfig.Labels).String())
+	return nil
+}
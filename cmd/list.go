@@ -13,14 +13,16 @@ import (
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/log/table"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // ListCmd holds the configuration
 type ListCmd struct {
 	*flags.GlobalFlags
 
-	Output  string
-	SkipPro bool
+	Output   string
+	SkipPro  bool
+	Selector string
 }
 
 // NewListCmd creates a new destroy command
@@ -44,6 +46,7 @@ func NewListCmd(flags *flags.GlobalFlags) *cobra.Command {
 
 	listCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
 	listCmd.Flags().BoolVar(&cmd.SkipPro, "skip-pro", false, "Don't list pro workspaces")
+	listCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Only list workspaces whose labels match the selector, e.g. team=a,env!=prod")
 	return listCmd
 }
 
@@ -58,6 +61,14 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if cmd.Selector != "" {
+		selector, err := labels.Parse(cmd.Selector)
+		if err != nil {
+			return fmt.Errorf("parse selector: %w", err)
+		}
+
+		workspaces = workspace.MatchSelector(workspaces, selector)
+	}
 
 	if cmd.Output == "json" {
 		sort.SliceStable(workspaces, func(i, j int) bool {
@@ -87,6 +98,7 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 				time.Since(entry.LastUsedTimestamp.Time).Round(1 * time.Second).String(),
 				time.Since(entry.CreationTimestamp.Time).Round(1 * time.Second).String(),
 				fmt.Sprintf("%t", entry.IsPro()),
+				labels.Set(entry.Labels).String(),
 			})
 		}
 
@@ -99,7 +111,9 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 			"Last Used",
 			"Age",
 			"Pro",
+			"Labels",
 		}, tableEntries)
+		warnIdleWorkspaces(devPodConfig, log.Default.ErrorStreamOnly())
 	} else {
 		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
 	}
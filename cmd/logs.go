@@ -11,16 +11,19 @@ import (
 	"github.com/loft-sh/devpod/pkg/agent"
 	clientpkg "github.com/loft-sh/devpod/pkg/client"
 	"github.com/loft-sh/devpod/pkg/config"
-	"github.com/loft-sh/devpod/pkg/ssh"
+	devssh "github.com/loft-sh/devpod/pkg/ssh"
 	"github.com/loft-sh/devpod/pkg/workspace"
 	"github.com/loft-sh/log"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
 )
 
 // LogsCmd holds the configuration
 type LogsCmd struct {
 	*flags.GlobalFlags
+
+	Follow bool
 }
 
 // NewLogsCmd creates a new destroy command
@@ -39,6 +42,7 @@ func NewLogsCmd(flags *flags.GlobalFlags) *cobra.Command {
 		},
 	}
 
+	startCmd.Flags().BoolVarP(&cmd.Follow, "follow", "f", false, "If true will stream the logs until the command is interrupted")
 	return startCmd
 }
 
@@ -58,6 +62,32 @@ func (cmd *LogsCmd) Run(ctx context.Context, args []string) error {
 	if !ok {
 		return fmt.Errorf("this command is not supported for proxy providers")
 	}
+
+	// create agent command
+	agentCommand := fmt.Sprintf("'%s' agent workspace logs --context '%s' --id '%s'", client.AgentPath(), client.Context(), client.Workspace())
+	if cmd.Follow {
+		agentCommand += " --follow"
+	}
+	if log.Default.GetLevel() == logrus.DebugLevel {
+		agentCommand += " --debug"
+	}
+
+	return runAgentSSHCommand(ctx, devPodConfig, client, func(sshClient *ssh.Client) error {
+		session, err := sshClient.NewSession()
+		if err != nil {
+			return err
+		}
+		defer session.Close()
+
+		session.Stdout = os.Stdout
+		session.Stderr = os.Stderr
+		return session.Run(agentCommand)
+	})
+}
+
+// runAgentSSHCommand starts an ssh server on the machine of the workspace and runs the given function with
+// an ssh client connected to it, the function is expected to run an agent command in a session
+func runAgentSSHCommand(ctx context.Context, devPodConfig *config.Config, client clientpkg.WorkspaceClient, run func(sshClient *ssh.Client) error) error {
 	log := log.Default
 
 	// create readers
@@ -107,32 +137,13 @@ func (cmd *LogsCmd) Run(ctx context.Context, args []string) error {
 			log.ErrorStreamOnly(), timeout)
 	}()
 
-	// create agent command
-	agentCommand := fmt.Sprintf("'%s' agent workspace logs --context '%s' --id '%s'", client.AgentPath(), client.Context(), client.Workspace())
-	if log.GetLevel() == logrus.DebugLevel {
-		agentCommand += " --debug"
-	}
-
 	// create new ssh client
 	// start ssh client as root / default user
-	sshClient, err := ssh.StdioClientWithUser(stdoutReader, stdinWriter, "" /* default */, false)
+	sshClient, err := devssh.StdioClientWithUser(stdoutReader, stdinWriter, "" /* default */, false)
 	if err != nil {
 		return err
 	}
 	defer sshClient.Close()
 
-	session, err := sshClient.NewSession()
-	if err != nil {
-		return err
-	}
-	defer session.Close()
-
-	session.Stdout = os.Stdout
-	session.Stderr = os.Stderr
-	err = session.Run(agentCommand)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return run(sshClient)
 }
@@ -130,6 +130,45 @@ func StartSSHSession(ctx context.Context, user, command string, agentForwarding
 	return RunSSHSession(ctx, sshClient, agentForwarding, command, stderr)
 }
 
+// RunSSHCommand runs the command without a terminal through the ssh server started by exec and writes its output to stdout and stderr
+func RunSSHCommand(ctx context.Context, user, command string, exec ExecFunc, stdout io.Writer, stderr io.Writer) error {
+	// create readers
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stdoutReader.Close()
+	defer stdoutWriter.Close()
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stdinWriter.Close()
+	defer stdinReader.Close()
+
+	// start ssh machine
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- exec(ctx, stdinReader, stdoutWriter, stderr)
+	}()
+
+	sshClient, err := devssh.StdioClientWithUser(stdoutReader, stdinWriter, user, false)
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	return session.Run(command)
+}
+
 func RunSSHSession(ctx context.Context, sshClient *ssh.Client, agentForwarding bool, command string, stderr io.Writer) error {
 	// create a new session
 	session, err := sshClient.NewSession()
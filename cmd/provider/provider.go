@@ -20,5 +20,6 @@ func NewProviderCmd(flags *flags.GlobalFlags) *cobra.Command {
 	providerCmd.AddCommand(NewAddCmd(flags))
 	providerCmd.AddCommand(NewUpdateCmd(flags))
 	providerCmd.AddCommand(NewSetOptionsCmd(flags))
+	providerCmd.AddCommand(NewTestCmd(flags))
 	return providerCmd
 }
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/conformance"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/spf13/cobra"
+)
+
+// TestCmd holds the test cmd flags
+type TestCmd struct {
+	*flags.GlobalFlags
+
+	Machine       string
+	SkipAgent     bool
+	StatusTimeout time.Duration
+	Output        string
+}
+
+// NewTestCmd creates a new command
+func NewTestCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &TestCmd{
+		GlobalFlags: flags,
+	}
+	testCmd := &cobra.Command{
+		Use:   "test [provider]",
+		Short: "Runs a provider through its lifecycle and checks it behaves as DevPod expects",
+		Long: `Runs a provider through its lifecycle and checks it behaves as DevPod expects.
+
+The provider is initialized, a test machine is created, commands are run on it with
+stdin, stdout, stderr, exit codes and quoting checked, the agent is injected and the
+machine is stopped, started and deleted again. Non-machine providers only run init,
+command and agent checks.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetProviderSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	testCmd.Flags().StringVar(&cmd.Machine, "machine", "", "The name of the test machine, a random name is used if empty")
+	testCmd.Flags().BoolVar(&cmd.SkipAgent, "skip-agent", false, "If true, will not inject the DevPod agent")
+	testCmd.Flags().DurationVar(&cmd.StatusTimeout, "status-timeout", time.Minute*5, "How long to wait for the provider to report a status after an operation")
+	testCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	return testCmd
+}
+
+// Run runs the command logic
+func (cmd *TestCmd) Run(ctx context.Context, args []string) error {
+	if cmd.Output != "plain" && cmd.Output != "json" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	devPodConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	providerName := devPodConfig.Current().DefaultProvider
+	if len(args) > 0 {
+		providerName = args[0]
+	} else if providerName == "" {
+		return fmt.Errorf("please specify a provider")
+	}
+
+	providerWithOptions, err := workspace.FindProvider(devPodConfig, providerName, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return err
+	}
+
+	var logger log.Logger = log.Default
+	if cmd.Output == "json" {
+		logger = log.Default.ErrorStreamOnly()
+	}
+	report, err := conformance.Run(ctx, devPodConfig, providerWithOptions.Config, &conformance.Options{
+		MachineID:     cmd.Machine,
+		SkipAgent:     cmd.SkipAgent,
+		StatusTimeout: cmd.StatusTimeout,
+	}, logger)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == "plain" {
+		tableEntries := [][]string{}
+		for _, result := range report.Results {
+			tableEntries = append(tableEntries, []string{
+				result.Name,
+				result.Result,
+				result.Duration.String(),
+				result.Message,
+			})
+		}
+
+		table.PrintTable(log.Default, []string{
+			"Check",
+			"Result",
+			"Duration",
+			"Message",
+		}, tableEntries)
+	} else {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	}
+
+	if report.Failed() {
+		return fmt.Errorf("provider %s failed the conformance test", providerName)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/docker"
+	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// PruneCmd holds the cmd flags
+type PruneCmd struct {
+	*flags.GlobalFlags
+
+	DryRun      bool
+	SkipExpired bool
+	DockerPath  string
+}
+
+// NewPruneCmd creates a new command
+func NewPruneCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PruneCmd{
+		GlobalFlags: flags,
+	}
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes expired workspaces and orphaned DevPod resources",
+		Long: `Applies the idle policy of the current context (see the IDLE_TTL, IDLE_ACTION and IDLE_WARNING context options),
+which devpod up also applies to all other workspaces, and removes resources that don't correspond to any workspace anymore:
+
+  - DevPod containers, volumes and images on the local docker host
+  - agent workspace folders
+  - DevPod host entries in the ssh config`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	pruneCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If true will only print what would be removed")
+	pruneCmd.Flags().BoolVar(&cmd.SkipExpired, "skip-expired", false, "If true will not stop or delete expired workspaces")
+	pruneCmd.Flags().StringVar(&cmd.DockerPath, "docker-path", "docker", "The docker command to use to find orphaned containers, volumes and images")
+	return pruneCmd
+}
+
+// Run runs the command logic
+func (cmd *PruneCmd) Run(ctx context.Context) error {
+	devPodConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.SkipExpired {
+		err = expireIdleWorkspaces(ctx, devPodConfig, cmd.GlobalFlags, "", cmd.DryRun, log.Default)
+		if err != nil {
+			return err
+		}
+	}
+
+	candidates, err := workspace2.FindPruneCandidates(ctx, devPodConfig, &docker.DockerHelper{DockerCommand: cmd.DockerPath, Log: log.Default}, log.Default)
+	if err != nil {
+		return err
+	} else if len(candidates) == 0 {
+		log.Default.Info("Nothing to prune")
+		return nil
+	}
+
+	failed := 0
+	for _, candidate := range candidates {
+		if cmd.DryRun {
+			log.Default.Infof("Would remove %s %s", candidate.Type, candidate.Name)
+			continue
+		}
+
+		err = candidate.Remove(ctx)
+		if err != nil {
+			log.Default.Errorf("Error removing %s %s: %v", candidate.Type, candidate.Name, err)
+			failed++
+			continue
+		}
+
+		log.Default.Donef("Removed %s %s", candidate.Type, candidate.Name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of %d resources", failed, len(candidates))
+	}
+
+	return nil
+}
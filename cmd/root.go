@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,15 +9,19 @@ import (
 	"github.com/loft-sh/devpod/cmd/agent"
 	"github.com/loft-sh/devpod/cmd/completion"
 	"github.com/loft-sh/devpod/cmd/context"
+	"github.com/loft-sh/devpod/cmd/features"
 	"github.com/loft-sh/devpod/cmd/flags"
 	"github.com/loft-sh/devpod/cmd/helper"
+	"github.com/loft-sh/devpod/cmd/hooks"
 	"github.com/loft-sh/devpod/cmd/ide"
 	"github.com/loft-sh/devpod/cmd/machine"
 	"github.com/loft-sh/devpod/cmd/pro"
 	"github.com/loft-sh/devpod/cmd/provider"
+	"github.com/loft-sh/devpod/cmd/snapshot"
 	"github.com/loft-sh/devpod/cmd/use"
 	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
 	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/driver/custom"
 	"github.com/loft-sh/devpod/pkg/telemetry"
 	log2 "github.com/loft-sh/log"
 	"github.com/loft-sh/log/terminal"
@@ -80,6 +85,8 @@ func Execute() {
 
 	// execute command
 	err := rootCmd.Execute()
+	clientimplementation.ClosePlugins()
+	custom.CloseDrivers()
 	telemetry.CollectorCLI.RecordCLI(err)
 	telemetry.CollectorCLI.Flush()
 	if err != nil {
@@ -93,6 +100,12 @@ func Execute() {
 			os.Exit(execExitErr.ExitCode())
 		}
 
+		var selectorErr *SelectorError
+		if errors.As(err, &selectorErr) {
+			log2.Default.Error(selectorErr)
+			os.Exit(selectorErr.ExitCode)
+		}
+
 		if globalFlags.Debug {
 			log2.Default.Fatalf("%+v", err)
 		} else {
@@ -122,6 +135,9 @@ func BuildRoot() *cobra.Command {
 	rootCmd.AddCommand(ide.NewIDECmd(globalFlags))
 	rootCmd.AddCommand(machine.NewMachineCmd(globalFlags))
 	rootCmd.AddCommand(context.NewContextCmd(globalFlags))
+	rootCmd.AddCommand(snapshot.NewSnapshotCmd(globalFlags))
+	rootCmd.AddCommand(hooks.NewHooksCmd(globalFlags))
+	rootCmd.AddCommand(features.NewFeaturesCmd(globalFlags))
 	rootCmd.AddCommand(pro.NewProCmd(globalFlags, log2.Default))
 	rootCmd.AddCommand(NewUpCmd(globalFlags))
 	rootCmd.AddCommand(NewDeleteCmd(globalFlags))
@@ -135,8 +151,12 @@ func BuildRoot() *cobra.Command {
 	rootCmd.AddCommand(NewExportCmd(globalFlags))
 	rootCmd.AddCommand(NewImportCmd(globalFlags))
 	rootCmd.AddCommand(NewLogsCmd(globalFlags))
+	rootCmd.AddCommand(NewExecCmd(globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(NewTroubleshootCmd(globalFlags))
 	rootCmd.AddCommand(NewPingCmd(globalFlags))
+	rootCmd.AddCommand(NewDiffCmd(globalFlags))
+	rootCmd.AddCommand(NewLabelCmd(globalFlags))
+	rootCmd.AddCommand(NewPruneCmd(globalFlags))
 	return rootCmd
 }
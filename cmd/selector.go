@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/platform"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// SelectorError is returned if an operation failed for at least one of the workspaces matching a selector
+type SelectorError struct {
+	Failed int
+	Total  int
+
+	// ExitCode is the highest exit code of the failed operations
+	ExitCode int
+}
+
+func (e *SelectorError) Error() string {
+	return fmt.Sprintf("%d of %d workspaces failed", e.Failed, e.Total)
+}
+
+// SelectorResult is the result of an operation on a single workspace
+type SelectorResult struct {
+	Workspace *provider2.Workspace
+	Err       error
+}
+
+// runSelected runs the operation concurrently for every workspace that matches the selector and waits for all of them
+func runSelected(
+	ctx context.Context,
+	devPodConfig *config.Config,
+	selector string,
+	owner platform.OwnerFilter,
+	log log.Logger,
+	operation func(ctx context.Context, workspace *provider2.Workspace) error,
+) ([]SelectorResult, error) {
+	workspaces, err := workspace2.ListSelected(ctx, devPodConfig, selector, owner, log)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SelectorResult, len(workspaces))
+	waitGroup := sync.WaitGroup{}
+	for i, workspace := range workspaces {
+		waitGroup.Add(1)
+		go func(i int, workspace *provider2.Workspace) {
+			defer waitGroup.Done()
+
+			results[i] = SelectorResult{
+				Workspace: workspace,
+				Err:       operation(ctx, workspace),
+			}
+		}(i, workspace)
+	}
+	waitGroup.Wait()
+
+	return results, nil
+}
+
+// aggregateSelectorResults logs the results and returns a SelectorError if any operation failed.
+// The success message is formatted with the workspace id and skipped if empty.
+func aggregateSelectorResults(results []SelectorResult, successMessage string, log log.Logger) error {
+	selectorErr := &SelectorError{Total: len(results)}
+	for _, result := range results {
+		if result.Err == nil {
+			if successMessage != "" {
+				log.Donef(successMessage, result.Workspace.ID)
+			}
+			continue
+		}
+
+		log.Errorf("Workspace '%s': %v", result.Workspace.ID, result.Err)
+		selectorErr.Failed++
+
+		exitCode := 1
+		var sshExitErr *ssh.ExitError
+		var execExitErr *exec.ExitError
+		if errors.As(result.Err, &sshExitErr) {
+			exitCode = sshExitErr.ExitStatus()
+		} else if errors.As(result.Err, &execExitErr) {
+			exitCode = execExitErr.ExitCode()
+		}
+		if exitCode > selectorErr.ExitCode {
+			selectorErr.ExitCode = exitCode
+		}
+	}
+	if selectorErr.Failed > 0 {
+		return selectorErr
+	}
+
+	return nil
+}
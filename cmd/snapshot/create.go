@@ -0,0 +1,80 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	client2 "github.com/loft-sh/devpod/pkg/client"
+	"github.com/loft-sh/devpod/pkg/driver"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// CreateCmd holds the configuration
+type CreateCmd struct {
+	*flags.GlobalFlags
+
+	Name string
+}
+
+// NewCreateCmd creates a new command
+func NewCreateCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &CreateCmd{
+		GlobalFlags: flags,
+	}
+	createCmd := &cobra.Command{
+		Use:   "create [flags] [workspace-path|workspace-name]",
+		Short: "Creates a snapshot of a workspace",
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	createCmd.Flags().StringVar(&cmd.Name, "name", "", "The name of the snapshot")
+	_ = createCmd.MarkFlagRequired("name")
+	return createCmd
+}
+
+// Run runs the command logic
+func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
+	err := driver.ValidateSnapshotName(cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	client, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	err = client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Unlock()
+
+	instanceStatus, err := client.Status(ctx, client2.StatusOptions{})
+	if err != nil {
+		return err
+	} else if instanceStatus == client2.StatusNotFound {
+		return fmt.Errorf("cannot snapshot workspace %s, because it doesn't exist", client.Workspace())
+	}
+
+	log.Default.Infof("Creating snapshot %s of workspace %s...", cmd.Name, client.Workspace())
+	err = workspace.ExecuteAgentWorkspaceCommand(ctx, client, provider2.CLIOptions{}, []string{"snapshot", "create", "--name", cmd.Name}, io.Discard, log.Default)
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+
+	log.Default.Donef("Successfully created snapshot %s", cmd.Name)
+	return nil
+}
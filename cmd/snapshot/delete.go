@@ -0,0 +1,71 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	"github.com/loft-sh/devpod/pkg/driver"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/spf13/cobra"
+)
+
+// DeleteCmd holds the configuration
+type DeleteCmd struct {
+	*flags.GlobalFlags
+
+	Name string
+}
+
+// NewDeleteCmd creates a new command
+func NewDeleteCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DeleteCmd{
+		GlobalFlags: flags,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete [flags] [workspace-path|workspace-name]",
+		Short: "Deletes a snapshot on the machine of a workspace",
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	deleteCmd.Flags().StringVar(&cmd.Name, "name", "", "The name of the snapshot")
+	_ = deleteCmd.MarkFlagRequired("name")
+	return deleteCmd
+}
+
+// Run runs the command logic
+func (cmd *DeleteCmd) Run(ctx context.Context, args []string) error {
+	err := driver.ValidateSnapshotName(cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	client, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	err = client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Unlock()
+
+	err = workspace.ExecuteAgentWorkspaceCommand(ctx, client, provider2.CLIOptions{}, []string{"snapshot", "delete", "--name", cmd.Name}, io.Discard, log.Default)
+	if err != nil {
+		return fmt.Errorf("delete snapshot: %w", err)
+	}
+
+	log.Default.Donef("Successfully deleted snapshot %s", cmd.Name)
+	return nil
+}
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/loft-sh/devpod/cmd/completion"
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/cmd/workspaceclient"
+	"github.com/loft-sh/devpod/pkg/driver"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ListCmd holds the configuration
+type ListCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+}
+
+// NewListCmd creates a new command
+func NewListCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: flags,
+	}
+	listCmd := &cobra.Command{
+		Use:     "list [flags] [workspace-path|workspace-name]",
+		Aliases: []string{"ls"},
+		Short:   "Lists the snapshots available on the machine of a workspace",
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	listCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	return listCmd
+}
+
+// Run runs the command logic
+func (cmd *ListCmd) Run(ctx context.Context, args []string) error {
+	if cmd.Output != "plain" && cmd.Output != "json" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	client, err := workspaceclient.Get(ctx, cmd.GlobalFlags, args)
+	if err != nil {
+		return err
+	}
+
+	stdout := &bytes.Buffer{}
+	err = workspace.ExecuteAgentWorkspaceCommand(ctx, client, provider2.CLIOptions{}, []string{"snapshot", "list"}, stdout, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return fmt.Errorf("list snapshots: %w", err)
+	}
+
+	snapshots := []*driver.Snapshot{}
+	if stdout.Len() > 0 {
+		err = json.Unmarshal(stdout.Bytes(), &snapshots)
+		if err != nil {
+			return errors.Wrap(err, "parse snapshots")
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
+	if cmd.Output == "json" {
+		out, err := json.Marshal(snapshots)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+
+	tableEntries := [][]string{}
+	for _, snapshot := range snapshots {
+		tableEntries = append(tableEntries, []string{
+			snapshot.Name,
+			snapshot.WorkspaceID,
+			snapshot.Image,
+			time.Since(snapshot.CreationTimestamp.Time).Round(1 * time.Second).String(),
+		})
+	}
+	table.PrintTable(log.Default, []string{
+		"Name",
+		"Workspace",
+		"Image",
+		"Age",
+	}, tableEntries)
+	return nil
+}
@@ -0,0 +1,21 @@
+package snapshot
+
+import (
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewSnapshotCmd returns a new root command
+func NewSnapshotCmd(flags *flags.GlobalFlags) *cobra.Command {
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "DevPod Snapshot commands",
+		Long: `Snapshots capture the state of a workspace container and its volumes.
+Use 'devpod up --from-snapshot NAME' to start a workspace from a snapshot.`,
+	}
+
+	snapshotCmd.AddCommand(NewCreateCmd(flags))
+	snapshotCmd.AddCommand(NewListCmd(flags))
+	snapshotCmd.AddCommand(NewDeleteCmd(flags))
+	return snapshotCmd
+}
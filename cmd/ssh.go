@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/loft-sh/devpod/cmd/completion"
@@ -57,9 +59,10 @@ type SSHCmd struct {
 
 	StartServices bool
 
-	Command string
-	User    string
-	WorkDir string
+	Command  string
+	User     string
+	WorkDir  string
+	Selector string
 }
 
 // NewSSHCmd creates a new ssh command
@@ -82,6 +85,10 @@ func NewSSHCmd(f *flags.GlobalFlags) *cobra.Command {
 			}
 
 			ctx := cobraCmd.Context()
+			if cmd.Selector != "" {
+				return cmd.RunSelected(ctx, devPodConfig, args, log.Default.ErrorStreamOnly())
+			}
+
 			client, err := workspace2.Get(ctx, devPodConfig, args, true, cmd.Owner, localOnly, log.Default.ErrorStreamOnly())
 			if err != nil {
 				return err
@@ -109,6 +116,7 @@ func NewSSHCmd(f *flags.GlobalFlags) *cobra.Command {
 	sshCmd.Flags().BoolVar(&cmd.Stdio, "stdio", false, "If true will tunnel connection through stdout and stdin")
 	sshCmd.Flags().BoolVar(&cmd.StartServices, "start-services", true, "If false will not start any port-forwarding or git / docker credentials helper")
 	sshCmd.Flags().DurationVar(&cmd.SSHKeepAliveInterval, "ssh-keepalive-interval", 55*time.Second, "How often should keepalive request be made (55s)")
+	sshCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Run the command in all workspaces whose labels match the selector, e.g. team=a,env!=prod. Requires --command")
 
 	return sshCmd
 }
@@ -158,6 +166,91 @@ func (cmd *SSHCmd) Run(
 	return nil
 }
 
+// RunSelected runs the command concurrently in all workspaces matching the selector.
+// The output of each workspace is printed once its command has finished.
+func (cmd *SSHCmd) RunSelected(ctx context.Context, devPodConfig *config.Config, args []string, log log.Logger) error {
+	if len(args) > 0 {
+		return fmt.Errorf("cannot specify a workspace together with --selector")
+	} else if cmd.Command == "" {
+		return fmt.Errorf("--selector requires --command")
+	} else if cmd.Stdio || len(cmd.ForwardPorts) > 0 || len(cmd.ReverseForwardPorts) > 0 {
+		return fmt.Errorf("--selector cannot be used with --stdio, --forward-ports or --reverse-forward-ports")
+	}
+
+	// set default context if needed
+	if cmd.Context == "" {
+		cmd.Context = devPodConfig.DefaultContext
+	}
+
+	outputMutex := sync.Mutex{}
+	results, err := runSelected(ctx, devPodConfig, cmd.Selector, cmd.Owner, log, func(ctx context.Context, workspace *provider.Workspace) error {
+		stdout := &bytes.Buffer{}
+		err := cmd.runCommand(ctx, devPodConfig, workspace, stdout, log)
+
+		outputMutex.Lock()
+		defer outputMutex.Unlock()
+		fmt.Printf("==> %s <==\n%s", workspace.ID, stdout.String())
+		if stdout.Len() > 0 && !bytes.HasSuffix(stdout.Bytes(), []byte("\n")) {
+			fmt.Println()
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return aggregateSelectorResults(results, "", log)
+}
+
+func (cmd *SSHCmd) runCommand(ctx context.Context, devPodConfig *config.Config, workspace *provider.Workspace, stdout io.Writer, log log.Logger) error {
+	client, err := workspace2.Get(ctx, devPodConfig, []string{workspace.ID}, true, cmd.Owner, false, log)
+	if err != nil {
+		return err
+	}
+	workspaceClient, ok := client.(client2.WorkspaceClient)
+	if !ok {
+		return fmt.Errorf("--selector is not supported for pro workspaces")
+	}
+
+	user := cmd.User
+	if user == "" {
+		user, err = devssh.GetUser(workspace.ID, workspace.SSHConfigPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// lock the workspace as long as we init the connection
+	err = workspaceClient.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer workspaceClient.Unlock()
+
+	err = startWait(ctx, workspaceClient, false, log)
+	if err != nil {
+		return err
+	}
+
+	envVars, err := cmd.retrieveEnVars()
+	if err != nil {
+		return err
+	}
+
+	return tunnel.NewContainerTunnel(workspaceClient, log).
+		Run(ctx, func(ctx context.Context, containerClient *ssh.Client) error {
+			workspaceClient.Unlock()
+
+			writer := log.Writer(logrus.InfoLevel, false)
+			defer writer.Close()
+
+			command := cmd.sshServerCommand(workspace.ID, user, log)
+			return machine.RunSSHCommand(ctx, user, cmd.Command, func(ctx context.Context, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+				return devssh.Run(ctx, containerClient, command, stdin, stdout, stderr, envVars)
+			}, stdout, writer)
+		}, devPodConfig, envVars)
+}
+
 func (cmd *SSHCmd) jumpContainerTailscale(
 	ctx context.Context,
 	devPodConfig *config.Config,
@@ -495,23 +588,8 @@ func (cmd *SSHCmd) startTunnel(ctx context.Context, devPodConfig *config.Config,
 		}
 	}
 
-	workdir := filepath.Join("/workspaces", workspaceClient.Workspace())
-	if cmd.WorkDir != "" {
-		workdir = cmd.WorkDir
-	}
-
 	log.Debugf("Run outer container tunnel")
-	command := fmt.Sprintf("'%s' helper ssh-server --track-activity --stdio --workdir '%s'", agent.ContainerDevPodHelperLocation, workdir)
-	if cmd.ReuseSSHAuthSock != "" {
-		log.Debug("Reusing SSH_AUTH_SOCK")
-		command += fmt.Sprintf(" --reuse-ssh-auth-sock=%s", cmd.ReuseSSHAuthSock)
-	}
-	if cmd.Debug {
-		command += " --debug"
-	}
-	if cmd.User != "" && cmd.User != "root" {
-		command = fmt.Sprintf("su -c \"%s\" '%s'", command, cmd.User)
-	}
+	command := cmd.sshServerCommand(workspaceClient.Workspace(), cmd.User, log)
 
 	envVars, err := cmd.retrieveEnVars()
 	if err != nil {
@@ -538,6 +616,28 @@ func (cmd *SSHCmd) startTunnel(ctx context.Context, devPodConfig *config.Config,
 	)
 }
 
+// sshServerCommand returns the command that starts the ssh server within the container
+func (cmd *SSHCmd) sshServerCommand(workspace, user string, log log.Logger) string {
+	workdir := filepath.Join("/workspaces", workspace)
+	if cmd.WorkDir != "" {
+		workdir = cmd.WorkDir
+	}
+
+	command := fmt.Sprintf("'%s' helper ssh-server --track-activity --stdio --workdir '%s'", agent.ContainerDevPodHelperLocation, workdir)
+	if cmd.ReuseSSHAuthSock != "" {
+		log.Debug("Reusing SSH_AUTH_SOCK")
+		command += fmt.Sprintf(" --reuse-ssh-auth-sock=%s", cmd.ReuseSSHAuthSock)
+	}
+	if cmd.Debug {
+		command += " --debug"
+	}
+	if user != "" && user != "root" {
+		command = fmt.Sprintf("su -c \"%s\" '%s'", command, user)
+	}
+
+	return command
+}
+
 func (cmd *SSHCmd) startServices(
 	ctx context.Context,
 	devPodConfig *config.Config,
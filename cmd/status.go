@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/loft-sh/devpod/cmd/completion"
@@ -11,8 +14,11 @@ import (
 	client2 "github.com/loft-sh/devpod/pkg/client"
 	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
 	"github.com/loft-sh/devpod/pkg/config"
+	config2 "github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
 	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -22,8 +28,9 @@ type StatusCmd struct {
 	*flags.GlobalFlags
 	client2.StatusOptions
 
-	Output  string
-	Timeout string
+	Output   string
+	Timeout  string
+	Selector string
 }
 
 // NewStatusCmd creates a new command
@@ -47,6 +54,10 @@ func NewStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
 			}
 
 			logger := log.Default.ErrorStreamOnly()
+			if cmd.Selector != "" {
+				return cmd.RunSelected(ctx, devPodConfig, args, logger)
+			}
+
 			client, err := workspace2.Get(ctx, devPodConfig, args, false, cmd.Owner, false, logger)
 			if err != nil {
 				return err
@@ -62,16 +73,86 @@ func NewStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
 	statusCmd.Flags().BoolVar(&cmd.ContainerStatus, "container-status", true, "If enabled shows the workspace container status as well")
 	statusCmd.Flags().StringVar(&cmd.Output, "output", "plain", "Status shows the workspace status")
 	statusCmd.Flags().StringVar(&cmd.Timeout, "timeout", "30s", "The timeout to wait until the status can be retrieved")
+	statusCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Show the status of all workspaces whose labels match the selector, e.g. team=a,env!=prod")
 	return statusCmd
 }
 
-// Run runs the command logic
-func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClient, log log.Logger) error {
+// RunSelected shows the status of all workspaces matching the selector
+func (cmd *StatusCmd) RunSelected(ctx context.Context, devPodConfig *config.Config, args []string, log log.Logger) error {
+	if len(args) > 0 {
+		return fmt.Errorf("cannot specify a workspace together with --selector")
+	} else if cmd.Output != "plain" && cmd.Output != "json" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	statuses := map[string]*client2.WorkspaceStatus{}
+	statusesMutex := sync.Mutex{}
+	results, err := runSelected(ctx, devPodConfig, cmd.Selector, cmd.Owner, log, func(ctx context.Context, workspace *provider2.Workspace) error {
+		client, err := workspace2.Get(ctx, devPodConfig, []string{workspace.ID}, false, cmd.Owner, false, log)
+		if err != nil {
+			return err
+		}
+
+		instanceStatus, err := cmd.status(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		statusesMutex.Lock()
+		defer statusesMutex.Unlock()
+		statuses[workspace.ID] = &client2.WorkspaceStatus{
+			ID:       client.Workspace(),
+			Context:  client.Context(),
+			Provider: client.Provider(),
+			State:    string(instanceStatus),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// keep the order of the workspaces
+	retStatuses := []*client2.WorkspaceStatus{}
+	for _, result := range results {
+		if statuses[result.Workspace.ID] != nil {
+			retStatuses = append(retStatuses, statuses[result.Workspace.ID])
+		}
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.Marshal(retStatuses)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(out))
+	} else {
+		tableEntries := [][]string{}
+		for _, status := range retStatuses {
+			tableEntries = append(tableEntries, []string{
+				status.ID,
+				status.Provider,
+				status.State,
+			})
+		}
+
+		table.PrintTable(log, []string{
+			"Name",
+			"Provider",
+			"Status",
+		}, tableEntries)
+	}
+
+	return aggregateSelectorResults(results, "", log)
+}
+
+func (cmd *StatusCmd) status(ctx context.Context, client client2.BaseWorkspaceClient) (client2.Status, error) {
 	// parse timeout
 	if cmd.Timeout != "" {
 		duration, err := time.ParseDuration(cmd.Timeout)
 		if err != nil {
-			return errors.Wrap(err, "parse --timeout")
+			return "", errors.Wrap(err, "parse --timeout")
 		}
 
 		var cancel context.CancelFunc
@@ -79,12 +160,26 @@ func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClien
 		defer cancel()
 	}
 
+	return client.Status(ctx, cmd.StatusOptions)
+}
+
+// Run runs the command logic
+func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClient, log log.Logger) error {
 	// get instance status
-	instanceStatus, err := client.Status(ctx, cmd.StatusOptions)
+	instanceStatus, err := cmd.status(ctx, client)
 	if err != nil {
 		return err
 	}
 
+	// get the status of the lifecycle hooks running in the background
+	var lifecycleHooks *config2.LifecycleHooksStatus
+	if instanceStatus == client2.StatusRunning && cmd.ContainerStatus {
+		lifecycleHooks, err = cmd.lifecycleHooksStatus(ctx, client)
+		if err != nil {
+			log.Debugf("Error retrieving lifecycle hooks status: %v", err)
+		}
+	}
+
 	if cmd.Output == "plain" {
 		if instanceStatus == client2.StatusStopped {
 			log.Infof("Workspace '%s' is '%s', you can start it via 'devpod up %s'", client.Workspace(), instanceStatus, client.Workspace())
@@ -95,12 +190,14 @@ func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClien
 		} else {
 			log.Infof("Workspace '%s' is '%s'", client.Workspace(), instanceStatus)
 		}
+		printLifecycleHooksStatus(lifecycleHooks, log)
 	} else if cmd.Output == "json" {
 		out, err := json.Marshal(&client2.WorkspaceStatus{
-			ID:       client.Workspace(),
-			Context:  client.Context(),
-			Provider: client.Provider(),
-			State:    string(instanceStatus),
+			ID:             client.Workspace(),
+			Context:        client.Context(),
+			Provider:       client.Provider(),
+			State:          string(instanceStatus),
+			LifecycleHooks: lifecycleHooks,
 		})
 		if err != nil {
 			return err
@@ -113,3 +210,42 @@ func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClien
 
 	return nil
 }
+
+// lifecycleHooksStatus returns the status of the lifecycle hooks that run in the background after waitFor
+func (cmd *StatusCmd) lifecycleHooksStatus(ctx context.Context, client client2.BaseWorkspaceClient) (*config2.LifecycleHooksStatus, error) {
+	workspaceClient, ok := client.(client2.WorkspaceClient)
+	if !ok {
+		return nil, nil
+	}
+
+	stdout := &bytes.Buffer{}
+	err := workspace2.ExecuteAgentWorkspaceCommand(ctx, workspaceClient, provider2.CLIOptions{}, []string{"hooks", "status"}, stdout, log.Discard)
+	if err != nil {
+		return nil, err
+	} else if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, nil
+	}
+
+	status := &config2.LifecycleHooksStatus{}
+	err = json.Unmarshal(stdout.Bytes(), status)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse lifecycle hooks status")
+	}
+
+	return status, nil
+}
+
+func printLifecycleHooksStatus(status *config2.LifecycleHooksStatus, log log.Logger) {
+	if status == nil {
+		return
+	}
+
+	switch status.State {
+	case config2.LifecycleHooksStateRunning:
+		log.Infof("Lifecycle hooks are running in the background, currently running %s (started at %s)", status.Hook, status.StartedAt)
+	case config2.LifecycleHooksStateFailed:
+		log.Warnf("Lifecycle hook %s failed in the background: %s, run 'devpod logs' for details", status.Hook, status.Error)
+	case config2.LifecycleHooksStateDone:
+		log.Infof("Lifecycle hooks %s finished in the background at %s", strings.Join(status.Hooks, ", "), status.FinishedAt)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	client2 "github.com/loft-sh/devpod/pkg/client"
 	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
 	"github.com/loft-sh/devpod/pkg/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
 	workspace2 "github.com/loft-sh/devpod/pkg/workspace"
 	"github.com/loft-sh/log"
 	"github.com/pkg/errors"
@@ -19,6 +20,8 @@ import (
 type StopCmd struct {
 	*flags.GlobalFlags
 	client2.StopOptions
+
+	Selector string
 }
 
 // NewStopCmd creates a new destroy command
@@ -42,6 +45,10 @@ func NewStopCmd(flags *flags.GlobalFlags) *cobra.Command {
 				return fmt.Errorf("decode platform options: %w", err)
 			}
 
+			if cmd.Selector != "" {
+				return cmd.RunSelected(ctx, devPodConfig, args)
+			}
+
 			client, err := workspace2.Get(ctx, devPodConfig, args, false, cmd.Owner, false, log.Default)
 			if err != nil {
 				return err
@@ -54,9 +61,31 @@ func NewStopCmd(flags *flags.GlobalFlags) *cobra.Command {
 		},
 	}
 
+	stopCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "Stop all workspaces whose labels match the selector, e.g. team=a,env!=prod")
 	return stopCmd
 }
 
+// RunSelected stops all workspaces matching the selector concurrently
+func (cmd *StopCmd) RunSelected(ctx context.Context, devPodConfig *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("cannot specify a workspace together with --selector")
+	}
+
+	results, err := runSelected(ctx, devPodConfig, cmd.Selector, cmd.Owner, log.Default, func(ctx context.Context, workspace *provider2.Workspace) error {
+		client, err := workspace2.Get(ctx, devPodConfig, []string{workspace.ID}, false, cmd.Owner, false, log.Default)
+		if err != nil {
+			return err
+		}
+
+		return cmd.Run(ctx, devPodConfig, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return aggregateSelectorResults(results, "Successfully stopped workspace '%s'", log.Default)
+}
+
 // Run runs the command logic
 func (cmd *StopCmd) Run(ctx context.Context, devPodConfig *config.Config, client client2.BaseWorkspaceClient) error {
 	// lock workspace
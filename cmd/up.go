@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -16,6 +17,7 @@ import (
 	"syscall"
 
 	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
 	"github.com/loft-sh/devpod/cmd/flags"
 	"github.com/loft-sh/devpod/pkg/agent"
 	"github.com/loft-sh/devpod/pkg/agent/tunnelserver"
@@ -23,6 +25,7 @@ import (
 	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
 	"github.com/loft-sh/devpod/pkg/command"
 	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer"
 	config2 "github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/devcontainer/sshtunnel"
 	"github.com/loft-sh/devpod/pkg/ide"
@@ -34,6 +37,7 @@ import (
 	"github.com/loft-sh/devpod/pkg/ide/vscode"
 	"github.com/loft-sh/devpod/pkg/ide/zed"
 	open2 "github.com/loft-sh/devpod/pkg/open"
+	"github.com/loft-sh/devpod/pkg/options"
 	"github.com/loft-sh/devpod/pkg/platform"
 	"github.com/loft-sh/devpod/pkg/port"
 	provider2 "github.com/loft-sh/devpod/pkg/provider"
@@ -59,11 +63,15 @@ type UpCmd struct {
 	Machine string
 
 	ProviderOptions []string
+	Labels          []string
 
 	ConfigureSSH       bool
 	GPGAgentForwarding bool
 	OpenIDE            bool
 	Reconfigure        bool
+	DryRun             bool
+
+	Output string
 
 	SSHConfigPath string
 
@@ -94,12 +102,22 @@ func NewUpCmd(f *flags.GlobalFlags) *cobra.Command {
 			ctx, cancel := WithSignals(cobraCmd.Context())
 			defer cancel()
 
+			if cmd.DryRun {
+				return cmd.dryRun(ctx, devPodConfig, args)
+			}
+
 			client, logger, err := cmd.prepareClient(ctx, devPodConfig, args)
 			if err != nil {
 				return fmt.Errorf("prepare workspace client: %w", err)
 			}
 			telemetry.CollectorCLI.SetClient(client)
 
+			// stop or delete other workspaces that weren't used for too long
+			err = expireIdleWorkspaces(ctx, devPodConfig, cmd.GlobalFlags, client.Workspace(), false, logger)
+			if err != nil {
+				logger.Warnf("Error expiring idle workspaces: %v", err)
+			}
+
 			return cmd.Run(ctx, devPodConfig, client, args, logger)
 		},
 	}
@@ -114,9 +132,13 @@ func NewUpCmd(f *flags.GlobalFlags) *cobra.Command {
 	upCmd.Flags().StringVar(&cmd.DevContainerImage, "devcontainer-image", "", "The container image to use, this will override the devcontainer.json value in the project")
 	upCmd.Flags().StringVar(&cmd.DevContainerPath, "devcontainer-path", "", "The path to the devcontainer.json relative to the project")
 	upCmd.Flags().StringArrayVar(&cmd.ProviderOptions, "provider-option", []string{}, "Provider option in the form KEY=VALUE")
+	upCmd.Flags().StringArrayVar(&cmd.Labels, "label", []string{}, "Label to add to the workspace in the form key=value")
 	upCmd.Flags().BoolVar(&cmd.Reconfigure, "reconfigure", false, "Reconfigure the options for this workspace. Only supported in DevPod Pro right now.")
 	upCmd.Flags().BoolVar(&cmd.Recreate, "recreate", false, "If true will remove any existing containers and recreate them")
 	upCmd.Flags().BoolVar(&cmd.Reset, "reset", false, "If true will remove any existing containers including sources, and recreate them")
+	upCmd.Flags().StringVar(&cmd.FromSnapshot, "from-snapshot", "", "The snapshot to start the workspace from. Requires --recreate if the workspace already has a container")
+	upCmd.Flags().StringVar(&cmd.PvcRetentionPolicy, "pvc-retention-policy", "", "Kubernetes only. What happens to the workspace volume on delete, one of Delete, Retain or Snapshot. Overrides the PVC_RETENTION_POLICY provider option")
+	upCmd.Flags().StringVar(&cmd.GoldenSnapshot, "golden-snapshot", "", "Kubernetes only. The snapshot a new workspace volume is provisioned from. Overrides the GOLDEN_SNAPSHOT provider option")
 	upCmd.Flags().StringSliceVar(&cmd.PrebuildRepositories, "prebuild-repository", []string{}, "Docker repository that hosts devpod prebuilds for this workspace")
 	upCmd.Flags().StringArrayVar(&cmd.WorkspaceEnv, "workspace-env", []string{}, "Extra env variables to put into the workspace. E.g. MY_ENV_VAR=MY_VALUE")
 	upCmd.Flags().StringSliceVar(&cmd.WorkspaceEnvFile, "workspace-env-file", []string{}, "The path to files containing a list of extra env variables to put into the workspace. E.g. MY_ENV_VAR=MY_VALUE")
@@ -131,6 +153,8 @@ func NewUpCmd(f *flags.GlobalFlags) *cobra.Command {
 	upCmd.Flags().StringVar(&cmd.FallbackImage, "fallback-image", "", "The fallback image to use if no devcontainer configuration has been detected")
 	upCmd.Flags().BoolVar(&cmd.DisableDaemon, "disable-daemon", false, "If enabled, will not install a daemon into the target machine to track activity")
 	upCmd.Flags().StringVar(&cmd.Source, "source", "", "Optional source for the workspace. E.g. git:https://github.com/my-org/my-repo")
+	upCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If true will only print the resolved execution plan without creating a machine, building an image or starting a container")
+	upCmd.Flags().StringVar(&cmd.Output, "output", "json", "The output format of the execution plan when using --dry-run. Can be json or yaml")
 
 	// testing
 	upCmd.Flags().StringVar(&cmd.DaemonInterval, "daemon-interval", "", "TESTING ONLY")
@@ -1400,6 +1424,11 @@ func (cmd *UpCmd) prepareClient(ctx context.Context, devPodConfig *config.Config
 		cmd.SSHConfigPath = devPodConfig.ContextOption(config.ContextOptionSSHConfigPath)
 	}
 
+	labels, _, err := workspace2.ParseLabels(cmd.Labels, false)
+	if err != nil {
+		return nil, logger, err
+	}
+
 	client, err := workspace2.Resolve(
 		ctx,
 		devPodConfig,
@@ -1423,6 +1452,13 @@ func (cmd *UpCmd) prepareClient(ctx context.Context, devPodConfig *config.Config
 		return nil, logger, err
 	}
 
+	if len(labels) > 0 {
+		_, err = workspace2.UpdateLabels(devPodConfig, client.Workspace(), labels, nil)
+		if err != nil {
+			return nil, logger, fmt.Errorf("update labels: %w", err)
+		}
+	}
+
 	if !cmd.Platform.Enabled {
 		proInstance := getProInstance(devPodConfig, client.Provider(), logger)
 		err = checkProviderUpdate(devPodConfig, proInstance, logger)
@@ -1434,6 +1470,110 @@ func (cmd *UpCmd) prepareClient(ctx context.Context, devPodConfig *config.Config
 	return client, logger, nil
 }
 
+// dryRun prints the execution plan of devpod up without creating or changing anything
+func (cmd *UpCmd) dryRun(ctx context.Context, devPodConfig *config.Config, args []string) error {
+	if cmd.Output != "json" && cmd.Output != "yaml" {
+		return fmt.Errorf("unexpected output format, choose either json or yaml. Got %s", cmd.Output)
+	} else if cmd.Platform.Enabled {
+		return fmt.Errorf("dry run is not supported in platform mode")
+	}
+
+	// try to parse flags from env
+	if err := mergeDevPodUpOptions(&cmd.CLIOptions); err != nil {
+		return err
+	}
+	if err := mergeEnvFromFiles(&cmd.CLIOptions); err != nil {
+		return err
+	}
+
+	var source *provider2.WorkspaceSource
+	if cmd.Source != "" {
+		source = provider2.ParseWorkspaceSource(cmd.Source)
+		if source == nil {
+			return fmt.Errorf("workspace source is missing")
+		}
+	}
+
+	// the plan is the only output on stdout
+	logger := log.Default.ErrorStreamOnly()
+	planned, err := workspace2.ResolvePlan(
+		ctx,
+		devPodConfig,
+		args,
+		cmd.ID,
+		cmd.Machine,
+		cmd.ProviderOptions,
+		cmd.DevContainerImage,
+		cmd.DevContainerPath,
+		source,
+		cmd.UID,
+		cmd.Owner,
+		logger,
+	)
+	if err != nil {
+		return err
+	}
+
+	contentFolder, cleanup, err := workspace2.PrepareContentFolder(ctx, planned.Workspace, cmd.StrictHostKeyChecking, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cliOptions := cmd.CLIOptions
+	cliOptions.DevContainerImage = planned.Workspace.DevContainerImage
+	cliOptions.DevContainerPath = planned.Workspace.DevContainerPath
+	agentConfig := options.ResolveAgentConfig(devPodConfig, planned.Provider, planned.Workspace, planned.Machine)
+	plan, err := devcontainer.Plan(ctx, &provider2.AgentWorkspaceInfo{
+		Workspace:     planned.Workspace,
+		Machine:       planned.Machine,
+		Agent:         agentConfig,
+		CLIOptions:    cliOptions,
+		Options:       planned.Options,
+		ContentFolder: contentFolder,
+	}, cliOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	plan.Workspace = planned.Workspace.ID
+	plan.Source = planned.Workspace.Source.String()
+	plan.Exists = planned.Exists
+	plan.Provider = planned.Provider.Name
+	plan.Driver = agentConfig.Driver
+	if plan.Driver == "" {
+		plan.Driver = provider2.DockerDriver
+	}
+	if planned.Machine != nil {
+		plan.Machine = &config2.PlanMachine{
+			ID:     planned.Machine.ID,
+			Create: planned.CreateMachine,
+		}
+	}
+	plan.ProviderOptions = map[string]string{}
+	for name, value := range planned.Options {
+		if planned.Provider.Options[name] != nil && planned.Provider.Options[name].Password {
+			plan.ProviderOptions[name] = "********"
+			continue
+		}
+
+		plan.ProviderOptions[name] = value.Value
+	}
+
+	var out []byte
+	if cmd.Output == "yaml" {
+		out, err = yaml.Marshal(plan)
+	} else {
+		out, err = json.MarshalIndent(plan, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
 func WithSignals(ctx context.Context) (context.Context, func()) {
 	ctx, cancel := context.WithCancel(ctx)
 	signals := make(chan os.Signal, 1)
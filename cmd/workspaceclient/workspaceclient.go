@@ -0,0 +1,33 @@
+package workspaceclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/devpod/cmd/flags"
+	"github.com/loft-sh/devpod/pkg/client"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/workspace"
+	"github.com/loft-sh/log"
+)
+
+// Get returns the client of an existing workspace for commands that run the agent in the workspace, which
+// proxy providers don't support
+func Get(ctx context.Context, globalFlags *flags.GlobalFlags, args []string) (client.WorkspaceClient, error) {
+	devPodConfig, err := config.LoadConfig(globalFlags.Context, globalFlags.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	baseClient, err := workspace.Get(ctx, devPodConfig, args, false, globalFlags.Owner, false, log.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceClient, ok := baseClient.(client.WorkspaceClient)
+	if !ok {
+		return nil, fmt.Errorf("this command is not supported for proxy providers")
+	}
+
+	return workspaceClient, nil
+}
@@ -271,6 +271,13 @@ func rerunAsRoot(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (b
 			log.Debugf("Error trying to reach docker daemon: %v", err)
 			dockerRootRequired = true
 		}
+	} else if workspaceInfo != nil && workspaceInfo.Agent.Driver == provider2.NerdctlDriver {
+		var err error
+		dockerRootRequired, err = dockerReachable(workspaceInfo.Agent.Nerdctl.Command(), workspaceInfo.Agent.Nerdctl.Environment())
+		if err != nil {
+			log.Debugf("Error trying to reach containerd: %v", err)
+			dockerRootRequired = true
+		}
 	}
 
 	// check if daemon needs to be installed
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ContainerSessionsDir holds a file per open SSH or IDE session within the container
+const ContainerSessionsDir = "/tmp/devpod.sessions"
+
+// TrackSession registers the current process as an open session until the returned function is called
+func TrackSession() (func(), error) {
+	err := os.MkdirAll(ContainerSessionsDir, 0o777)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Chmod(ContainerSessionsDir, 0o777)
+
+	sessionFile := filepath.Join(ContainerSessionsDir, strconv.Itoa(os.Getpid()))
+	err = os.WriteFile(sessionFile, nil, 0o666)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = os.Remove(sessionFile)
+	}, nil
+}
+
+// CountSessions returns the number of open sessions within the container. Sessions of processes that
+// were killed before they could unregister are removed.
+func CountSessions() (int, error) {
+	entries, err := os.ReadDir(ContainerSessionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	sessions := 0
+	for _, entry := range entries {
+		_, err := os.Stat(filepath.Join("/proc", entry.Name()))
+		if err != nil {
+			_ = os.Remove(filepath.Join(ContainerSessionsDir, entry.Name()))
+			continue
+		}
+
+		sessions++
+	}
+
+	return sessions, nil
+}
@@ -222,6 +222,15 @@ func GetAgentWorkspaceDir(agentFolder, context, workspaceID string) (string, err
 	return "", os.ErrNotExist
 }
 
+func GetAgentSnapshotsDir(agentFolder string) (string, error) {
+	homeFolder, err := FindAgentHomeFolder(agentFolder)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeFolder, "snapshots"), nil
+}
+
 func CreateAgentWorkspaceDir(agentFolder, context, workspaceID string) (string, error) {
 	homeFolder, err := PrepareAgentHomeFolder(agentFolder)
 	if err != nil {
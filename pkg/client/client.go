@@ -211,6 +211,9 @@ type WorkspaceStatus struct {
 	Context  string `json:"context,omitempty"`
 	Provider string `json:"provider,omitempty"`
 	State    string `json:"state,omitempty"`
+
+	// LifecycleHooks is the progress of the lifecycle hooks that run in the background
+	LifecycleHooks *config.LifecycleHooksStatus `json:"lifecycleHooks,omitempty"`
 }
 
 type User struct {
@@ -0,0 +1,231 @@
+package clientimplementation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/loft-sh/devpod/pkg/binaries"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/jsonrpc"
+	"github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"github.com/sirupsen/logrus"
+)
+
+// PluginProtocolVersion is the version of the provider plugin protocol. A plugin is a long-lived provider process
+// that DevPod starts once per CLI or daemon session and that speaks JSON-RPC 2.0 over stdio, see the jsonrpc package.
+// It can keep state such as authentication across operations.
+//
+// DevPod first sends an initialize request and the plugin answers with the commands it implements. Each command is
+// then sent as request with the environment variables DevPod would set for the exec command, e.g. MACHINE_ID or
+// COMMAND. Input and output of the command are sent as stream notifications. Commands the plugin doesn't implement
+// are run through the exec commands of the provider. The plugin should exit once its stdin is closed.
+const PluginProtocolVersion = 1
+
+const PluginMethodInitialize = "initialize"
+
+// PluginCommands are the commands a plugin can implement, they are the method names of the requests
+var PluginCommands = []string{"create", "delete", "start", "stop", "status", "command"}
+
+type PluginInitializeParams struct {
+	// ProtocolVersion is the highest protocol version DevPod supports
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+type PluginInitializeResult struct {
+	// ProtocolVersion is the protocol version the plugin speaks
+	ProtocolVersion int `json:"protocolVersion"`
+
+	// Commands are the commands the plugin implements
+	Commands []string `json:"commands,omitempty"`
+}
+
+// PluginCommandParams are the params of the create, delete, start, stop, status and command methods
+type PluginCommandParams struct {
+	// Env are the environment variables of the exec command
+	Env map[string]string `json:"env"`
+
+	// Stream identifies the stream notifications of this command
+	Stream string `json:"stream"`
+}
+
+type PluginCommandResult struct {
+	ExitCode int `json:"exitCode"`
+}
+
+type plugin struct {
+	once sync.Once
+
+	client   *jsonrpc.Client
+	commands []string
+	err      error
+}
+
+var (
+	pluginsMutex sync.Mutex
+	plugins      = map[string]*plugin{}
+)
+
+// ClosePlugins stops all provider plugins that were started by this process
+func ClosePlugins() {
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+
+	for key, p := range plugins {
+		if p.client != nil {
+			_ = p.client.Close()
+		}
+		delete(plugins, key)
+	}
+}
+
+// getPlugin returns the plugin of the provider and starts it on first use. If it can't be started the
+// error is returned for the rest of the session. A plugin that exited is dropped, so it is started again
+// on the next use.
+func getPlugin(ctx context.Context, devPodContext string, options map[string]config.OptionValue, config *provider.ProviderConfig, log log.Logger) (*plugin, error) {
+	pluginsMutex.Lock()
+	key := devPodContext + "/" + config.Name
+	p := plugins[key]
+	if p == nil {
+		p = &plugin{}
+		plugins[key] = p
+	}
+	pluginsMutex.Unlock()
+
+	p.once.Do(func() {
+		p.client, p.commands, p.err = startPlugin(ctx, devPodContext, options, config, log)
+	})
+	if p.err == nil && p.client.Closed() {
+		pluginsMutex.Lock()
+		if plugins[key] == p {
+			delete(plugins, key)
+		}
+		pluginsMutex.Unlock()
+
+		return nil, fmt.Errorf("provider plugin exited")
+	}
+
+	return p, p.err
+}
+
+func startPlugin(ctx context.Context, devPodContext string, options map[string]config.OptionValue, config *provider.ProviderConfig, log log.Logger) (*jsonrpc.Client, []string, error) {
+	environ, err := binaries.ToEnvironmentWithBinaries(devPodContext, nil, nil, options, config, provider.GetBaseEnvironment(devPodContext, config.Name), log)
+	if err != nil {
+		return nil, nil, err
+	}
+	if log.GetLevel() == logrus.DebugLevel {
+		environ = append(environ, DevPodDebug+"=true")
+	}
+
+	log.Debugf("Start provider plugin: %s", strings.Join(config.Exec.Plugin, " "))
+	client, err := jsonrpc.Start("provider plugin", func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+		stderr := log.Writer(logrus.DebugLevel, false)
+		defer stderr.Close()
+
+		return RunCommand(ctx, config.Exec.Plugin, environ, stdin, stdout, stderr)
+	}, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &PluginInitializeResult{}
+	err = client.Call(ctx, PluginMethodInitialize, &PluginInitializeParams{ProtocolVersion: PluginProtocolVersion}, result)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("initialize provider plugin: %w", err)
+	} else if result.ProtocolVersion != PluginProtocolVersion {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("provider plugin speaks protocol version %d, DevPod supports version %d", result.ProtocolVersion, PluginProtocolVersion)
+	}
+
+	return client, result.Commands, nil
+}
+
+// runPluginCommand runs the command through the provider plugin. It returns false if the provider has no plugin
+// or the plugin doesn't implement the command, in which case the exec command should be used.
+func runPluginCommand(
+	ctx context.Context,
+	name string,
+	devPodContext string,
+	workspace *provider.Workspace,
+	machine *provider.Machine,
+	options map[string]config.OptionValue,
+	config *provider.ProviderConfig,
+	extraEnv map[string]string,
+	stdin io.Reader,
+	stdout io.Writer,
+	stderr io.Writer,
+	log log.Logger,
+) (bool, error) {
+	if len(config.Exec.Plugin) == 0 || !slices.Contains(PluginCommands, name) {
+		return false, nil
+	}
+
+	p, err := getPlugin(ctx, devPodContext, options, config, log)
+	if err != nil {
+		log.Debugf("Error starting provider plugin, falling back to %s command: %v", name, err)
+		return false, nil
+	} else if !slices.Contains(p.commands, name) {
+		return false, nil
+	}
+
+	env := provider.ToOptions(workspace, machine, options)
+	maps.Copy(env, extraEnv)
+	binariesMap, err := binaries.GetBinaries(devPodContext, config)
+	if err != nil {
+		return true, err
+	}
+	maps.Copy(env, binariesMap)
+	if log.GetLevel() == logrus.DebugLevel {
+		env[DevPodDebug] = "true"
+	}
+
+	log.Debugf("Run %s provider command through plugin", name)
+	stream := p.client.NewStream(stdout, stderr)
+	defer p.client.RemoveStream(stream)
+
+	// input is only sent once the plugin knows the stream
+	request, err := p.client.Request(name, &PluginCommandParams{Env: env, Stream: stream})
+	if err != nil {
+		return true, err
+	}
+
+	commandCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if stdin != nil {
+		go p.client.ForwardStdin(commandCtx, stream, stdin)
+	} else {
+		err = p.client.Notify(jsonrpc.NotificationStream, &jsonrpc.StreamParams{Stream: stream, Name: jsonrpc.StreamStdin, EOF: true})
+		if err != nil {
+			return true, err
+		}
+	}
+
+	result := &PluginCommandResult{}
+	err = request.Wait(ctx, result)
+	if err != nil {
+		return true, err
+	} else if result.ExitCode != 0 {
+		return true, &PluginExitError{Code: result.ExitCode}
+	}
+
+	return true, nil
+}
+
+// PluginExitError is returned if a command run through the provider plugin exits with a non zero code
+type PluginExitError struct {
+	Code int
+}
+
+func (e *PluginExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the exit code of the command, like exec.ExitError does
+func (e *PluginExitError) ExitCode() int {
+	return e.Code
+}
@@ -0,0 +1,181 @@
+package clientimplementation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/jsonrpc"
+	"github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/types"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+const standInPluginEnv = "DEVPOD_TEST_STAND_IN_PLUGIN"
+
+// TestStandInPlugin is the provider plugin the tests start. It implements the command method, echoes the
+// command and its stdin to stdout and exits with code 3 for the command fail. The mode is the last argument:
+// serve, version to speak another protocol version or exit to exit right after the initialize request.
+func TestStandInPlugin(t *testing.T) {
+	if os.Getenv(standInPluginEnv) == "" {
+		t.Skip("only runs as stand-in provider plugin")
+	}
+
+	runStandInPlugin(os.Args[len(os.Args)-1])
+	os.Exit(0)
+}
+
+func runStandInPlugin(mode string) {
+	encoder := json.NewEncoder(os.Stdout)
+	respond := func(id *int64, result interface{}) {
+		out, _ := json.Marshal(result)
+		_ = encoder.Encode(&jsonrpc.Message{JSONRPC: jsonrpc.Version, ID: id, Result: out})
+	}
+	notify := func(stream, name, data string) {
+		out, _ := json.Marshal(&jsonrpc.StreamParams{Stream: stream, Name: name, Data: []byte(data)})
+		_ = encoder.Encode(&jsonrpc.Message{JSONRPC: jsonrpc.Version, Method: jsonrpc.NotificationStream, Params: out})
+	}
+
+	// commands wait for the end of their stdin
+	type command struct {
+		id    *int64
+		env   map[string]string
+		stdin bytes.Buffer
+	}
+	commands := map[string]*command{}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		message := &jsonrpc.Message{}
+		if json.Unmarshal(scanner.Bytes(), message) != nil {
+			continue
+		}
+
+		switch message.Method {
+		case PluginMethodInitialize:
+			protocolVersion := PluginProtocolVersion
+			if mode == "version" {
+				protocolVersion++
+			}
+			respond(message.ID, &PluginInitializeResult{ProtocolVersion: protocolVersion, Commands: []string{"command"}})
+			if mode == "exit" {
+				return
+			}
+		case "command":
+			params := &PluginCommandParams{}
+			_ = json.Unmarshal(message.Params, params)
+			commands[params.Stream] = &command{id: message.ID, env: params.Env}
+		case jsonrpc.NotificationStream:
+			params := &jsonrpc.StreamParams{}
+			_ = json.Unmarshal(message.Params, params)
+			c := commands[params.Stream]
+			if c == nil {
+				continue
+			}
+
+			c.stdin.Write(params.Data)
+			if !params.EOF {
+				continue
+			}
+
+			delete(commands, params.Stream)
+			notify(params.Stream, jsonrpc.StreamStdout, c.env[provider.CommandEnv]+":"+c.stdin.String())
+			notify(params.Stream, jsonrpc.StreamStderr, "done")
+			exitCode := 0
+			if c.env[provider.CommandEnv] == "fail" {
+				exitCode = 3
+			}
+			respond(c.id, &PluginCommandResult{ExitCode: exitCode})
+		}
+	}
+}
+
+// newStandInPluginProvider returns a provider whose plugin is the stand-in plugin in the given mode
+func newStandInPluginProvider(t *testing.T, mode string) *provider.ProviderConfig {
+	t.Setenv(standInPluginEnv, "true")
+	t.Setenv("DEVPOD_HOME", t.TempDir())
+	t.Cleanup(ClosePlugins)
+
+	return &provider.ProviderConfig{
+		Name: "stand-in-" + mode,
+		Exec: provider.ProviderCommands{
+			Plugin:  types.StrArray{os.Args[0], "-test.run=^TestStandInPlugin$", mode},
+			Command: types.StrArray{`echo "exec $COMMAND"`},
+			Status:  types.StrArray{`echo "exec status"`},
+		},
+	}
+}
+
+func runProviderCommand(config *provider.ProviderConfig, name string, command types.StrArray, commandEnv, stdin string) (string, string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := RunCommandWithBinaries(context.Background(), name, command, "default", nil, nil, nil, config, map[string]string{
+		provider.CommandEnv: commandEnv,
+	}, strings.NewReader(stdin), stdout, stderr, log.Discard)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestPluginCommand(t *testing.T) {
+	config := newStandInPluginProvider(t, "serve")
+
+	stdout, stderr, err := runProviderCommand(config, "command", config.Exec.Command, "hello", "input")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "hello:input")
+	assert.Equal(t, stderr, "done")
+
+	_, _, err = runProviderCommand(config, "command", config.Exec.Command, "fail", "")
+	assert.Error(t, err, "exit status 3")
+	exitErr := &PluginExitError{}
+	assert.Assert(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitErr.ExitCode(), 3)
+
+	// without stdin the input is closed right away
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out := &bytes.Buffer{}
+	err = RunCommandWithBinaries(ctx, "command", config.Exec.Command, "default", nil, nil, nil, config, map[string]string{
+		provider.CommandEnv: "hello",
+	}, nil, out, &bytes.Buffer{}, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "hello:")
+
+	// the plugin doesn't implement status
+	stdout, _, err = runProviderCommand(config, "status", config.Exec.Status, "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "exec status\n")
+}
+
+func TestPluginVersionMismatch(t *testing.T) {
+	config := newStandInPluginProvider(t, "version")
+
+	_, err := getPlugin(context.Background(), "default", nil, config, log.Discard)
+	assert.ErrorContains(t, err, "provider plugin speaks protocol version 2")
+
+	stdout, _, err := runProviderCommand(config, "command", config.Exec.Command, "hello", "")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "exec hello\n")
+}
+
+func TestPluginExited(t *testing.T) {
+	config := newStandInPluginProvider(t, "exit")
+
+	p, err := getPlugin(context.Background(), "default", nil, config, log.Discard)
+	assert.NilError(t, err)
+	for start := time.Now(); !p.client.Closed() && time.Since(start) < 10*time.Second; {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stdout, _, err := runProviderCommand(config, "command", config.Exec.Command, "hello", "")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "exec hello\n")
+
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+	assert.Assert(t, plugins["default/"+config.Name] == nil)
+}
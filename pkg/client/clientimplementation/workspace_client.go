@@ -223,6 +223,9 @@ func (s *workspaceClient) agentInfo(cliOptions provider.CLIOptions) *provider.Ag
 	// Set registry cache from context option
 	agentInfo.RegistryCache = s.devPodConfig.ContextOption(config.ContextOptionRegistryCache)
 
+	// Set shutdown grace period from context option
+	agentInfo.ShutdownGracePeriod = s.devPodConfig.ContextOption(config.ContextOptionShutdownGracePeriod)
+
 	return agentInfo
 }
 
@@ -318,10 +321,45 @@ func (s *workspaceClient) Create(ctx context.Context, options client.CreateOptio
 		return nil
 	}
 
+	// make sure the machine will be big enough
+	err = s.validateMachineSize()
+	if err != nil {
+		return err
+	}
+
 	// create the machine
 	return machineClient.Create(ctx, client.CreateOptions{})
 }
 
+// validateMachineSize checks the hostRequirements of a local devcontainer.json against the machine size
+// declared by the provider. Other sources are validated by the agent on the machine.
+func (s *workspaceClient) validateMachineSize() error {
+	if s.workspace.Source.LocalFolder == "" {
+		return nil
+	}
+
+	machineOptions := s.devPodConfig.ProviderOptions(s.config.Name)
+	for name, value := range s.machine.Provider.Options {
+		machineOptions[name] = value
+	}
+	available, err := s.config.MachineResources(machineOptions)
+	if err != nil {
+		return err
+	} else if available == nil {
+		return nil
+	}
+
+	devContainerConfig, err := config2.ParseDevContainerJSON(s.workspace.Source.LocalFolder, s.workspace.DevContainerPath)
+	if err != nil {
+		s.log.Debugf("Skip validating hostRequirements: %v", err)
+		return nil
+	} else if devContainerConfig == nil {
+		return nil
+	}
+
+	return config2.ValidateHostRequirements(devContainerConfig.HostRequirements, available, "machine "+s.machine.ID)
+}
+
 func (s *workspaceClient) Delete(ctx context.Context, opt client.DeleteOptions) error {
 	s.m.Lock()
 	defer s.m.Unlock()
@@ -490,18 +528,14 @@ func (s *workspaceClient) Stop(ctx context.Context, opt client.StopOptions) erro
 }
 
 func (s *workspaceClient) Command(ctx context.Context, commandOptions client.CommandOptions) (err error) {
-	// get environment variables
 	s.m.Lock()
-	environ, err := binaries.ToEnvironmentWithBinaries(s.workspace.Context, s.workspace, s.machine, s.devPodConfig.ProviderOptions(s.config.Name), s.config, map[string]string{
-		provider.CommandEnv: commandOptions.Command,
-	}, s.log)
-	if err != nil {
-		return err
-	}
+	workspace := s.workspace
+	machine := s.machine
 	s.m.Unlock()
 
-	// resolve options
-	return runCommand(ctx, "command", s.config.Exec.Command, environ, commandOptions.Stdin, commandOptions.Stdout, commandOptions.Stderr, s.log.ErrorStreamOnly())
+	return RunCommandWithBinaries(ctx, "command", s.config.Exec.Command, workspace.Context, workspace, machine, s.devPodConfig.ProviderOptions(s.config.Name), s.config, map[string]string{
+		provider.CommandEnv: commandOptions.Command,
+	}, commandOptions.Stdin, commandOptions.Stdout, commandOptions.Stderr, s.log.ErrorStreamOnly())
 }
 
 func (s *workspaceClient) Status(ctx context.Context, options client.StatusOptions) (client.Status, error) {
@@ -583,6 +617,14 @@ func (s *workspaceClient) isMachineProvider() bool {
 }
 
 func RunCommandWithBinaries(ctx context.Context, name string, command types.StrArray, context string, workspace *provider.Workspace, machine *provider.Machine, options map[string]config.OptionValue, config *provider.ProviderConfig, extraEnv map[string]string, stdin io.Reader, stdout io.Writer, stderr io.Writer, log log.Logger) (err error) {
+	// run through the provider plugin if there is one
+	if len(command) > 0 {
+		handled, err := runPluginCommand(ctx, name, context, workspace, machine, options, config, extraEnv, stdin, stdout, stderr, log)
+		if handled {
+			return err
+		}
+	}
+
 	environ, err := binaries.ToEnvironmentWithBinaries(context, workspace, machine, options, config, extraEnv, log)
 	if err != nil {
 		return err
@@ -13,6 +13,7 @@ import (
 	"github.com/blang/semver"
 	composecli "github.com/compose-spec/compose-go/v2/cli"
 	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/loft-sh/devpod/pkg/command"
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/docker"
 	"github.com/pkg/errors"
@@ -21,10 +22,14 @@ import (
 const (
 	DockerCommand        = "docker"
 	DockerComposeCommand = "docker-compose"
+	PodmanComposeCommand = "podman-compose"
 	ProjectLabel         = "com.docker.compose.project"
 	ServiceLabel         = "com.docker.compose.service"
 )
 
+// nerdctl names compose images <project>-<service> like docker compose 2.8.0 since this version
+var nerdctlDashSeparatorVersion = semver.MustParse("0.23.0")
+
 func LoadDockerComposeProject(ctx context.Context, paths []string, envFiles []string) (*composetypes.Project, error) {
 	projectOptions, err := composecli.NewProjectOptions(
 		paths,
@@ -84,6 +89,76 @@ func NewComposeHelper(dockerComposeCLI string, dockerHelper *docker.DockerHelper
 	return nil, err
 }
 
+// NewPodmanComposeHelper prefers `podman compose`, which delegates to the compose provider configured for
+// podman, and falls back to podman-compose
+func NewPodmanComposeHelper(podmanHelper *docker.DockerHelper) (*ComposeHelper, error) {
+	out, err := podmanHelper.Command(context.TODO(), "compose", "version", "--short").Output()
+	if err == nil {
+		return &ComposeHelper{
+			Command: podmanHelper.DockerCommand,
+			Version: strings.TrimSpace(string(out)),
+			Args:    []string{"compose"},
+			Docker:  podmanHelper,
+		}, nil
+	}
+
+	out, err = exec.Command(PodmanComposeCommand, "version", "--short").Output()
+	if err != nil {
+		return nil, fmt.Errorf("neither '%s compose' nor %s is available: %w", podmanHelper.DockerCommand, PodmanComposeCommand, err)
+	}
+
+	return &ComposeHelper{
+		Command: PodmanComposeCommand,
+		Version: strings.TrimSpace(string(out)),
+		Args:    []string{},
+		Docker:  podmanHelper,
+	}, nil
+}
+
+// NewNerdctlComposeHelper uses `nerdctl compose`, which runs the compose project on containerd. nerdctl doesn't
+// report a compose version, so the compose version it behaves like is derived from the nerdctl version.
+func NewNerdctlComposeHelper(nerdctlHelper *docker.DockerHelper) (*ComposeHelper, error) {
+	out, err := nerdctlHelper.Command(context.TODO(), "compose", "version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("'%s compose' is not available: %w", nerdctlHelper.DockerCommand, command.WrapCommandError(out, err))
+	}
+
+	out, err = nerdctlHelper.Command(context.TODO(), "--version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("get nerdctl version: %w", command.WrapCommandError(out, err))
+	}
+
+	version, err := nerdctlComposeVersion(string(out))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComposeHelper{
+		Command: nerdctlHelper.DockerCommand,
+		Version: version,
+		Args:    []string{"compose"},
+		Docker:  nerdctlHelper,
+	}, nil
+}
+
+// nerdctlComposeVersion returns the docker compose version whose project and image naming the nerdctl version
+// in the given `nerdctl --version` output follows
+func nerdctlComposeVersion(versionOutput string) (string, error) {
+	match := regexp.MustCompile(`\d+\.\d+\.\d+`).FindString(versionOutput)
+	if match == "" {
+		return "", fmt.Errorf("parse nerdctl version from %q", strings.TrimSpace(versionOutput))
+	}
+
+	version, err := semver.Parse(match)
+	if err != nil {
+		return "", fmt.Errorf("parse nerdctl version: %w", err)
+	} else if version.LT(nerdctlDashSeparatorVersion) {
+		return "2.7.0", nil
+	}
+
+	return "2.8.0", nil
+}
+
 func (h *ComposeHelper) FindDevContainer(ctx context.Context, projectName, serviceName string) (*config.ContainerDetails, error) {
 	containerIDs, err := h.Docker.FindContainer(ctx, []string{
 		fmt.Sprintf("%s=%s", ProjectLabel, projectName),
@@ -211,7 +286,11 @@ func (h *ComposeHelper) buildCmd(ctx context.Context, args ...string) *exec.Cmd
 	var allArgs []string
 	allArgs = append(allArgs, h.Args...)
 	allArgs = append(allArgs, args...)
-	return exec.CommandContext(ctx, h.Command, allArgs...)
+	cmd := exec.CommandContext(ctx, h.Command, allArgs...)
+	if h.Docker != nil && h.Docker.Environment != nil {
+		cmd.Env = append(os.Environ(), h.Docker.Environment...)
+	}
+	return cmd
 }
 
 func (h *ComposeHelper) useNewProjectName() (bool, error) {
@@ -0,0 +1,24 @@
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestNerdctlComposeVersion(t *testing.T) {
+	version, err := nerdctlComposeVersion("nerdctl version 1.7.6\n")
+	assert.NilError(t, err)
+	assert.Equal(t, version, "2.8.0")
+
+	version, err = nerdctlComposeVersion("nerdctl version 0.23.0")
+	assert.NilError(t, err)
+	assert.Equal(t, version, "2.8.0")
+
+	version, err = nerdctlComposeVersion("nerdctl version 0.22.2")
+	assert.NilError(t, err)
+	assert.Equal(t, version, "2.7.0")
+
+	_, err = nerdctlComposeVersion("nerdctl version unknown")
+	assert.ErrorContains(t, err, "parse nerdctl version")
+}
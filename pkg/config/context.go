@@ -22,6 +22,15 @@ const (
 	ContextOptionAgentInjectTimeout         = "AGENT_INJECT_TIMEOUT"
 	ContextOptionRegistryCache              = "REGISTRY_CACHE"
 	ContextOptionSSHStrictHostKeyChecking   = "SSH_STRICT_HOST_KEY_CHECKING"
+	ContextOptionIdleTTL                    = "IDLE_TTL"
+	ContextOptionIdleAction                 = "IDLE_ACTION"
+	ContextOptionIdleWarning                = "IDLE_WARNING"
+	ContextOptionShutdownGracePeriod        = "SHUTDOWN_GRACE_PERIOD"
+)
+
+const (
+	IdleActionStop   = "stop"
+	IdleActionDelete = "delete"
 )
 
 var ContextOptions = []ContextOption{
@@ -105,6 +114,27 @@ var ContextOptions = []ContextOption{
 		Default:     "false",
 		Enum:        []string{"true", "false"},
 	},
+	{
+		Name:        ContextOptionIdleTTL,
+		Description: "Specifies after how many days without usage a workspace expires, 0 disables expiry",
+		Default:     "0",
+	},
+	{
+		Name:        ContextOptionIdleAction,
+		Description: "Specifies if DevPod should stop or delete expired workspaces",
+		Default:     IdleActionStop,
+		Enum:        []string{IdleActionStop, IdleActionDelete},
+	},
+	{
+		Name:        ContextOptionIdleWarning,
+		Description: "Specifies how many days before expiry DevPod should warn about a workspace",
+		Default:     "1",
+	},
+	{
+		Name:        ContextOptionShutdownGracePeriod,
+		Description: "Specifies how long DevPod waits after the last SSH or IDE session disconnected before it applies the shutdownAction of the devcontainer.json, e.g. 5m",
+		Default:     "5m",
+	},
 }
 
 func MergeContextOptions(contextConfig *ContextConfig, environ []string) {
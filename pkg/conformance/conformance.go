@@ -0,0 +1,391 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/client"
+	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/options"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/random"
+	"github.com/loft-sh/devpod/pkg/types"
+	"github.com/loft-sh/devpod/pkg/version"
+	"github.com/loft-sh/log"
+	"mvdan.cc/sh/v3/interp"
+)
+
+const (
+	ResultPass = "Pass"
+	ResultFail = "Fail"
+	ResultSkip = "Skip"
+)
+
+// Options configure a conformance run
+type Options struct {
+	// MachineID is the id of the machine or workspace the provider is tested with, a random id is used if empty
+	MachineID string
+
+	// SkipAgent skips injecting the DevPod agent, e.g. if the environment can't download it
+	SkipAgent bool
+
+	// StatusTimeout is how long to wait for the provider to report a status after an operation
+	StatusTimeout time.Duration
+}
+
+// Result is the result of a single check
+type Result struct {
+	Name     string        `json:"name"`
+	Result   string        `json:"result"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the result of a conformance run
+type Report struct {
+	Provider string    `json:"provider"`
+	Results  []*Result `json:"results"`
+}
+
+// Failed returns true if any check failed
+func (r *Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Result == ResultFail {
+			return true
+		}
+	}
+
+	return false
+}
+
+type suite struct {
+	devPodConfig *config.Config
+	provider     *provider2.ProviderConfig
+	options      *Options
+	log          log.Logger
+
+	// machine is only set for machine providers, workspace only for the others
+	machine       *provider2.Machine
+	machineClient client.MachineClient
+	workspace     *provider2.Workspace
+
+	report *Report
+
+	// deleted is true once the delete check removed the machine
+	deleted bool
+}
+
+// Run runs the provider through its lifecycle: init, create, status, command, agent injection, stop, start and
+// delete. Each check compares the behaviour of the provider with what DevPod expects. The returned error is only
+// set if the run couldn't start, failed checks are part of the report. The test machine is deleted afterwards,
+// even if a check failed.
+func Run(ctx context.Context, devPodConfig *config.Config, provider *provider2.ProviderConfig, opts *Options, log log.Logger) (*Report, error) {
+	if opts.MachineID == "" {
+		opts.MachineID = "devpod-conformance-" + random.String(6)
+	}
+	if opts.StatusTimeout == 0 {
+		opts.StatusTimeout = time.Minute * 5
+	}
+
+	s := &suite{
+		devPodConfig: devPodConfig,
+		provider:     provider,
+		options:      opts,
+		log:          log,
+		report:       &Report{Provider: provider.Name},
+	}
+	if provider.IsMachineProvider() {
+		if provider2.MachineExists(devPodConfig.DefaultContext, opts.MachineID) {
+			return nil, fmt.Errorf("machine %s already exists, please choose another machine", opts.MachineID)
+		}
+
+		machineDir, err := provider2.GetMachineDir(devPodConfig.DefaultContext, opts.MachineID)
+		if err != nil {
+			return nil, err
+		}
+
+		s.machine = &provider2.Machine{
+			ID:                opts.MachineID,
+			Context:           devPodConfig.DefaultContext,
+			Provider:          provider2.MachineProviderConfig{Name: provider.Name},
+			CreationTimestamp: types.Now(),
+			Origin:            filepath.Join(machineDir, provider2.MachineConfigFile),
+		}
+		err = provider2.SaveMachineConfig(s.machine)
+		if err != nil {
+			return nil, err
+		}
+		defer s.cleanup(ctx)
+
+		s.machineClient, err = clientimplementation.NewMachineClient(devPodConfig, provider, s.machine, log)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		s.workspace = &provider2.Workspace{
+			ID:       opts.MachineID,
+			UID:      opts.MachineID,
+			Context:  devPodConfig.DefaultContext,
+			Provider: provider2.WorkspaceProviderConfig{Name: provider.Name},
+		}
+	}
+
+	s.run(ctx)
+	return s.report, nil
+}
+
+func (s *suite) run(ctx context.Context) {
+	machineProvider := s.machineClient != nil
+	hasStatus := machineProvider && len(s.provider.Exec.Status) > 0
+	hasStop := machineProvider && len(s.provider.Exec.Stop) > 0
+	hasStart := machineProvider && len(s.provider.Exec.Start) > 0
+
+	s.check(ctx, "init", len(s.provider.Exec.Init) > 0, "no init command", s.checkInit)
+	created := s.check(ctx, "create", machineProvider, "not a machine provider", func(ctx context.Context) error {
+		return s.machineClient.Create(ctx, client.CreateOptions{})
+	})
+	if !created && machineProvider {
+		s.skipAll("create failed", "status running", "command stdout", "command stderr", "command stdin", "command exit code", "command quoting", "agent")
+	} else if s.provider.IsProxyProvider() {
+		// proxy providers run the devpod commands themselves and have no command to check
+		s.skipAll("proxy provider", "status running", "command stdout", "command stderr", "command stdin", "command exit code", "command quoting", "agent")
+	} else {
+		s.check(ctx, "status running", hasStatus, "no status command", s.waitForStatus(client.StatusRunning))
+		s.check(ctx, "command stdout", true, "", s.checkStdout)
+		s.check(ctx, "command stderr", true, "", s.checkStderr)
+		s.check(ctx, "command stdin", true, "", s.checkStdin)
+		s.check(ctx, "command exit code", true, "", s.checkExitCode)
+		s.check(ctx, "command quoting", true, "", s.checkQuoting)
+		s.check(ctx, "agent", !s.options.SkipAgent, "skipped", s.checkAgent)
+	}
+
+	if !created {
+		reason := "create failed"
+		if !machineProvider {
+			reason = "not a machine provider"
+		}
+		s.skipAll(reason, "stop", "status stopped", "start", "status running after start", "delete", "status not found")
+		return
+	}
+
+	stopped := s.check(ctx, "stop", hasStop, "no stop command", func(ctx context.Context) error {
+		return s.machineClient.Stop(ctx, client.StopOptions{})
+	})
+	s.check(ctx, "status stopped", stopped && hasStatus, "stop or status not available", s.waitForStatus(client.StatusStopped))
+	started := s.check(ctx, "start", stopped && hasStart, "stop or start not available", func(ctx context.Context) error {
+		return s.machineClient.Start(ctx, client.StartOptions{})
+	})
+	s.check(ctx, "status running after start", started && hasStatus, "start or status not available", s.waitForStatus(client.StatusRunning))
+	s.deleted = s.check(ctx, "delete", true, "", func(ctx context.Context) error {
+		return s.machineClient.Delete(ctx, client.DeleteOptions{})
+	})
+	s.check(ctx, "status not found", s.deleted && hasStatus, "delete or status not available", s.waitForStatus(client.StatusNotFound))
+}
+
+// cleanup force deletes the test machine if the delete check didn't run or failed and removes its config
+func (s *suite) cleanup(ctx context.Context) {
+	if s.machineClient != nil && !s.deleted {
+		err := s.machineClient.Delete(ctx, client.DeleteOptions{Force: true})
+		if err != nil {
+			s.log.Warnf("Error deleting test machine %s: %v", s.machine.ID, err)
+		}
+	}
+
+	err := clientimplementation.DeleteMachineFolder(s.machine.Context, s.machine.ID)
+	if err != nil {
+		s.log.Warnf("Error removing test machine config %s: %v", s.machine.ID, err)
+	}
+}
+
+// check runs the check if run is true and records its result, it returns true if the check passed
+func (s *suite) check(ctx context.Context, name string, run bool, skipReason string, fn func(ctx context.Context) error) bool {
+	if !run {
+		s.skipAll(skipReason, name)
+		return false
+	}
+
+	s.log.Infof("Run check '%s'...", name)
+	now := time.Now()
+	err := fn(ctx)
+	result := &Result{Name: name, Result: ResultPass, Duration: time.Since(now).Round(time.Millisecond)}
+	if err != nil {
+		result.Result = ResultFail
+		result.Message = err.Error()
+		s.log.Errorf("Check '%s' failed: %v", name, err)
+	}
+
+	s.report.Results = append(s.report.Results, result)
+	return err == nil
+}
+
+func (s *suite) skipAll(reason string, names ...string) {
+	for _, name := range names {
+		s.report.Results = append(s.report.Results, &Result{Name: name, Result: ResultSkip, Message: reason})
+	}
+}
+
+func (s *suite) checkInit(ctx context.Context) error {
+	stdout := &bytes.Buffer{}
+	err := clientimplementation.RunCommandWithBinaries(ctx, "init", s.provider.Exec.Init, s.devPodConfig.DefaultContext, nil, nil, s.devPodConfig.ProviderOptions(s.provider.Name), s.provider, nil, nil, stdout, stdout, s.log)
+	if err != nil {
+		return fmt.Errorf("init failed: %s%w", stdout.String(), err)
+	}
+
+	return nil
+}
+
+// waitForStatus waits until the provider reports the status, other statuses are allowed until the timeout
+// as providers might still report the previous status right after an operation
+func (s *suite) waitForStatus(expected client.Status) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, s.options.StatusTimeout)
+		defer cancel()
+
+		for {
+			status, err := s.machineClient.Status(ctx, client.StatusOptions{})
+			if err != nil {
+				return err
+			} else if status == expected {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("expected status %s, got %s after %s", expected, status, s.options.StatusTimeout)
+			case <-time.After(time.Second * 2):
+			}
+		}
+	}
+}
+
+func (s *suite) command(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	return clientimplementation.RunCommandWithBinaries(ctx, "command", s.provider.Exec.Command, s.devPodConfig.DefaultContext, s.workspace, s.machine, s.devPodConfig.ProviderOptions(s.provider.Name), s.provider, map[string]string{
+		provider2.CommandEnv: command,
+	}, stdin, stdout, stderr, s.log.ErrorStreamOnly())
+}
+
+func (s *suite) checkStdout(ctx context.Context) error {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := s.command(ctx, "echo devpod-stdout", nil, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("run command: %s%w", stderr.String(), err)
+	} else if strings.TrimSpace(stdout.String()) != "devpod-stdout" {
+		return fmt.Errorf("expected stdout 'devpod-stdout', got '%s'", stdout.String())
+	}
+
+	return nil
+}
+
+func (s *suite) checkStderr(ctx context.Context) error {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := s.command(ctx, "echo devpod-stderr 1>&2", nil, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("run command: %s%w", stderr.String(), err)
+	} else if !strings.Contains(stderr.String(), "devpod-stderr") {
+		return fmt.Errorf("expected 'devpod-stderr' on stderr, got '%s'", stderr.String())
+	} else if strings.Contains(stdout.String(), "devpod-stderr") {
+		return fmt.Errorf("stderr of the command was written to stdout")
+	}
+
+	return nil
+}
+
+// checkStdin sends binary data through the command, which is how DevPod tunnels its connection to the agent
+func (s *suite) checkStdin(ctx context.Context) error {
+	input := make([]byte, 256*1024)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := s.command(ctx, "cat", bytes.NewReader(input), stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("run command: %s%w", stderr.String(), err)
+	} else if !bytes.Equal(stdout.Bytes(), input) {
+		return fmt.Errorf("expected stdin to be written to stdout unchanged, got %d of %d bytes", stdout.Len(), len(input))
+	}
+
+	return nil
+}
+
+func (s *suite) checkExitCode(ctx context.Context) error {
+	stderr := &bytes.Buffer{}
+	err := s.command(ctx, "exit 3", nil, io.Discard, stderr)
+	if err == nil {
+		return fmt.Errorf("expected the command to fail with exit code 3, but it succeeded")
+	} else if exitCode, ok := getExitCode(err); !ok || exitCode != 3 {
+		return fmt.Errorf("expected exit code 3, got: %s%w", stderr.String(), err)
+	}
+
+	return nil
+}
+
+// getExitCode returns the exit code of a failed provider command, which is either run as a process, in the
+// emulated shell or through the provider plugin
+func getExitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	var pluginExitErr *clientimplementation.PluginExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	} else if errors.As(err, &pluginExitErr) {
+		return pluginExitErr.ExitCode(), true
+	} else if exitStatus, ok := interp.IsExitStatus(err); ok {
+		return int(exitStatus), true
+	}
+
+	return 0, false
+}
+
+func (s *suite) checkQuoting(ctx context.Context) error {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := s.command(ctx, `printf '%s|' "a  b" 'c"d' '$HOME' "it's"`, nil, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("run command: %s%w", stderr.String(), err)
+	} else if stdout.String() != `a  b|c"d|$HOME|it's|` {
+		return fmt.Errorf(`expected 'a  b|c"d|$HOME|it's|', got '%s', COMMAND needs to be passed unchanged to a shell`, stdout.String())
+	}
+
+	return nil
+}
+
+func (s *suite) checkAgent(ctx context.Context) error {
+	agentConfig := options.ResolveAgentConfig(s.devPodConfig, s.provider, s.workspace, s.machine)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := agent.InjectAgentAndExecute(
+		ctx,
+		func(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+			return s.command(ctx, command, stdin, stdout, stderr)
+		},
+		agentConfig.Local == "true",
+		agentConfig.Path,
+		agentConfig.DownloadURL,
+		true,
+		fmt.Sprintf("'%s' version", agentConfig.Path),
+		nil,
+		stdout,
+		stderr,
+		s.log.ErrorStreamOnly(),
+		config.ParseTimeOption(s.devPodConfig, config.ContextOptionAgentInjectTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("inject agent: %s%w", stderr.String(), err)
+	} else if strings.TrimSpace(stdout.String()) != version.GetVersion() {
+		return fmt.Errorf("expected agent version %s, got '%s'", version.GetVersion(), strings.TrimSpace(stdout.String()))
+	}
+
+	return nil
+}
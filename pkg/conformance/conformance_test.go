@@ -0,0 +1,129 @@
+package conformance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+// standInProvider keeps the state of its machine in a file of the machine folder
+const standInProvider = `name: stand-in
+version: v0.0.1
+exec:
+  command: sh -c "${COMMAND}"
+  create: echo Running > "${MACHINE_FOLDER}/state"
+  status: cat "${MACHINE_FOLDER}/state" 2>/dev/null || echo NotFound
+  stop: echo Stopped > "${MACHINE_FOLDER}/state"
+  start: echo Running > "${MACHINE_FOLDER}/state"
+  delete: rm -f "${MACHINE_FOLDER}/state"
+`
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Provider string
+
+		ExpectedFailed []string
+	}{
+		{
+			Name:     "conformant",
+			Provider: standInProvider,
+		},
+		{
+			Name:           "unquoted command",
+			Provider:       strings.Replace(standInProvider, `sh -c "${COMMAND}"`, `sh -c ${COMMAND}`, 1),
+			ExpectedFailed: []string{"command stdout", "command stderr", "command exit code", "command quoting"},
+		},
+		{
+			Name:     "command arguments",
+			Provider: strings.Replace(standInProvider, `command: sh -c "${COMMAND}"`, `command: [sh, -c, 'eval "${COMMAND}"']`, 1),
+		},
+		{
+			Name:           "stop is ignored",
+			Provider:       strings.Replace(standInProvider, `echo Stopped`, `echo Running`, 1),
+			ExpectedFailed: []string{"status stopped"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Setenv(config.DEVPOD_HOME, t.TempDir())
+			devPodConfig, err := config.LoadConfig("", "")
+			assert.NilError(t, err)
+			providerConfig, err := provider.ParseProvider(strings.NewReader(testCase.Provider))
+			assert.NilError(t, err)
+
+			report, err := Run(context.Background(), devPodConfig, providerConfig, &Options{
+				SkipAgent:     true,
+				StatusTimeout: time.Second,
+			}, log.Discard)
+			assert.NilError(t, err)
+
+			failed := []string{}
+			for _, result := range report.Results {
+				if result.Result == ResultFail {
+					failed = append(failed, result.Name)
+				}
+			}
+			assert.DeepEqual(t, failed, append([]string{}, testCase.ExpectedFailed...))
+			assert.Equal(t, report.Failed(), len(testCase.ExpectedFailed) > 0)
+		})
+	}
+}
+
+func TestRunCleanup(t *testing.T) {
+	t.Setenv(config.DEVPOD_HOME, t.TempDir())
+	deletedFile := filepath.Join(t.TempDir(), "deleted")
+	t.Setenv("DELETED_FILE", deletedFile)
+	devPodConfig, err := config.LoadConfig("", "")
+	assert.NilError(t, err)
+	providerConfig, err := provider.ParseProvider(strings.NewReader(strings.NewReplacer(
+		`create: echo Running > "${MACHINE_FOLDER}/state"`, `create: echo Running > "${MACHINE_FOLDER}/state" && exit 1`,
+		`delete: rm -f "${MACHINE_FOLDER}/state"`, `delete: rm -f "${MACHINE_FOLDER}/state" && echo "${MACHINE_ID}" > "${DELETED_FILE}"`,
+	).Replace(standInProvider)))
+	assert.NilError(t, err)
+
+	options := &Options{SkipAgent: true, StatusTimeout: time.Second}
+	report, err := Run(context.Background(), devPodConfig, providerConfig, options, log.Discard)
+	assert.NilError(t, err)
+	assert.Assert(t, report.Failed())
+	assert.Assert(t, strings.HasPrefix(options.MachineID, "devpod-conformance-"))
+
+	// the machine is deleted although the create check failed
+	out, err := os.ReadFile(deletedFile)
+	assert.NilError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(out)), options.MachineID)
+	assert.Assert(t, !provider.MachineExists(devPodConfig.DefaultContext, options.MachineID))
+}
+
+func TestRunProxyProvider(t *testing.T) {
+	t.Setenv(config.DEVPOD_HOME, t.TempDir())
+	devPodConfig, err := config.LoadConfig("", "")
+	assert.NilError(t, err)
+	providerConfig, err := provider.ParseProvider(strings.NewReader(`name: stand-in-proxy
+version: v0.0.1
+exec:
+  proxy:
+    up: "true"
+    stop: "true"
+    delete: "true"
+    ssh: "true"
+    status: "true"
+`))
+	assert.NilError(t, err)
+
+	report, err := Run(context.Background(), devPodConfig, providerConfig, &Options{StatusTimeout: time.Second}, log.Discard)
+	assert.NilError(t, err)
+	assert.Assert(t, !report.Failed())
+	for _, result := range report.Results {
+		assert.Equal(t, result.Result, ResultSkip, result.Name)
+	}
+}
@@ -28,6 +28,15 @@ type DaemonConfig struct {
 	Platform devpod.PlatformOptions `json:"platform,omitempty"`
 	Ssh      SshConfig              `json:"ssh,omitempty"`
 	Timeout  string                 `json:"timeout"`
+	Shutdown ShutdownConfig         `json:"shutdown,omitempty"`
+}
+
+type ShutdownConfig struct {
+	// Action is the shutdownAction of the devcontainer.json
+	Action string `json:"action,omitempty"`
+
+	// GracePeriod is the duration to wait after the last session disconnected
+	GracePeriod string `json:"gracePeriod,omitempty"`
 }
 
 func BuildWorkspaceDaemonConfig(platformOptions devpod.PlatformOptions, workspaceConfig *provider2.Workspace, substitutionContext *config.SubstitutionContext, mergedConfig *config.MergedDevContainerConfig) (*DaemonConfig, error) {
@@ -29,6 +29,8 @@ func (r *runner) build(
 ) (*config.BuildInfo, error) {
 	if isDockerFileConfig(parsedConfig.Config) {
 		return r.buildAndExtendImage(ctx, parsedConfig, substitutionContext, options)
+	} else if isDockerComposeConfig(parsedConfig.Config) && r.runsComposeServices() {
+		return r.buildComposeService(ctx, parsedConfig, substitutionContext, options)
 	} else if isDockerComposeConfig(parsedConfig.Config) {
 		return r.buildDevImageCompose(ctx, parsedConfig, substitutionContext, options)
 	}
@@ -266,6 +268,11 @@ func (r *runner) buildImage(
 		return buildInfo, nil
 	}
 
+	// let drivers without docker build the image if they can
+	if buildDriver, ok := r.Driver.(driver.BuildDriver); ok && buildDriver.CanBuild() && !options.ForceDockerless {
+		return buildDriver.BuildDevContainer(ctx, prebuildHash, parsedConfig, extendedBuildInfo, dockerfilePath, dockerfileContent, r.LocalWorkspaceFolder, options)
+	}
+
 	// check if we should fallback to dockerless.
 	// This should only be OSS kubernetes as of March 06, 2025.
 	dockerDriver, ok := r.Driver.(driver.DockerDriver)
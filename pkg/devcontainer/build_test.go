@@ -1,8 +1,15 @@
 package devcontainer
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
 	"gotest.tools/assert"
 )
 
@@ -48,3 +55,83 @@ func TestFallbackContainerContextAndDockerfile(t *testing.T) {
 		assert.Equal(t, outDockerfile, testCase.expectedDockerfile, testCase.name)
 	}
 }
+
+type fakeBuildDriver struct {
+	fakeDriver
+
+	canBuild     bool
+	prebuildHash string
+}
+
+func (f *fakeBuildDriver) TargetArchitecture(ctx context.Context, workspaceID string) (string, error) {
+	return "amd64", nil
+}
+
+func (f *fakeBuildDriver) CanBuild() bool {
+	return f.canBuild
+}
+
+func (f *fakeBuildDriver) BuildDevContainer(ctx context.Context, prebuildHash string, parsedConfig *config.SubstitutedConfig, extendedBuildInfo *feature.ExtendedBuildInfo, dockerfilePath, dockerfileContent string, localWorkspaceFolder string, options provider2.BuildOptions) (*config.BuildInfo, error) {
+	f.prebuildHash = prebuildHash
+	return &config.BuildInfo{ImageName: "my-registry/devpod:" + prebuildHash, PrebuildHash: prebuildHash}, nil
+}
+
+func TestBuildImageWithBuildDriver(t *testing.T) {
+	contextPath := t.TempDir()
+	dockerfilePath := filepath.Join(contextPath, "Dockerfile")
+	dockerfileContent := "FROM alpine\n"
+	assert.NilError(t, os.WriteFile(dockerfilePath, []byte(dockerfileContent), 0o600))
+
+	parsedConfig := &config.SubstitutedConfig{Config: &config.DevContainerConfig{}}
+	parsedConfig.Config.Origin = filepath.Join(contextPath, ".devcontainer.json")
+	parsedConfig.Config.Build = &config.ConfigBuildOptions{Dockerfile: "Dockerfile"}
+
+	testCases := []struct {
+		name     string
+		canBuild bool
+		options  provider2.BuildOptions
+
+		expectBuild bool
+	}{
+		{
+			name:        "driver builds",
+			canBuild:    true,
+			options:     provider2.BuildOptions{CLIOptions: provider2.CLIOptions{ForceBuild: true}},
+			expectBuild: true,
+		},
+		{
+			name:    "driver is not configured to build",
+			options: provider2.BuildOptions{CLIOptions: provider2.CLIOptions{ForceBuild: true}},
+		},
+		{
+			name:     "dockerless is forced",
+			canBuild: true,
+			options:  provider2.BuildOptions{CLIOptions: provider2.CLIOptions{ForceBuild: true, ForceDockerless: true}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			buildDriver := &fakeBuildDriver{canBuild: testCase.canBuild}
+			r := &runner{
+				Driver: buildDriver,
+				WorkspaceConfig: &provider2.AgentWorkspaceInfo{
+					Agent: provider2.ProviderAgentConfig{Dockerless: provider2.ProviderDockerlessOptions{Disabled: "true"}},
+				},
+				LocalWorkspaceFolder: contextPath,
+				Log:                  log.Discard,
+			}
+
+			buildInfo, err := r.buildImage(context.Background(), parsedConfig, &config.SubstitutionContext{}, &config.ImageBuildInfo{}, &feature.ExtendedBuildInfo{}, dockerfilePath, dockerfileContent, testCase.options)
+			if !testCase.expectBuild {
+				assert.ErrorContains(t, err, "dockerless fallback is disabled")
+				assert.Equal(t, buildDriver.prebuildHash, "")
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Assert(t, buildDriver.prebuildHash != "")
+			assert.Equal(t, buildInfo.ImageName, "my-registry/devpod:"+buildDriver.prebuildHash)
+		})
+	}
+}
@@ -117,6 +117,13 @@ func (r *runner) runDockerCompose(
 	options UpOptions,
 	timeout time.Duration,
 ) (*config.Result, error) {
+	if options.FromSnapshot != "" {
+		return nil, fmt.Errorf("restoring snapshots is not supported for docker compose workspaces")
+	} else if r.runsComposeServices() {
+		// the driver runs the other services next to the devcontainer service
+		return r.runSingleContainer(ctx, parsedConfig, substitutionContext, options, timeout)
+	}
+
 	composeHelper, err := r.composeHelper()
 	if err != nil {
 		return nil, errors.Wrap(err, "find docker compose")
@@ -186,6 +193,11 @@ func (r *runner) runDockerCompose(
 
 		// Start container if not running
 		if !didStartProject {
+			err = r.validateHostRequirements(ctx, parsedConfig.Config)
+			if err != nil {
+				return nil, err
+			}
+
 			containerDetails, err = r.startContainer(ctx, parsedConfig, substitutionContext, project, composeHelper, composeGlobalArgs, containerDetails, options)
 			if err != nil {
 				return nil, errors.Wrap(err, "start container")
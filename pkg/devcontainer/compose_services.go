@@ -0,0 +1,314 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/loft-sh/devpod/pkg/compose"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/provider"
+	"github.com/pkg/errors"
+)
+
+// runsComposeServices returns true if the driver runs the services of a docker compose devcontainer itself.
+// The devcontainer service is then built and run like a single container and the other services are passed
+// to the driver through driver.RunOptions.Services.
+func (r *runner) runsComposeServices() bool {
+	composeDriver, ok := r.Driver.(driver.ComposeDriver)
+	return ok && composeDriver.RunsComposeServices()
+}
+
+func (r *runner) loadComposeProject(ctx context.Context, parsedConfig *config.SubstitutedConfig) (*composetypes.Project, error) {
+	composeFiles, envFiles, _, err := r.dockerComposeProjectFiles(parsedConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "get compose/env files")
+	}
+
+	r.Log.Debugf("Loading docker compose project %+v", composeFiles)
+	project, err := compose.LoadDockerComposeProject(ctx, composeFiles, envFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "load docker compose project")
+	}
+
+	return project, nil
+}
+
+// buildComposeService builds the devcontainer service of a docker compose project the same way
+// an image or Dockerfile based devcontainer is built
+func (r *runner) buildComposeService(
+	ctx context.Context,
+	parsedConfig *config.SubstitutedConfig,
+	substitutionContext *config.SubstitutionContext,
+	options provider.BuildOptions,
+) (*config.BuildInfo, error) {
+	project, err := r.loadComposeProject(ctx, parsedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	service := parsedConfig.Config.Service
+	composeService, err := project.GetService(service)
+	if err != nil {
+		return nil, fmt.Errorf("service '%s' configured in devcontainer.json not found in Docker Compose configuration", service)
+	}
+
+	serviceConfig, err := composeServiceConfig(parsedConfig, composeService)
+	if err != nil {
+		return nil, err
+	} else if isDockerFileConfig(serviceConfig.Config) {
+		return r.buildAndExtendImage(ctx, serviceConfig, substitutionContext, options)
+	}
+
+	return r.extendImage(ctx, serviceConfig, substitutionContext, options)
+}
+
+// composeServiceConfig returns a copy of the devcontainer config that uses the image or build of the compose service
+func composeServiceConfig(parsedConfig *config.SubstitutedConfig, composeService composetypes.ServiceConfig) (*config.SubstitutedConfig, error) {
+	serviceConfig := *parsedConfig.Config
+	serviceConfig.ComposeContainer = config.ComposeContainer{}
+	serviceConfig.ImageContainer = config.ImageContainer{Image: composeService.Image}
+	serviceConfig.DockerfileContainer = config.DockerfileContainer{}
+	if composeService.Build != nil {
+		// paths in the devcontainer.json are relative to its folder
+		configDir := filepath.Dir(parsedConfig.Config.Origin)
+		dockerfile := composeService.Build.Dockerfile
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(composeService.Build.Context, dockerfile)
+		}
+		dockerfile, err := filepath.Rel(configDir, dockerfile)
+		if err != nil {
+			return nil, fmt.Errorf("resolve dockerfile of service '%s': %w", composeService.Name, err)
+		}
+		context, err := filepath.Rel(configDir, composeService.Build.Context)
+		if err != nil {
+			return nil, fmt.Errorf("resolve build context of service '%s': %w", composeService.Name, err)
+		}
+
+		serviceConfig.Build = &config.ConfigBuildOptions{
+			Dockerfile: filepath.ToSlash(dockerfile),
+			Context:    filepath.ToSlash(context),
+			Target:     composeService.Build.Target,
+			Args:       composeEnv(composeService.Build.Args),
+		}
+	} else if composeService.Image == "" {
+		return nil, fmt.Errorf("service '%s' has neither an image nor a build", composeService.Name)
+	}
+
+	return &config.SubstitutedConfig{
+		Config: &serviceConfig,
+		Raw:    parsedConfig.Raw,
+	}, nil
+}
+
+// addComposeServices adds the environment and volumes of the devcontainer service and the services
+// it needs to the run options
+func (r *runner) addComposeServices(ctx context.Context, parsedConfig *config.SubstitutedConfig, runOptions *driver.RunOptions) error {
+	project, err := r.loadComposeProject(ctx, parsedConfig)
+	if err != nil {
+		return err
+	}
+
+	devService, err := project.GetService(parsedConfig.Config.Service)
+	if err != nil {
+		return fmt.Errorf("service '%s' configured in devcontainer.json not found in Docker Compose configuration", parsedConfig.Config.Service)
+	}
+
+	// containerEnv of the devcontainer.json takes precedence over the compose environment
+	for k, v := range composeEnv(devService.Environment) {
+		if _, ok := runOptions.Env[k]; !ok {
+			runOptions.Env[k] = v
+		}
+	}
+
+	// bind mounts of the devcontainer service are replaced by the workspace mount
+	runOptions.Mounts = slices.Clone(runOptions.Mounts)
+	for _, mount := range composeMounts(devService) {
+		if mount.Type != composetypes.VolumeTypeBind {
+			runOptions.Mounts = append(runOptions.Mounts, mount)
+		}
+	}
+
+	// collect the services to run, that is the runServices or all, and everything they depend on
+	names := slices.Clone(parsedConfig.Config.RunServices)
+	if len(names) == 0 {
+		names = project.ServiceNames()
+	}
+	names = append(names, devService.GetDependencies()...)
+	services := map[string]composetypes.ServiceConfig{}
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+		if _, ok := services[name]; ok || name == devService.Name {
+			continue
+		}
+
+		service, err := project.GetService(name)
+		if err != nil {
+			return fmt.Errorf("service '%s' not found in Docker Compose configuration", name)
+		}
+		services[name] = service
+		names = append(names, service.GetDependencies()...)
+	}
+
+	// services another service waits for to become healthy need a health check
+	waitHealthy := map[string]bool{}
+	for _, service := range append(slices.Collect(maps.Values(services)), devService) {
+		for name, dependency := range service.DependsOn {
+			if dependency.Condition == composetypes.ServiceConditionHealthy {
+				waitHealthy[name] = true
+			}
+		}
+	}
+
+	runOptions.Services = nil
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		serviceOptions, err := composeServiceOptions(services[name])
+		if err != nil {
+			return err
+		}
+
+		if waitHealthy[name] {
+			serviceOptions.HealthCheck = composeHealthCheck(services[name].HealthCheck)
+			if serviceOptions.HealthCheck == nil {
+				r.Log.Warnf("Service '%s' has no healthcheck, will not wait for it to become healthy", name)
+			}
+		}
+
+		runOptions.Services = append(runOptions.Services, serviceOptions)
+	}
+
+	return nil
+}
+
+func composeServiceOptions(service composetypes.ServiceConfig) (*driver.ServiceOptions, error) {
+	if service.Image == "" {
+		return nil, fmt.Errorf("service '%s' has no image, building service images is only supported for the devcontainer service with this provider", service.Name)
+	}
+
+	// the service should be reachable by the same names as in the compose network
+	aliases := []string{}
+	for _, alias := range []string{service.ContainerName, service.Hostname} {
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, network := range service.Networks {
+		if network != nil {
+			aliases = append(aliases, network.Aliases...)
+		}
+	}
+	slices.Sort(aliases)
+	aliases = slices.DeleteFunc(slices.Compact(aliases), func(alias string) bool {
+		return alias == service.Name
+	})
+
+	ports := []int{}
+	for _, port := range service.Ports {
+		ports = append(ports, int(port.Target))
+	}
+	for _, expose := range service.Expose {
+		expose, _, _ = strings.Cut(expose, "/")
+		port, err := strconv.Atoi(expose)
+		if err == nil {
+			ports = append(ports, port)
+		}
+	}
+	slices.Sort(ports)
+
+	return &driver.ServiceOptions{
+		Name:       service.Name,
+		Aliases:    aliases,
+		Image:      service.Image,
+		User:       service.User,
+		Entrypoint: service.Entrypoint,
+		Cmd:        service.Command,
+		WorkingDir: service.WorkingDir,
+		Env:        composeEnv(service.Environment),
+		Ports:      slices.Compact(ports),
+		Mounts:     composeMounts(service),
+		DependsOn:  slices.Sorted(maps.Keys(service.DependsOn)),
+	}, nil
+}
+
+// composeMounts converts the volumes and tmpfs of a compose service. Named volumes keep their source,
+// anonymous volumes have an empty source.
+func composeMounts(service composetypes.ServiceConfig) []*config.Mount {
+	mounts := []*config.Mount{}
+	for _, volume := range service.Volumes {
+		switch volume.Type {
+		case composetypes.VolumeTypeVolume, composetypes.VolumeTypeBind, composetypes.VolumeTypeTmpfs:
+			mounts = append(mounts, &config.Mount{
+				Type:   volume.Type,
+				Source: volume.Source,
+				Target: volume.Target,
+			})
+		}
+	}
+	for _, tmpfs := range service.Tmpfs {
+		target, _, _ := strings.Cut(tmpfs, ":")
+		mounts = append(mounts, &config.Mount{
+			Type:   composetypes.VolumeTypeTmpfs,
+			Target: target,
+		})
+	}
+
+	return mounts
+}
+
+// composeHealthCheck returns nil if the health check is disabled or missing
+func composeHealthCheck(healthCheck *composetypes.HealthCheckConfig) *driver.ServiceHealthCheck {
+	if healthCheck == nil || healthCheck.Disable || len(healthCheck.Test) == 0 {
+		return nil
+	}
+
+	var command []string
+	switch healthCheck.Test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		command = healthCheck.Test[1:]
+	case "CMD-SHELL":
+		command = []string{"/bin/sh", "-c", strings.Join(healthCheck.Test[1:], " ")}
+	default:
+		command = healthCheck.Test
+	}
+
+	retHealthCheck := &driver.ServiceHealthCheck{
+		Command: command,
+	}
+	if healthCheck.Interval != nil {
+		retHealthCheck.Interval = time.Duration(*healthCheck.Interval)
+	}
+	if healthCheck.Timeout != nil {
+		retHealthCheck.Timeout = time.Duration(*healthCheck.Timeout)
+	}
+	if healthCheck.StartPeriod != nil {
+		retHealthCheck.StartPeriod = time.Duration(*healthCheck.StartPeriod)
+	}
+	if healthCheck.Retries != nil {
+		retHealthCheck.Retries = int(*healthCheck.Retries)
+	}
+
+	return retHealthCheck
+}
+
+func composeEnv(mapping composetypes.MappingWithEquals) map[string]string {
+	env := map[string]string{}
+	for k, v := range mapping {
+		if v != nil {
+			env[k] = *v
+		}
+	}
+	return env
+}
@@ -82,6 +82,10 @@ func (r *runner) getDefaultConfig(options provider2.CLIOptions) (*config.DevCont
 	}
 
 	defaultConfig.Origin = path.Join(filepath.ToSlash(r.LocalWorkspaceFolder), ".devcontainer.json")
+	if r.DryRun {
+		return defaultConfig, nil
+	}
+
 	err := config.SaveDevContainerJSON(defaultConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "write default devcontainer.json")
@@ -110,6 +114,10 @@ func (r *runner) substitute(
 		r.WorkspaceConfig.Workspace.ID,
 		rawParsedConfig,
 	)
+	if r.runsOnHost() {
+		// the workspace is used in place, there is no folder within a container
+		workspaceMount, containerWorkspaceFolder = "", r.LocalWorkspaceFolder
+	}
 	substitutionContext := &config.SubstitutionContext{
 		DevContainerID:           r.ID,
 		LocalWorkspaceFolder:     r.LocalWorkspaceFolder,
@@ -125,10 +133,10 @@ func (r *runner) substitute(
 	if err != nil {
 		return nil, nil, err
 	}
-	if parsedConfig.WorkspaceFolder != "" {
+	if parsedConfig.WorkspaceFolder != "" && !r.runsOnHost() {
 		substitutionContext.ContainerWorkspaceFolder = parsedConfig.WorkspaceFolder
 	}
-	if parsedConfig.WorkspaceMount != "" {
+	if parsedConfig.WorkspaceMount != "" && !r.runsOnHost() {
 		substitutionContext.WorkspaceMount = parsedConfig.WorkspaceMount
 	}
 
@@ -6,6 +6,9 @@ import (
 
 const (
 	DockerIDLabel           = "dev.containers.id"
+	PrebuildHashLabel       = "devpod.prebuildHash"
+	ImageLabel              = "devpod.image"
+	SnapshotLabel           = "devpod.snapshot"
 	DockerfileDefaultTarget = "dev_container_auto_added_stage_label"
 
 	DevPodContextFeatureFolder      = ".devpod-internal"
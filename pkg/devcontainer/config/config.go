@@ -10,6 +10,12 @@ import (
 	"github.com/loft-sh/devpod/pkg/types"
 )
 
+const (
+	ShutdownActionNone          = "none"
+	ShutdownActionStopContainer = "stopContainer"
+	ShutdownActionStopCompose   = "stopCompose"
+)
+
 type MergedDevContainerConfig struct {
 	DevContainerConfigBase  `json:",inline"`
 	UpdatedConfigProperties `json:",inline"`
@@ -57,7 +63,7 @@ type DevContainerConfigBase struct {
 	ForwardPorts types.StrIntArray `json:"forwardPorts,omitempty"`
 
 	// Set default properties that are applied when a specific port number is forwarded.
-	PortsAttributes map[string]PortAttribute `json:"portAttributes,omitempty"`
+	PortsAttributes map[string]PortAttribute `json:"portsAttributes,omitempty"`
 
 	// Set default properties that are applied to all ports that don't get properties from the setting `remote.portsAttributes`.
 	OtherPortsAttributes *PortAttribute `json:"otherPortsAttributes,omitempty"`
@@ -327,6 +333,17 @@ type PortAttribute struct {
 type DevPodCustomizations struct {
 	PrebuildRepository         types.StrArray    `json:"prebuildRepository,omitempty"`
 	FeatureDownloadHTTPHeaders map[string]string `json:"featureDownloadHTTPHeaders,omitempty"`
+
+	// LifecycleHooks configures how the lifecycle hooks run, keyed by the hook name, e.g. postCreateCommand
+	LifecycleHooks map[string]LifecycleHookOptions `json:"lifecycleHooks,omitempty"`
+}
+
+type LifecycleHookOptions struct {
+	// Timeout is the maximum duration of the hook including all retries, e.g. 10m
+	Timeout string `json:"timeout,omitempty"`
+
+	// Retries is the number of times a failed command of the hook is retried
+	Retries int `json:"retries,omitempty"`
 }
 
 type VSCodeCustomizations struct {
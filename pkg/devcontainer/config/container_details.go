@@ -16,6 +16,7 @@ type ImageDetailsConfig struct {
 type ContainerDetails struct {
 	ID      string                 `json:"ID,omitempty"`
 	Created string                 `json:"Created,omitempty"`
+	Image   string                 `json:"Image,omitempty"`
 	State   ContainerDetailsState  `json:"State,omitempty"`
 	Config  ContainerDetailsConfig `json:"Config,omitempty"`
 }
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+type DiffAction string
+
+const (
+	DiffActionRebuild  DiffAction = "needs rebuild"
+	DiffActionRecreate DiffAction = "needs recreate"
+	DiffActionRestart  DiffAction = "needs restart"
+	DiffActionLive     DiffAction = "applies live"
+)
+
+// diffActionSeverity orders the actions from least to most disruptive
+var diffActionSeverity = map[DiffAction]int{
+	DiffActionLive:     0,
+	DiffActionRestart:  1,
+	DiffActionRecreate: 2,
+	DiffActionRebuild:  3,
+}
+
+// diffPropertyActions maps merged config properties to the action that is required to apply a change.
+// Properties that are not listed apply live, i.e. on the next devpod up or ssh session.
+var diffPropertyActions = map[string]DiffAction{
+	"image":                       DiffActionRebuild,
+	"dockerFile":                  DiffActionRebuild,
+	"context":                     DiffActionRebuild,
+	"build":                       DiffActionRebuild,
+	"features":                    DiffActionRebuild,
+	"overrideFeatureInstallOrder": DiffActionRebuild,
+
+	"entrypoints":          DiffActionRecreate,
+	"appPort":              DiffActionRecreate,
+	"containerEnv":         DiffActionRecreate,
+	"containerUser":        DiffActionRecreate,
+	"mounts":               DiffActionRecreate,
+	"init":                 DiffActionRecreate,
+	"privileged":           DiffActionRecreate,
+	"capAdd":               DiffActionRecreate,
+	"securityOpt":          DiffActionRecreate,
+	"runArgs":              DiffActionRecreate,
+	"workspaceMount":       DiffActionRecreate,
+	"workspaceFolder":      DiffActionRecreate,
+	"overrideCommand":      DiffActionRecreate,
+	"updateRemoteUserUID":  DiffActionRecreate,
+	"hostRequirements":     DiffActionRecreate,
+	"onCreateCommand":      DiffActionRecreate,
+	"updateContentCommand": DiffActionRecreate,
+	"postCreateCommand":    DiffActionRecreate,
+	"dockerComposeFile":    DiffActionRecreate,
+	"service":              DiffActionRecreate,
+	"runServices":          DiffActionRecreate,
+	"containerID":          DiffActionRecreate,
+
+	"postStartCommand": DiffActionRestart,
+	"shutdownAction":   DiffActionRestart,
+}
+
+// DiffResult is the drift between a running dev container and its current configuration
+type DiffResult struct {
+	// Action is the most disruptive action needed to apply all differences. Empty if there are none.
+	Action DiffAction `json:"action,omitempty"`
+
+	// Differences are the changed properties
+	Differences []ConfigDiff `json:"differences,omitempty"`
+
+	// Warnings are reasons why drift might not be detected completely
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ConfigDiff is a single changed property
+type ConfigDiff struct {
+	Property string      `json:"property"`
+	Action   DiffAction  `json:"action"`
+	Current  interface{} `json:"current,omitempty"`
+	Desired  interface{} `json:"desired,omitempty"`
+}
+
+// GetDiffAction returns the action needed to apply a change to the given property
+func GetDiffAction(property string) DiffAction {
+	action, ok := diffPropertyActions[property]
+	if !ok {
+		return DiffActionLive
+	}
+
+	return action
+}
+
+// DiffMergedConfig compares the merged configuration of a running container with the desired one
+func DiffMergedConfig(current, desired *MergedDevContainerConfig) ([]ConfigDiff, error) {
+	currentMap, err := toMap(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredMap, err := toMap(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]bool{}
+	for k := range currentMap {
+		properties[k] = true
+	}
+	for k := range desiredMap {
+		properties[k] = true
+	}
+
+	diffs := []ConfigDiff{}
+	for property := range properties {
+		if reflect.DeepEqual(currentMap[property], desiredMap[property]) {
+			continue
+		}
+
+		diffs = append(diffs, ConfigDiff{
+			Property: property,
+			Action:   GetDiffAction(property),
+			Current:  currentMap[property],
+			Desired:  desiredMap[property],
+		})
+	}
+
+	SortConfigDiffs(diffs)
+	return diffs, nil
+}
+
+// SortConfigDiffs sorts the most disruptive differences first
+func SortConfigDiffs(diffs []ConfigDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffActionSeverity[diffs[i].Action] != diffActionSeverity[diffs[j].Action] {
+			return diffActionSeverity[diffs[i].Action] > diffActionSeverity[diffs[j].Action]
+		}
+
+		return diffs[i].Property < diffs[j].Property
+	})
+}
+
+// NewDiffResult creates a result with the most disruptive action of the given differences
+func NewDiffResult(diffs []ConfigDiff, warnings []string) *DiffResult {
+	SortConfigDiffs(diffs)
+	result := &DiffResult{
+		Differences: diffs,
+		Warnings:    warnings,
+	}
+	if len(diffs) > 0 {
+		result.Action = diffs[0].Action
+	}
+
+	return result
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	retMap := map[string]interface{}{}
+	err = json.Unmarshal(out, &retMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return retMap, nil
+}
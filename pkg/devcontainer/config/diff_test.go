@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/types"
+	"gotest.tools/assert"
+)
+
+func TestDiffMergedConfig(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		current *MergedDevContainerConfig
+		desired *MergedDevContainerConfig
+
+		expectedProperties []string
+		expectedAction     DiffAction
+	}{
+		{
+			name:    "no changes",
+			current: &MergedDevContainerConfig{DevContainerConfigBase: DevContainerConfigBase{RemoteUser: "vscode"}},
+			desired: &MergedDevContainerConfig{DevContainerConfigBase: DevContainerConfigBase{RemoteUser: "vscode"}},
+
+			expectedProperties: []string{},
+		},
+		{
+			name:    "remote env applies live",
+			current: &MergedDevContainerConfig{DevContainerConfigBase: DevContainerConfigBase{RemoteEnv: map[string]string{"A": "1"}}},
+			desired: &MergedDevContainerConfig{DevContainerConfigBase: DevContainerConfigBase{RemoteEnv: map[string]string{"A": "2"}}},
+
+			expectedProperties: []string{"remoteEnv"},
+			expectedAction:     DiffActionLive,
+		},
+		{
+			name: "most disruptive first",
+			current: &MergedDevContainerConfig{
+				NonComposeBase:          NonComposeBase{ContainerEnv: map[string]string{"A": "1"}},
+				UpdatedConfigProperties: UpdatedConfigProperties{PostStartCommands: []types.LifecycleHook{{"": {"echo"}}}},
+			},
+			desired: &MergedDevContainerConfig{
+				DevContainerConfigBase: DevContainerConfigBase{ForwardPorts: types.StrIntArray{"8080"}},
+			},
+
+			expectedProperties: []string{"containerEnv", "postStartCommand", "forwardPorts"},
+			expectedAction:     DiffActionRecreate,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diffs, err := DiffMergedConfig(testCase.current, testCase.desired)
+			assert.NilError(t, err)
+
+			properties := []string{}
+			for _, diff := range diffs {
+				properties = append(properties, diff.Property)
+			}
+			assert.DeepEqual(t, properties, testCase.expectedProperties)
+			assert.Equal(t, NewDiffResult(diffs, nil).Action, testCase.expectedAction)
+		})
+	}
+}
@@ -7,6 +7,9 @@ type FeatureSet struct {
 	Folder   string
 	Config   *FeatureConfig
 	Options  interface{}
+
+	// DependsOn are the feature sets that have to be installed before this one
+	DependsOn []*FeatureSet
 }
 
 type FeatureConfig struct {
@@ -58,12 +61,30 @@ type FeatureConfig struct {
 	// Array of ID's of Features that should execute before this one. Allows control for feature authors on soft dependencies between different Features.
 	InstallsAfter []string `json:"installsAfter,omitempty"`
 
+	// Features with their options that have to be installed before this one. Unlike installsAfter, these are hard dependencies and are installed even if they are not part of the devcontainer.json.
+	DependsOn map[string]interface{} `json:"dependsOn,omitempty"`
+
 	// Container environment variables.
 	ContainerEnv map[string]string `json:"containerEnv,omitempty"`
 
 	// Tool-specific configuration. Each tool should use a JSON object subproperty with a unique name to group its customizations.
 	Customizations map[string]interface{} `json:"customizations,omitempty"`
 
+	// A command to run when creating the container, before the onCreateCommand of the devcontainer.json.
+	OnCreateCommand types.LifecycleHook `json:"onCreateCommand,omitempty"`
+
+	// A command to run when creating the container and when the workspace content was updated, before the updateContentCommand of the devcontainer.json.
+	UpdateContentCommand types.LifecycleHook `json:"updateContentCommand,omitempty"`
+
+	// A command to run after creating the container, before the postCreateCommand of the devcontainer.json.
+	PostCreateCommand types.LifecycleHook `json:"postCreateCommand,omitempty"`
+
+	// A command to run after starting the container, before the postStartCommand of the devcontainer.json.
+	PostStartCommand types.LifecycleHook `json:"postStartCommand,omitempty"`
+
+	// A command to run when attaching to the container, before the postAttachCommand of the devcontainer.json.
+	PostAttachCommand types.LifecycleHook `json:"postAttachCommand,omitempty"`
+
 	// Origin is the path where the feature was loaded from
 	Origin string `json:"-"`
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// HostResources are the resources a target offers to a dev container. Zero values mean the
+// resource is unknown and will not be validated.
+type HostResources struct {
+	// CPUs is the number of available CPUs
+	CPUs int
+
+	// Memory is the available memory in bytes
+	Memory int64
+
+	// Storage is the available disk space in bytes
+	Storage int64
+
+	// GPU is true if the target can provide a GPU. A nil value means unknown.
+	GPU *bool
+}
+
+// RequiresGPU returns true if the dev container cannot run without a GPU. A value of "optional"
+// means a GPU is used if available.
+func (h *HostRequirements) RequiresGPU() bool {
+	if h == nil {
+		return false
+	}
+
+	gpu, _ := h.GPU.Bool()
+	return gpu
+}
+
+// MemoryBytes returns the required memory in bytes or 0 if no memory is required
+func (h *HostRequirements) MemoryBytes() (int64, error) {
+	if h == nil {
+		return 0, nil
+	}
+
+	return parseHostRequirementBytes("memory", h.Memory)
+}
+
+// StorageBytes returns the required storage in bytes or 0 if no storage is required
+func (h *HostRequirements) StorageBytes() (int64, error) {
+	if h == nil {
+		return 0, nil
+	}
+
+	return parseHostRequirementBytes("storage", h.Storage)
+}
+
+// ValidateHostRequirements checks the requirements against the resources of the target and returns an
+// error that lists all unmet requirements
+func ValidateHostRequirements(requirements *HostRequirements, available *HostResources, target string) error {
+	if requirements == nil || available == nil {
+		return nil
+	}
+
+	memory, err := requirements.MemoryBytes()
+	if err != nil {
+		return err
+	}
+	storage, err := requirements.StorageBytes()
+	if err != nil {
+		return err
+	}
+
+	unmet := []string{}
+	if requirements.CPUs > 0 && available.CPUs > 0 && available.CPUs < requirements.CPUs {
+		unmet = append(unmet, fmt.Sprintf("%d CPUs required, but only %d available", requirements.CPUs, available.CPUs))
+	}
+	if memory > 0 && available.Memory > 0 && available.Memory < memory {
+		unmet = append(unmet, fmt.Sprintf("%s memory required, but only %s available", units.BytesSize(float64(memory)), units.BytesSize(float64(available.Memory))))
+	}
+	if storage > 0 && available.Storage > 0 && available.Storage < storage {
+		unmet = append(unmet, fmt.Sprintf("%s storage required, but only %s available", units.BytesSize(float64(storage)), units.BytesSize(float64(available.Storage))))
+	}
+	if requirements.RequiresGPU() && available.GPU != nil && !*available.GPU {
+		unmet = append(unmet, "a GPU is required, but none is available")
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("%s doesn't meet the hostRequirements of the devcontainer.json: %s", target, strings.Join(unmet, ", "))
+	}
+
+	return nil
+}
+
+func parseHostRequirementBytes(name, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	bytes, err := units.RAMInBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse hostRequirements.%s: %w", name, err)
+	}
+
+	return bytes, nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateHostRequirements(t *testing.T) {
+	gpu := false
+	available := &HostResources{CPUs: 4, Memory: 8 << 30, GPU: &gpu}
+
+	assert.NilError(t, ValidateHostRequirements(&HostRequirements{CPUs: 4, Memory: "8gb", GPU: "optional"}, available, "docker host"))
+
+	// unknown resources are not validated
+	assert.NilError(t, ValidateHostRequirements(&HostRequirements{Storage: "1tb"}, available, "docker host"))
+
+	err := ValidateHostRequirements(&HostRequirements{CPUs: 8, Memory: "16gb", GPU: "true"}, available, "docker host")
+	assert.Error(t, err, "docker host doesn't meet the hostRequirements of the devcontainer.json: 8 CPUs required, but only 4 available, 16GiB memory required, but only 8GiB available, a GPU is required, but none is available")
+
+	err = ValidateHostRequirements(&HostRequirements{Memory: "lots"}, available, "docker host")
+	assert.ErrorContains(t, err, "parse hostRequirements.memory")
+}
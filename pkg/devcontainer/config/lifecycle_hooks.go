@@ -0,0 +1,46 @@
+package config
+
+const (
+	OnCreateCommand      = "onCreateCommand"
+	UpdateContentCommand = "updateContentCommand"
+	PostCreateCommand    = "postCreateCommand"
+	PostStartCommand     = "postStartCommand"
+	PostAttachCommand    = "postAttachCommand"
+)
+
+// LifecycleHookNames are the lifecycle hooks that run inside the container in the order of the spec
+var LifecycleHookNames = []string{
+	OnCreateCommand,
+	UpdateContentCommand,
+	PostCreateCommand,
+	PostStartCommand,
+	PostAttachCommand,
+}
+
+const (
+	LifecycleHooksStateRunning = "Running"
+	LifecycleHooksStateDone    = "Done"
+	LifecycleHooksStateFailed  = "Failed"
+)
+
+// LifecycleHooksStatus is the progress of the lifecycle hooks that run in the background after the hook
+// named in waitFor finished
+type LifecycleHooksStatus struct {
+	// State is either Running, Done or Failed
+	State string `json:"state,omitempty"`
+
+	// Hook is the hook that is currently running or that failed
+	Hook string `json:"hook,omitempty"`
+
+	// Hooks are the hooks that run in the background
+	Hooks []string `json:"hooks,omitempty"`
+
+	// Error is the error of the failed hook
+	Error string `json:"error,omitempty"`
+
+	// StartedAt is the time the background hooks were started in RFC 3339 format
+	StartedAt string `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the background hooks finished in RFC 3339 format
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
@@ -36,13 +36,15 @@ func MergeConfiguration(config *DevContainerConfig, imageMetadataEntries []*Imag
 	mergedConfig.Privileged = some(reversed, func(entry *ImageMetadata) *bool { return entry.Privileged })
 	mergedConfig.CapAdd = unique(unionOrNil(reversed, func(entry *ImageMetadata) []string { return entry.CapAdd }))
 	mergedConfig.SecurityOpt = unique(unionOrNil(reversed, func(entry *ImageMetadata) []string { return entry.SecurityOpt }))
-	mergedConfig.Entrypoints = collectOrNil(reversed, func(entry *ImageMetadata) string { return entry.Entrypoint })
 	mergedConfig.Mounts = mergeMounts(reversed)
-	mergedConfig.OnCreateCommands = mergeLifestyleHooks(reversed, func(entry *ImageMetadata) types.LifecycleHook { return entry.OnCreateCommand })
-	mergedConfig.UpdateContentCommands = mergeLifestyleHooks(reversed, func(entry *ImageMetadata) types.LifecycleHook { return entry.UpdateContentCommand })
-	mergedConfig.PostCreateCommands = mergeLifestyleHooks(reversed, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostCreateCommand })
-	mergedConfig.PostStartCommands = mergeLifestyleHooks(reversed, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostStartCommand })
-	mergedConfig.PostAttachCommands = mergeLifestyleHooks(reversed, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostAttachCommand })
+
+	// entrypoints and lifecycle hooks run in metadata order, which is base image, features in install order and the devcontainer.json last
+	mergedConfig.Entrypoints = collectOrNil(imageMetadataEntries, func(entry *ImageMetadata) string { return entry.Entrypoint })
+	mergedConfig.OnCreateCommands = mergeLifestyleHooks(imageMetadataEntries, func(entry *ImageMetadata) types.LifecycleHook { return entry.OnCreateCommand })
+	mergedConfig.UpdateContentCommands = mergeLifestyleHooks(imageMetadataEntries, func(entry *ImageMetadata) types.LifecycleHook { return entry.UpdateContentCommand })
+	mergedConfig.PostCreateCommands = mergeLifestyleHooks(imageMetadataEntries, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostCreateCommand })
+	mergedConfig.PostStartCommands = mergeLifestyleHooks(imageMetadataEntries, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostStartCommand })
+	mergedConfig.PostAttachCommands = mergeLifestyleHooks(imageMetadataEntries, func(entry *ImageMetadata) types.LifecycleHook { return entry.PostAttachCommand })
 	mergedConfig.WaitFor = firstString(reversed, func(entry *ImageMetadata) string { return entry.WaitFor })
 	mergedConfig.RemoteUser = firstString(reversed, func(entry *ImageMetadata) string { return entry.RemoteUser })
 	mergedConfig.ContainerUser = firstString(reversed, func(entry *ImageMetadata) string { return entry.ContainerUser })
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/types"
+	"gotest.tools/assert"
+)
+
+func TestMergeConfigurationLifecycleHookOrder(t *testing.T) {
+	entries := []*ImageMetadata{
+		{ID: "base", DevContainerActions: DevContainerActions{PostCreateCommand: types.LifecycleHook{"": {"echo base"}}}},
+		{ID: "feature", DevContainerActions: DevContainerActions{PostCreateCommand: types.LifecycleHook{"": {"echo feature"}}}, Entrypoint: "/feature-entrypoint.sh"},
+		{DevContainerActions: DevContainerActions{PostCreateCommand: types.LifecycleHook{"": {"echo user"}}}},
+	}
+
+	mergedConfig, err := MergeConfiguration(&DevContainerConfig{}, entries)
+	assert.NilError(t, err)
+
+	// hooks run in spec order: base image, features and the devcontainer.json last
+	assert.DeepEqual(t, mergedConfig.PostCreateCommands, []types.LifecycleHook{
+		{"": {"echo base"}},
+		{"": {"echo feature"}},
+		{"": {"echo user"}},
+	})
+	assert.DeepEqual(t, mergedConfig.Entrypoints, []string{"/feature-entrypoint.sh"})
+}
@@ -0,0 +1,109 @@
+package config
+
+import "github.com/loft-sh/devpod/pkg/types"
+
+// Plan is the resolved execution plan of devpod up. It describes what would be built and started
+// without creating a machine, building an image or starting a container.
+type Plan struct {
+	// Workspace is the id of the workspace
+	Workspace string `json:"workspace"`
+
+	// Source is the source of the workspace
+	Source string `json:"source,omitempty"`
+
+	// Exists is true if the workspace already exists
+	Exists bool `json:"exists"`
+
+	// Provider is the name of the provider
+	Provider string `json:"provider"`
+
+	// ProviderOptions are the resolved provider options, passwords are redacted
+	ProviderOptions map[string]string `json:"providerOptions,omitempty"`
+
+	// Machine is the machine the workspace would run on
+	Machine *PlanMachine `json:"machine,omitempty"`
+
+	// Driver is the driver that would run the dev container
+	Driver string `json:"driver,omitempty"`
+
+	// Config is the substituted devcontainer.json
+	Config *DevContainerConfig `json:"config,omitempty"`
+
+	// Build is the image that would be built or used
+	Build *PlanBuild `json:"build,omitempty"`
+
+	// Features are the features in install order
+	Features []PlanFeature `json:"features,omitempty"`
+
+	// Container are the options the dev container would be started with
+	Container *PlanContainer `json:"container,omitempty"`
+
+	// LifecycleHooks are the lifecycle hooks that would run, including the ones from features and the image
+	LifecycleHooks *PlanLifecycleHooks `json:"lifecycleHooks,omitempty"`
+
+	// Warnings are parts of the plan that couldn't be resolved
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type PlanMachine struct {
+	// ID is the id of the machine
+	ID string `json:"id,omitempty"`
+
+	// Create is true if a new machine would be created
+	Create bool `json:"create"`
+}
+
+type PlanBuild struct {
+	// Image is the image the dev container is based on
+	Image string `json:"image,omitempty"`
+
+	// Dockerfile is the path to the Dockerfile of the dev container
+	Dockerfile string `json:"dockerfile,omitempty"`
+
+	// Target is the target of the Dockerfile
+	Target string `json:"target,omitempty"`
+
+	// Args are the build args
+	Args map[string]string `json:"args,omitempty"`
+
+	// ExtendedDockerfile is the Dockerfile that extends the image with the features
+	ExtendedDockerfile string `json:"extendedDockerfile,omitempty"`
+
+	// Required is true if an image needs to be built. A prebuild image might still be used instead.
+	Required bool `json:"required"`
+}
+
+type PlanFeature struct {
+	// ID is the id of the feature as referenced in the devcontainer.json
+	ID string `json:"id"`
+
+	// Version is the version of the feature
+	Version string `json:"version,omitempty"`
+
+	// Options are the options the feature is installed with
+	Options interface{} `json:"options,omitempty"`
+}
+
+type PlanContainer struct {
+	User            string            `json:"user,omitempty"`
+	RemoteUser      string            `json:"remoteUser,omitempty"`
+	WorkspaceMount  string            `json:"workspaceMount,omitempty"`
+	WorkspaceFolder string            `json:"workspaceFolder,omitempty"`
+	Mounts          []*Mount          `json:"mounts,omitempty"`
+	RunArgs         []string          `json:"runArgs,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	CapAdd          []string          `json:"capAdd,omitempty"`
+	SecurityOpt     []string          `json:"securityOpt,omitempty"`
+	Privileged      *bool             `json:"privileged,omitempty"`
+	Init            *bool             `json:"init,omitempty"`
+	Entrypoints     []string          `json:"entrypoints,omitempty"`
+}
+
+type PlanLifecycleHooks struct {
+	Initialize    types.LifecycleHook   `json:"initializeCommand,omitempty"`
+	OnCreate      []types.LifecycleHook `json:"onCreateCommand,omitempty"`
+	UpdateContent []types.LifecycleHook `json:"updateContentCommand,omitempty"`
+	PostCreate    []types.LifecycleHook `json:"postCreateCommand,omitempty"`
+	PostStart     []types.LifecycleHook `json:"postStartCommand,omitempty"`
+	PostAttach    []types.LifecycleHook `json:"postAttachCommand,omitempty"`
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OnAutoForwardNotify          = "notify"
+	OnAutoForwardOpenBrowser     = "openBrowser"
+	OnAutoForwardOpenBrowserOnce = "openBrowserOnce"
+	OnAutoForwardOpenPreview     = "openPreview"
+	OnAutoForwardSilent          = "silent"
+	OnAutoForwardIgnore          = "ignore"
+)
+
+// GetPortAttribute returns the attributes for the given port. Keys of portsAttributes can be a port number or
+// a port range like 40000-55000, an exact port number takes precedence over a range. Ports without attributes
+// use otherPortsAttributes.
+func GetPortAttribute(portsAttributes map[string]PortAttribute, otherPortsAttributes *PortAttribute, port int) PortAttribute {
+	attribute, ok := portsAttributes[strconv.Itoa(port)]
+	if ok {
+		return withPortAttributeDefaults(attribute)
+	}
+
+	keys := make([]string, 0, len(portsAttributes))
+	for key := range portsAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		start, end, found := strings.Cut(key, "-")
+		if !found {
+			continue
+		}
+
+		startPort, err := strconv.Atoi(strings.TrimSpace(start))
+		if err != nil {
+			continue
+		}
+		endPort, err := strconv.Atoi(strings.TrimSpace(end))
+		if err != nil {
+			continue
+		}
+		if port >= startPort && port <= endPort {
+			return withPortAttributeDefaults(portsAttributes[key])
+		}
+	}
+
+	if otherPortsAttributes != nil {
+		return withPortAttributeDefaults(*otherPortsAttributes)
+	}
+
+	return withPortAttributeDefaults(PortAttribute{})
+}
+
+func withPortAttributeDefaults(attribute PortAttribute) PortAttribute {
+	if attribute.OnAutoForward == "" {
+		attribute.OnAutoForward = OnAutoForwardNotify
+	}
+
+	return attribute
+}
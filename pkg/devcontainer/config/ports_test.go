@@ -0,0 +1,21 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestGetPortAttribute(t *testing.T) {
+	portsAttributes := map[string]PortAttribute{
+		"3000":        {Label: "Frontend", OnAutoForward: OnAutoForwardOpenBrowser},
+		"3000-3999":   {Label: "Range", OnAutoForward: OnAutoForwardSilent},
+		"not-a-range": {Label: "Invalid"},
+	}
+	otherPortsAttributes := &PortAttribute{OnAutoForward: OnAutoForwardIgnore}
+
+	assert.Equal(t, GetPortAttribute(portsAttributes, otherPortsAttributes, 3000).Label, "Frontend")
+	assert.Equal(t, GetPortAttribute(portsAttributes, otherPortsAttributes, 3001).OnAutoForward, OnAutoForwardSilent)
+	assert.Equal(t, GetPortAttribute(portsAttributes, otherPortsAttributes, 8080).OnAutoForward, OnAutoForwardIgnore)
+	assert.Equal(t, GetPortAttribute(nil, nil, 8080).OnAutoForward, OnAutoForwardNotify)
+}
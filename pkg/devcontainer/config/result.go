@@ -88,6 +88,36 @@ func GetVSCodeConfiguration(mergedConfig *MergedDevContainerConfig) *VSCodeCusto
 	return retVSCodeCustomizations
 }
 
+// GetLifecycleHookOptions returns the devpod customizations for the given lifecycle hook. Options of
+// later metadata entries override earlier ones.
+func GetLifecycleHookOptions(mergedConfig *MergedDevContainerConfig, hook string) LifecycleHookOptions {
+	options := LifecycleHookOptions{}
+	if mergedConfig.Customizations == nil {
+		return options
+	}
+
+	for _, customization := range mergedConfig.Customizations["devpod"] {
+		devPod := &DevPodCustomizations{}
+		err := Convert(customization, devPod)
+		if err != nil {
+			continue
+		}
+
+		hookOptions, ok := devPod.LifecycleHooks[hook]
+		if !ok {
+			continue
+		}
+		if hookOptions.Timeout != "" {
+			options.Timeout = hookOptions.Timeout
+		}
+		if hookOptions.Retries > 0 {
+			options.Retries = hookOptions.Retries
+		}
+	}
+
+	return options
+}
+
 func contains(stack []string, k string) bool {
 	for _, s := range stack {
 		if s == k {
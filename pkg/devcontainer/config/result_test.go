@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestGetLifecycleHookOptions(t *testing.T) {
+	mergedConfig := &MergedDevContainerConfig{UpdatedConfigProperties: UpdatedConfigProperties{
+		Customizations: map[string][]interface{}{
+			"devpod": {
+				map[string]interface{}{
+					"lifecycleHooks": map[string]interface{}{
+						"postCreateCommand": map[string]interface{}{"timeout": "5m", "retries": 2},
+					},
+				},
+				map[string]interface{}{
+					"lifecycleHooks": map[string]interface{}{
+						"postCreateCommand": map[string]interface{}{"timeout": "10m"},
+					},
+				},
+			},
+		},
+	}}
+
+	assert.DeepEqual(t, GetLifecycleHookOptions(mergedConfig, PostCreateCommand), LifecycleHookOptions{Timeout: "10m", Retries: 2})
+	assert.DeepEqual(t, GetLifecycleHookOptions(mergedConfig, PostStartCommand), LifecycleHookOptions{})
+}
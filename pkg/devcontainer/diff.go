@@ -0,0 +1,186 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/metadata"
+	"github.com/loft-sh/devpod/pkg/dockerfile"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/pkg/errors"
+)
+
+func (r *runner) Diff(ctx context.Context, options provider2.CLIOptions) (*config.DiffResult, error) {
+	containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find dev container: %w", err)
+	} else if containerDetails == nil {
+		return nil, fmt.Errorf("couldn't find dev container for workspace %s, please run devpod up first", r.ID)
+	}
+
+	substitutedConfig, substitutionContext, err := r.getSubstitutedConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	if substitutedConfig.Config.ContainerID != "" && containerDetails.Config.WorkingDir != "" {
+		substitutionContext.ContainerWorkspaceFolder = containerDetails.Config.WorkingDir
+	}
+
+	imageMetadataConfig, err := metadata.GetImageMetadataFromContainer(containerDetails, substitutionContext, r.Log)
+	if err != nil {
+		return nil, err
+	} else if len(imageMetadataConfig.Config) == 0 {
+		return nil, fmt.Errorf("dev container is missing the %s label", metadata.ImageMetadataLabel)
+	}
+
+	// the last metadata entry always stems from the devcontainer.json, so we
+	// exchange it with the current one and keep base image and features as is
+	desiredMetadata := append([]*config.ImageMetadata{}, imageMetadataConfig.Config[:len(imageMetadataConfig.Config)-1]...)
+	desiredMetadata = append(desiredMetadata, metadata.DevContainerConfigToImageMetadata(substitutedConfig.Config))
+
+	currentMergedConfig, err := config.MergeConfiguration(substitutedConfig.Config, imageMetadataConfig.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge current config")
+	}
+	desiredMergedConfig, err := config.MergeConfiguration(substitutedConfig.Config, desiredMetadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge desired config")
+	}
+
+	diffs, err := config.DiffMergedConfig(currentMergedConfig, desiredMergedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the image needs to be rebuilt
+	warnings := sourceDiffWarnings(r.WorkspaceConfig)
+	if reason := skipImageDiffReason(substitutedConfig.Config, containerDetails.Config.Labels); reason != "" {
+		warnings = append(warnings, reason)
+	} else {
+		imageDiff, err := r.diffImage(ctx, substitutedConfig, substitutionContext, containerDetails.Config.Labels)
+		if err != nil {
+			return nil, err
+		} else if imageDiff == nil {
+			warnings = append(warnings, "dev container was created without build information, recreate it once to detect image changes")
+		} else if imageDiff.Current != imageDiff.Desired {
+			diffs = append(diffs, *imageDiff)
+		}
+	}
+
+	return config.NewDiffResult(diffs, warnings), nil
+}
+
+// sourceDiffWarnings returns warnings about the source the diff is calculated from. The
+// devcontainer.json is read from the agent's copy of the workspace, which for local folders
+// on remote machines is only updated by devpod up.
+func sourceDiffWarnings(workspaceInfo *provider2.AgentWorkspaceInfo) []string {
+	warnings := []string{}
+	if workspaceInfo == nil || workspaceInfo.Workspace == nil || workspaceInfo.Workspace.Source.LocalFolder == "" {
+		return warnings
+	}
+
+	local, _ := workspaceInfo.Agent.Local.Bool()
+	if !local {
+		warnings = append(warnings, "workspace runs on a remote machine, local changes that were not synced with devpod up are not detected")
+	}
+
+	return warnings
+}
+
+// skipImageDiffReason returns why image changes can't be detected for the container, or an
+// empty string if they can.
+func skipImageDiffReason(devContainerConfig *config.DevContainerConfig, labels map[string]string) string {
+	if isDockerComposeConfig(devContainerConfig) || devContainerConfig.ContainerID != "" {
+		return "image changes are not detected for docker compose or existing containers"
+	} else if labels[config.SnapshotLabel] != "" {
+		return fmt.Sprintf("dev container was restored from snapshot %s, image changes are not detected until it is recreated", labels[config.SnapshotLabel])
+	}
+
+	return ""
+}
+
+// diffImage compares the recorded prebuild hash or image of the container with the current one.
+// Returns nil if the container doesn't record this information.
+func (r *runner) diffImage(
+	ctx context.Context,
+	parsedConfig *config.SubstitutedConfig,
+	substitutionContext *config.SubstitutionContext,
+	labels map[string]string,
+) (*config.ConfigDiff, error) {
+	// image without features is used as is
+	if !isDockerFileConfig(parsedConfig.Config) && len(parsedConfig.Config.Features) == 0 {
+		if labels[config.ImageLabel] == "" {
+			return nil, nil
+		}
+
+		return &config.ConfigDiff{
+			Property: "image",
+			Action:   config.DiffActionRebuild,
+			Current:  labels[config.ImageLabel],
+			Desired:  parsedConfig.Config.Image,
+		}, nil
+	} else if labels[config.PrebuildHashLabel] == "" {
+		return nil, nil
+	}
+
+	prebuildHash, err := r.calculatePrebuildHash(ctx, parsedConfig, substitutionContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "calculate prebuild hash")
+	}
+
+	return &config.ConfigDiff{
+		Property: "prebuildHash",
+		Action:   config.DiffActionRebuild,
+		Current:  labels[config.PrebuildHashLabel],
+		Desired:  prebuildHash,
+	}, nil
+}
+
+// calculatePrebuildHash calculates the prebuild hash the same way a build would, without building
+func (r *runner) calculatePrebuildHash(
+	ctx context.Context,
+	parsedConfig *config.SubstitutedConfig,
+	substitutionContext *config.SubstitutionContext,
+) (string, error) {
+	targetArch, err := r.Driver.TargetArchitecture(ctx, r.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if !isDockerFileConfig(parsedConfig.Config) {
+		imageBuildInfo, err := r.getImageBuildInfoFromImage(ctx, substitutionContext, parsedConfig.Config.Image)
+		if err != nil {
+			return "", errors.Wrap(err, "get image build info")
+		}
+
+		return config.CalculatePrebuildHash(parsedConfig.Config, "", targetArch, config.GetContextPath(parsedConfig.Config), "", "", imageBuildInfo, r.Log)
+	}
+
+	dockerFilePath, err := r.getDockerfilePath(parsedConfig.Config)
+	if err != nil {
+		return "", err
+	}
+
+	dockerFileContent, err := os.ReadFile(dockerFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	if parsedConfig.Config.GetTarget() == "" {
+		_, modifiedDockerfileContents, err := dockerfile.EnsureDockerfileHasFinalStageName(string(dockerFileContent), config.DockerfileDefaultTarget)
+		if err != nil {
+			return "", err
+		} else if modifiedDockerfileContents != "" {
+			dockerFileContent = []byte(modifiedDockerfileContents)
+		}
+	}
+
+	imageBuildInfo, err := r.getImageBuildInfoFromDockerfile(substitutionContext, string(dockerFileContent), parsedConfig.Config.GetArgs(), parsedConfig.Config.GetTarget())
+	if err != nil {
+		return "", errors.Wrap(err, "get image build info")
+	}
+
+	return config.CalculatePrebuildHash(parsedConfig.Config, "", targetArch, config.GetContextPath(parsedConfig.Config), dockerFilePath, string(dockerFileContent), imageBuildInfo, r.Log)
+}
@@ -0,0 +1,106 @@
+package devcontainer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/types"
+	"gotest.tools/assert"
+)
+
+func TestSourceDiffWarnings(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		workspaceInfo *provider2.AgentWorkspaceInfo
+
+		expectedWarnings int
+	}{
+		{
+			name: "local folder on local agent",
+			workspaceInfo: &provider2.AgentWorkspaceInfo{
+				Workspace: &provider2.Workspace{Source: provider2.WorkspaceSource{LocalFolder: "/my/project"}},
+				Agent:     provider2.ProviderAgentConfig{Local: types.StrBool("true")},
+			},
+		},
+		{
+			name: "local folder on remote machine",
+			workspaceInfo: &provider2.AgentWorkspaceInfo{
+				Workspace: &provider2.Workspace{Source: provider2.WorkspaceSource{LocalFolder: "/my/project"}},
+			},
+			expectedWarnings: 1,
+		},
+		{
+			name: "git repository on remote machine",
+			workspaceInfo: &provider2.AgentWorkspaceInfo{
+				Workspace: &provider2.Workspace{Source: provider2.WorkspaceSource{GitRepository: "https://github.com/loft-sh/devpod"}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			warnings := sourceDiffWarnings(testCase.workspaceInfo)
+			assert.Equal(t, len(warnings), testCase.expectedWarnings)
+		})
+	}
+}
+
+func TestSkipImageDiffReason(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		config *config.DevContainerConfig
+		labels map[string]string
+
+		expectSkip bool
+	}{
+		{
+			name:   "image",
+			config: &config.DevContainerConfig{ImageContainer: config.ImageContainer{Image: "alpine"}},
+			labels: map[string]string{config.ImageLabel: "alpine"},
+		},
+		{
+			name:       "docker compose",
+			config:     &config.DevContainerConfig{ComposeContainer: config.ComposeContainer{DockerComposeFile: []string{"docker-compose.yaml"}}},
+			expectSkip: true,
+		},
+		{
+			name:       "existing container",
+			config:     &config.DevContainerConfig{RunningContainer: config.RunningContainer{ContainerID: "abc"}},
+			expectSkip: true,
+		},
+		{
+			name:       "restored from snapshot",
+			config:     &config.DevContainerConfig{ImageContainer: config.ImageContainer{Image: "alpine"}},
+			labels:     map[string]string{config.ImageLabel: "devpod-snapshot-image", config.SnapshotLabel: "my-snapshot"},
+			expectSkip: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reason := skipImageDiffReason(testCase.config, testCase.labels)
+			assert.Equal(t, reason != "", testCase.expectSkip)
+		})
+	}
+}
+
+func TestDiffImageWithoutFeatures(t *testing.T) {
+	parsedConfig := &config.SubstitutedConfig{
+		Config: &config.DevContainerConfig{ImageContainer: config.ImageContainer{Image: "alpine:3.19"}},
+	}
+
+	r := &runner{}
+	imageDiff, err := r.diffImage(context.Background(), parsedConfig, nil, map[string]string{config.ImageLabel: "alpine:3.18"})
+	assert.NilError(t, err)
+	assert.Equal(t, imageDiff.Property, "image")
+	assert.Equal(t, imageDiff.Current, "alpine:3.18")
+	assert.Equal(t, imageDiff.Desired, "alpine:3.19")
+
+	imageDiff, err = r.diffImage(context.Background(), parsedConfig, nil, map[string]string{})
+	assert.NilError(t, err)
+	assert.Assert(t, imageDiff == nil)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -52,6 +53,15 @@ type BuildInfo struct {
 }
 
 func GetExtendedBuildInfo(ctx *config.SubstitutionContext, imageBuildInfo *config.ImageBuildInfo, target string, devContainerConfig *config.SubstitutedConfig, log log.Logger, forceBuild bool) (*ExtendedBuildInfo, error) {
+	return getExtendedBuildInfo(ctx, imageBuildInfo, target, devContainerConfig, log, forceBuild, true)
+}
+
+// GetPlannedBuildInfo resolves the same build info as GetExtendedBuildInfo, but doesn't copy the features into the build context
+func GetPlannedBuildInfo(ctx *config.SubstitutionContext, imageBuildInfo *config.ImageBuildInfo, target string, devContainerConfig *config.SubstitutedConfig, log log.Logger) (*ExtendedBuildInfo, error) {
+	return getExtendedBuildInfo(ctx, imageBuildInfo, target, devContainerConfig, log, false, false)
+}
+
+func getExtendedBuildInfo(ctx *config.SubstitutionContext, imageBuildInfo *config.ImageBuildInfo, target string, devContainerConfig *config.SubstitutedConfig, log log.Logger, forceBuild, copyFeatures bool) (*ExtendedBuildInfo, error) {
 	features, err := fetchFeatures(devContainerConfig.Config, log, forceBuild)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch features")
@@ -76,7 +86,7 @@ func GetExtendedBuildInfo(ctx *config.SubstitutionContext, imageBuildInfo *confi
 	}
 
 	contextPath := config.GetContextPath(devContainerConfig.Config)
-	buildInfo, err := getFeatureBuildOptions(contextPath, imageBuildInfo, target, features)
+	buildInfo, err := getFeatureBuildOptions(contextPath, imageBuildInfo, target, features, copyFeatures)
 	if err != nil {
 		return nil, err
 	}
@@ -89,21 +99,23 @@ func GetExtendedBuildInfo(ctx *config.SubstitutionContext, imageBuildInfo *confi
 	}, nil
 }
 
-func getFeatureBuildOptions(contextPath string, imageBuildInfo *config.ImageBuildInfo, target string, features []*config.FeatureSet) (*BuildInfo, error) {
+func getFeatureBuildOptions(contextPath string, imageBuildInfo *config.ImageBuildInfo, target string, features []*config.FeatureSet, copyFeatures bool) (*BuildInfo, error) {
 	containerUser, remoteUser := findContainerUsers(imageBuildInfo.Metadata, "", imageBuildInfo.User)
 
 	// copy features
 	featureFolder := filepath.Join(contextPath, config.DevPodContextFeatureFolder)
-	err := copyFeaturesToDestination(features, featureFolder)
-	if err != nil {
-		return nil, err
-	}
+	if copyFeatures {
+		err := copyFeaturesToDestination(features, featureFolder)
+		if err != nil {
+			return nil, err
+		}
 
-	// write devcontainer-features.builtin.env, its important to have a terminating \n here as we append to that file later
-	err = os.WriteFile(filepath.Join(featureFolder, "devcontainer-features.builtin.env"), []byte(`_CONTAINER_USER=`+containerUser+`
+		// write devcontainer-features.builtin.env, its important to have a terminating \n here as we append to that file later
+		err = os.WriteFile(filepath.Join(featureFolder, "devcontainer-features.builtin.env"), []byte(`_CONTAINER_USER=`+containerUser+`
 _REMOTE_USER=`+remoteUser+"\n"), 0600)
-	if err != nil {
-		return nil, err
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// prepare dockerfile
@@ -229,31 +241,32 @@ func findContainerUsers(baseImageMetadata *config.ImageMetadataConfig, composeSe
 }
 
 func fetchFeatures(devContainerConfig *config.DevContainerConfig, log log.Logger, forceBuild bool) ([]*config.FeatureSet, error) {
-	featureSets := []*config.FeatureSet{}
-	for featureID, featureOptions := range devContainerConfig.Features {
-		featureFolder, err := ProcessFeatureID(featureID, devContainerConfig, log, forceBuild)
-		if err != nil {
-			return nil, errors.Wrap(err, "process feature "+featureID)
-		}
+	lockfile, err := LoadLockfile(devContainerConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "load lockfile")
+	}
 
-		// parse feature
-		log.Debugf("Parse dev container feature in %s", featureFolder)
-		featureConfig, err := config.ParseDevContainerFeature(featureFolder)
+	fetcher := &featureFetcher{
+		devContainerConfig: devContainerConfig,
+		lockfile:           lockfile,
+		forceBuild:         forceBuild,
+		fetched:            map[string]*config.FeatureSet{},
+		log:                log,
+	}
+	featureIDs := []string{}
+	for featureID := range devContainerConfig.Features {
+		featureIDs = append(featureIDs, featureID)
+	}
+	sort.Strings(featureIDs)
+	for _, featureID := range featureIDs {
+		_, err := fetcher.fetch(featureID, devContainerConfig.Features[featureID], nil)
 		if err != nil {
-			return nil, errors.Wrap(err, "parse feature "+featureID)
+			return nil, err
 		}
-
-		// add to return array
-		featureSets = append(featureSets, &config.FeatureSet{
-			ConfigID: NormalizeFeatureID(featureID),
-			Folder:   featureFolder,
-			Config:   featureConfig,
-			Options:  featureOptions,
-		})
 	}
 
 	// compute order here
-	featureSets, err := computeFeatureOrder(devContainerConfig, featureSets)
+	featureSets, err := computeFeatureOrder(devContainerConfig, fetcher.featureSets)
 	if err != nil {
 		return nil, errors.Wrap(err, "compute feature order")
 	}
@@ -261,6 +274,80 @@ func fetchFeatures(devContainerConfig *config.DevContainerConfig, log log.Logger
 	return featureSets, nil
 }
 
+type featureFetcher struct {
+	devContainerConfig *config.DevContainerConfig
+	lockfile           *Lockfile
+	forceBuild         bool
+
+	// fetched holds the feature sets by their id and options
+	fetched     map[string]*config.FeatureSet
+	featureSets []*config.FeatureSet
+
+	log log.Logger
+}
+
+// fetch fetches the feature and recursively all features it depends on. Features with the same id and options are only fetched once.
+func (f *featureFetcher) fetch(featureID string, featureOptions interface{}, requiredBy []string) (*config.FeatureSet, error) {
+	featureFolder, err := ProcessFeatureID(featureID, f.devContainerConfig, f.lockfile, f.log, f.forceBuild)
+	if err != nil {
+		return nil, errors.Wrap(err, "process feature "+dependencyPath(append(requiredBy, featureID)))
+	}
+
+	// parse feature
+	f.log.Debugf("Parse dev container feature in %s", featureFolder)
+	featureConfig, err := config.ParseDevContainerFeature(featureFolder)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse feature "+dependencyPath(append(requiredBy, featureID)))
+	}
+
+	featureSet := &config.FeatureSet{
+		ConfigID: NormalizeFeatureID(featureID),
+		Folder:   featureFolder,
+		Config:   featureConfig,
+		Options:  featureOptions,
+	}
+	key, err := featureSetKey(featureSet)
+	if err != nil {
+		return nil, err
+	} else if existing, ok := f.fetched[key]; ok {
+		return existing, nil
+	}
+	f.fetched[key] = featureSet
+	f.featureSets = append(f.featureSets, featureSet)
+
+	// fetch hard dependencies
+	dependencyIDs := []string{}
+	for dependencyID := range featureConfig.DependsOn {
+		dependencyIDs = append(dependencyIDs, dependencyID)
+	}
+	sort.Strings(dependencyIDs)
+	for _, dependencyID := range dependencyIDs {
+		f.log.Debugf("Feature %s depends on %s", featureID, dependencyID)
+		dependency, err := f.fetch(dependencyID, featureConfig.DependsOn[dependencyID], append(requiredBy, featureID))
+		if err != nil {
+			return nil, err
+		}
+
+		featureSet.DependsOn = append(featureSet.DependsOn, dependency)
+	}
+
+	return featureSet, nil
+}
+
+// featureSetKey identifies a feature by its id and resolved options
+func featureSetKey(featureSet *config.FeatureSet) (string, error) {
+	options, err := json.Marshal(getFeatureValueObject(featureSet.Config, featureSet.Options))
+	if err != nil {
+		return "", errors.Wrapf(err, "marshal options of feature %s", featureSet.ConfigID)
+	}
+
+	return featureSet.ConfigID + " " + string(options), nil
+}
+
+func dependencyPath(featureIDs []string) string {
+	return strings.Join(featureIDs, " -> ")
+}
+
 func NormalizeFeatureID(featureID string) string {
 	ref, err := name.ParseReference(featureID)
 	if err != nil {
@@ -303,38 +390,68 @@ func computeFeatureOrder(devContainer *config.DevContainerConfig, features []*co
 	}
 
 	orderedFeatures = append(orderedFeatures, automaticOrder...)
+
+	// the override order cannot break hard dependencies
+	installed := map[*config.FeatureSet]bool{}
+	for _, feature := range orderedFeatures {
+		for _, dependency := range feature.DependsOn {
+			if !installed[dependency] {
+				return nil, fmt.Errorf("overrideFeatureInstallOrder installs %s before %s, but it depends on it", feature.ConfigID, dependency.ConfigID)
+			}
+		}
+
+		installed[feature] = true
+	}
+
 	return orderedFeatures, nil
 }
 
 func computeAutomaticFeatureOrder(features []*config.FeatureSet) ([]*config.FeatureSet, error) {
-	g := graph.NewGraph[*config.FeatureSet](graph.NewNode[*config.FeatureSet]("root", nil))
+	g := graph.NewGraphOf[*config.FeatureSet](graph.NewNode[*config.FeatureSet]("root", nil), "feature dependency")
 
-	// build lookup map
-	lookup := map[string]*config.FeatureSet{}
+	// build lookup maps, features that are installed with different options get their options as part of the node id
+	lookup := map[string][]*config.FeatureSet{}
+	for _, feature := range features {
+		lookup[feature.ConfigID] = append(lookup[feature.ConfigID], feature)
+	}
+	nodeIDs := map[*config.FeatureSet]string{}
 	for _, feature := range features {
-		lookup[feature.ConfigID] = feature
+		nodeIDs[feature] = feature.ConfigID
+		if len(lookup[feature.ConfigID]) > 1 {
+			key, err := featureSetKey(feature)
+			if err != nil {
+				return nil, err
+			}
+
+			nodeIDs[feature] = key
+		}
 	}
 
 	// build graph
 	for _, feature := range features {
-		_, err := g.InsertNodeAt("root", feature.ConfigID, feature)
+		_, err := g.InsertNodeAt("root", nodeIDs[feature], feature)
 		if err != nil {
 			return nil, err
 		}
-
-		// add edges
-		for _, installAfter := range feature.Config.InstallsAfter {
-			installAfterFeature, ok := lookup[installAfter]
-			if !ok {
-				continue
-			}
-
-			// add an edge from feature to installAfterFeature
-			_, err = g.InsertNodeAt(feature.ConfigID, installAfter, installAfterFeature)
+	}
+	for _, feature := range features {
+		// add an edge from feature to each feature it depends on
+		for _, dependency := range feature.DependsOn {
+			err := g.AddEdge(nodeIDs[feature], nodeIDs[dependency])
 			if err != nil {
 				return nil, err
 			}
 		}
+
+		// add an edge from feature to installAfterFeature
+		for _, installAfter := range feature.Config.InstallsAfter {
+			for _, installAfterFeature := range lookup[installAfter] {
+				err := g.AddEdge(nodeIDs[feature], nodeIDs[installAfterFeature])
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 
 	// now remove node after node
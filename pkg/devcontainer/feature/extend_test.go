@@ -0,0 +1,79 @@
+package feature
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestFetchFeaturesDependsOn(t *testing.T) {
+	dir := t.TempDir()
+	writeFeature(t, dir, "app", `{"id": "app", "dependsOn": {"./runtime": {"version": "2"}}}`)
+	writeFeature(t, dir, "runtime", `{"id": "runtime", "options": {"version": {"type": "string", "default": "1"}}, "dependsOn": {"./base": {}}}`)
+	writeFeature(t, dir, "base", `{"id": "base"}`)
+
+	featureSets, err := fetchFeatures(devContainerConfigWithFeatures(dir, map[string]interface{}{
+		"./app":     map[string]interface{}{},
+		"./runtime": map[string]interface{}{"version": "2"},
+		"./base":    map[string]interface{}{},
+	}), log.Discard, false)
+	assert.NilError(t, err)
+
+	// runtime with the same options as the dependency of app is only installed once
+	assert.DeepEqual(t, featureIDs(featureSets), []string{"./base", "./runtime", "./app"})
+
+	featureSets, err = fetchFeatures(devContainerConfigWithFeatures(dir, map[string]interface{}{
+		"./app":     map[string]interface{}{},
+		"./runtime": map[string]interface{}{"version": "3"},
+	}), log.Discard, false)
+	assert.NilError(t, err)
+
+	// runtime with different options is installed twice, dependencies are always installed first
+	assert.Equal(t, len(featureSets), 4)
+	installed := map[*config.FeatureSet]bool{}
+	for _, featureSet := range featureSets {
+		for _, dependency := range featureSet.DependsOn {
+			assert.Assert(t, installed[dependency], "%s installed before %s", featureSet.ConfigID, dependency.ConfigID)
+		}
+		installed[featureSet] = true
+	}
+}
+
+func TestFetchFeaturesDependsOnCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFeature(t, dir, "a", `{"id": "a", "dependsOn": {"./b": {}}}`)
+	writeFeature(t, dir, "b", `{"id": "b", "dependsOn": {"./a": {}}}`)
+
+	_, err := fetchFeatures(devContainerConfigWithFeatures(dir, map[string]interface{}{
+		"./a": map[string]interface{}{},
+	}), log.Discard, false)
+	assert.ErrorContains(t, err, "cyclic feature dependency found")
+}
+
+func writeFeature(t *testing.T, dir, id, featureJSON string) {
+	featureDir := filepath.Join(dir, id)
+	assert.NilError(t, os.MkdirAll(featureDir, 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(featureDir, config.DEVCONTAINER_FEATURE_FILE_NAME), []byte(featureJSON), 0644))
+}
+
+func devContainerConfigWithFeatures(dir string, features map[string]interface{}) *config.DevContainerConfig {
+	return &config.DevContainerConfig{
+		DevContainerConfigBase: config.DevContainerConfigBase{
+			Features: features,
+		},
+		Origin: filepath.Join(dir, "devcontainer.json"),
+	}
+}
+
+func featureIDs(featureSets []*config.FeatureSet) []string {
+	ids := []string{}
+	for _, featureSet := range featureSets {
+		ids = append(ids, featureSet.ConfigID)
+	}
+
+	return ids
+}
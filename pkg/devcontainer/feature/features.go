@@ -91,23 +91,43 @@ func escapeQuotesForShell(str string) string {
 	return strings.ReplaceAll(str, "'", `'\''`)
 }
 
-func ProcessFeatureID(id string, devContainerConfig *config.DevContainerConfig, log log.Logger, forceBuild bool) (string, error) {
-	if strings.HasPrefix(id, "https://") || strings.HasPrefix(id, "http://") {
+func ProcessFeatureID(id string, devContainerConfig *config.DevContainerConfig, lockfile *Lockfile, log log.Logger, forceBuild bool) (string, error) {
+	var locked *LockedFeature
+	if lockfile != nil {
+		locked = lockfile.Features[id]
+	}
+
+	if isTarballFeature(id) {
 		log.Debugf("Process url feature")
-		return processDirectTarFeature(id, config.GetDevPodCustomizations(devContainerConfig).FeatureDownloadHTTPHeaders, log, forceBuild)
-	} else if strings.HasPrefix(id, "./") || strings.HasPrefix(id, "../") {
+		return processDirectTarFeature(id, config.GetDevPodCustomizations(devContainerConfig).FeatureDownloadHTTPHeaders, locked, log, forceBuild)
+	} else if isLocalFeature(id) {
 		log.Debugf("Process local feature")
 		return filepath.Abs(path.Join(filepath.ToSlash(filepath.Dir(devContainerConfig.Origin)), id))
 	}
 
 	// get oci feature
 	log.Debugf("Process OCI feature")
-	return processOCIFeature(id, log)
+	return processOCIFeature(id, locked, log)
+}
+
+func isTarballFeature(id string) bool {
+	return strings.HasPrefix(id, "https://") || strings.HasPrefix(id, "http://")
 }
 
-func processOCIFeature(id string, log log.Logger) (string, error) {
+func isLocalFeature(id string) bool {
+	return strings.HasPrefix(id, "./") || strings.HasPrefix(id, "../")
+}
+
+func processOCIFeature(id string, locked *LockedFeature, log log.Logger) (string, error) {
+	// locked features are pulled by digest
+	pullID := id
+	if locked != nil && locked.Resolved != "" {
+		log.Debugf("Use locked feature %s for %s", locked.Resolved, id)
+		pullID = locked.Resolved
+	}
+
 	// feature already exists?
-	featureFolder := getFeaturesTempFolder(id)
+	featureFolder := getFeaturesTempFolder(pullID)
 	featureExtractedFolder := filepath.Join(featureFolder, "extracted")
 	_, err := os.Stat(featureExtractedFolder)
 	if err == nil {
@@ -121,7 +141,7 @@ func processOCIFeature(id string, log log.Logger) (string, error) {
 		}
 	}
 
-	ref, err := name.ParseReference(id)
+	ref, err := name.ParseReference(pullID)
 	if err != nil {
 		return "", err
 	}
@@ -131,6 +151,18 @@ func processOCIFeature(id string, log log.Logger) (string, error) {
 		return "", err
 	}
 
+	if locked != nil && locked.Integrity != "" {
+		digest, err := img.Digest()
+		if err != nil {
+			return "", err
+		}
+
+		err = verifyIntegrity(id, locked.Integrity, digest.String())
+		if err != nil {
+			return "", err
+		}
+	}
+
 	destFile := filepath.Join(featureFolder, "feature.tgz")
 	err = downloadLayer(img, id, destFile, log)
 	if err != nil {
@@ -195,7 +227,7 @@ func downloadLayer(img v1.Image, id, destFile string, log log.Logger) error {
 	return nil
 }
 
-func processDirectTarFeature(id string, httpHeaders map[string]string, log log.Logger, forceDownload bool) (string, error) {
+func processDirectTarFeature(id string, httpHeaders map[string]string, locked *LockedFeature, log log.Logger, forceDownload bool) (string, error) {
 	downloadBase := id[strings.LastIndex(id, "/"):]
 	if !directTarballRegEx.MatchString(downloadBase) {
 		return "", fmt.Errorf("expected tarball name to follow 'devcontainer-feature-<feature-id>.tgz' format.  Received '%s' ", downloadBase)
@@ -204,18 +236,41 @@ func processDirectTarFeature(id string, httpHeaders map[string]string, log log.L
 	// feature already exists?
 	featureFolder := getFeaturesTempFolder(id)
 	featureExtractedFolder := filepath.Join(featureFolder, "extracted")
+	downloadFile := filepath.Join(featureFolder, "feature.tgz")
 	_, err := os.Stat(featureExtractedFolder)
 	if err == nil && !forceDownload {
-		return featureExtractedFolder, nil
+		// reuse the cached feature only if it still matches the lockfile
+		if locked == nil || locked.Integrity == "" {
+			return featureExtractedFolder, nil
+		}
+
+		integrity, err := fileIntegrity(downloadFile)
+		if err == nil && integrity == locked.Integrity {
+			return featureExtractedFolder, nil
+		}
+
+		log.Debugf("Cached feature %s doesn't match the lockfile, downloading it again", id)
+		_ = os.RemoveAll(featureExtractedFolder)
 	}
 
 	// download feature tarball
-	downloadFile := filepath.Join(featureFolder, "feature.tgz")
 	err = downloadFeatureFromURL(id, downloadFile, httpHeaders, log)
 	if err != nil {
 		return "", err
 	}
 
+	if locked != nil && locked.Integrity != "" {
+		integrity, err := fileIntegrity(downloadFile)
+		if err != nil {
+			return "", err
+		}
+
+		err = verifyIntegrity(id, locked.Integrity, integrity)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// extract file
 	file, err := os.Open(downloadFile)
 	if err != nil {
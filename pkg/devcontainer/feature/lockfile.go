@@ -0,0 +1,227 @@
+package feature
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	LockfileName       = "devcontainer-lock.json"
+	HiddenLockfileName = ".devcontainer-lock.json"
+)
+
+// Lockfile pins the features of a devcontainer.json, see https://github.com/devcontainers/spec/blob/main/docs/specs/devcontainer-lockfile.md
+type Lockfile struct {
+	Features map[string]*LockedFeature `json:"features"`
+}
+
+type LockedFeature struct {
+	// Version is the version of the feature from its devcontainer-feature.json
+	Version string `json:"version"`
+
+	// Resolved is the immutable reference of the feature, for OCI features the reference by digest
+	Resolved string `json:"resolved"`
+
+	// Integrity is the manifest digest of OCI features or the sha256 of tarball features
+	Integrity string `json:"integrity"`
+
+	// DependsOn are the ids of the features this feature depends on
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// GetLockfilePath returns the path of the lockfile next to the devcontainer.json. A .devcontainer.json
+// uses a hidden .devcontainer-lock.json.
+func GetLockfilePath(devContainerConfig *config.DevContainerConfig) string {
+	if devContainerConfig.Origin == "" {
+		return ""
+	}
+
+	lockfileName := LockfileName
+	if strings.HasPrefix(filepath.Base(devContainerConfig.Origin), ".") {
+		lockfileName = HiddenLockfileName
+	}
+
+	return filepath.Join(filepath.Dir(devContainerConfig.Origin), lockfileName)
+}
+
+// LoadLockfile loads the lockfile of the devcontainer.json or returns nil if there is none
+func LoadLockfile(devContainerConfig *config.DevContainerConfig) (*Lockfile, error) {
+	lockfilePath := GetLockfilePath(devContainerConfig)
+	if lockfilePath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	lockfile := &Lockfile{}
+	err = json.Unmarshal(data, lockfile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", lockfilePath)
+	}
+
+	return lockfile, nil
+}
+
+// SaveLockfile writes the lockfile next to the devcontainer.json
+func SaveLockfile(devContainerConfig *config.DevContainerConfig, lockfile *Lockfile) (string, error) {
+	lockfilePath := GetLockfilePath(devContainerConfig)
+	if lockfilePath == "" {
+		return "", fmt.Errorf("no origin in config")
+	}
+
+	out, err := json.MarshalIndent(lockfile, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(lockfilePath, append(out, '\n'), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return lockfilePath, nil
+}
+
+// Lock resolves the features of the devcontainer.json and the features they depend on and returns the updated lockfile.
+// Features that are already locked keep their locked version unless they are part of upgrade or upgradeAll is set.
+func Lock(devContainerConfig *config.DevContainerConfig, existing *Lockfile, upgradeAll bool, upgrade []string, log log.Logger) (*Lockfile, error) {
+	upgradeIDs := map[string]bool{}
+	for _, featureID := range upgrade {
+		upgradeIDs[NormalizeFeatureID(featureID)] = true
+	}
+
+	queue := []string{}
+	for featureID := range devContainerConfig.Features {
+		queue = append(queue, featureID)
+	}
+	sort.Strings(queue)
+
+	lockfile := &Lockfile{Features: map[string]*LockedFeature{}}
+	for len(queue) > 0 {
+		featureID := queue[0]
+		queue = queue[1:]
+		if isLocalFeature(featureID) || lockfile.Features[featureID] != nil {
+			continue
+		}
+
+		if existing != nil && !upgradeAll && !upgradeIDs[NormalizeFeatureID(featureID)] {
+			locked, ok := existing.Features[featureID]
+			if ok {
+				lockfile.Features[featureID] = locked
+				queue = append(queue, locked.DependsOn...)
+				continue
+			}
+		}
+
+		log.Infof("Resolve feature %s", featureID)
+		locked, err := resolveFeature(featureID, devContainerConfig, log)
+		if err != nil {
+			return nil, errors.Wrap(err, "resolve feature "+featureID)
+		}
+
+		lockfile.Features[featureID] = locked
+		queue = append(queue, locked.DependsOn...)
+	}
+
+	return lockfile, nil
+}
+
+func resolveFeature(id string, devContainerConfig *config.DevContainerConfig, log log.Logger) (*LockedFeature, error) {
+	var (
+		featureFolder string
+		locked        *LockedFeature
+	)
+	if isTarballFeature(id) {
+		var err error
+		featureFolder, err = processDirectTarFeature(id, config.GetDevPodCustomizations(devContainerConfig).FeatureDownloadHTTPHeaders, nil, log, true)
+		if err != nil {
+			return nil, err
+		}
+
+		integrity, err := fileIntegrity(filepath.Join(getFeaturesTempFolder(id), "feature.tgz"))
+		if err != nil {
+			return nil, err
+		}
+
+		locked = &LockedFeature{
+			Resolved:  id,
+			Integrity: integrity,
+		}
+	} else {
+		ref, err := name.ParseReference(id)
+		if err != nil {
+			return nil, err
+		}
+
+		descriptor, err := remote.Get(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+		if err != nil {
+			return nil, err
+		}
+
+		locked = &LockedFeature{
+			Resolved:  ref.Context().Digest(descriptor.Digest.String()).String(),
+			Integrity: descriptor.Digest.String(),
+		}
+		featureFolder, err = processOCIFeature(id, locked, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	featureConfig, err := config.ParseDevContainerFeature(featureFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	locked.Version = featureConfig.Version
+	for dependencyID := range featureConfig.DependsOn {
+		locked.DependsOn = append(locked.DependsOn, dependencyID)
+	}
+	sort.Strings(locked.DependsOn)
+	return locked, nil
+}
+
+func verifyIntegrity(id, expected, actual string) error {
+	if expected != actual {
+		return fmt.Errorf("integrity check of feature %s failed: expected %s, got %s. Run 'devpod features upgrade' if the feature was changed on purpose", id, expected, actual)
+	}
+
+	return nil
+}
+
+func fileIntegrity(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
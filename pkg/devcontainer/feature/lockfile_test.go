@@ -0,0 +1,78 @@
+package feature
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestGetLockfilePath(t *testing.T) {
+	assert.Equal(t, GetLockfilePath(&config.DevContainerConfig{Origin: "/repo/.devcontainer/devcontainer.json"}), "/repo/.devcontainer/devcontainer-lock.json")
+	assert.Equal(t, GetLockfilePath(&config.DevContainerConfig{Origin: "/repo/.devcontainer.json"}), "/repo/.devcontainer-lock.json")
+	assert.Equal(t, GetLockfilePath(&config.DevContainerConfig{}), "")
+}
+
+func TestLockTarballFeature(t *testing.T) {
+	version := "1.0.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(featureTarball(t, version))
+	}))
+	defer server.Close()
+
+	// features are cached in the temp dir
+	t.Setenv("TMPDIR", t.TempDir())
+
+	featureID := server.URL + "/devcontainer-feature-hello.tgz"
+	devContainerConfig := &config.DevContainerConfig{
+		DevContainerConfigBase: config.DevContainerConfigBase{
+			Features: map[string]interface{}{
+				featureID: map[string]interface{}{},
+			},
+		},
+		Origin: filepath.Join(t.TempDir(), "devcontainer.json"),
+	}
+
+	lockfile, err := Lock(devContainerConfig, nil, false, nil, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, lockfile.Features[featureID].Version, "1.0.0")
+	assert.Assert(t, strings.HasPrefix(lockfile.Features[featureID].Integrity, "sha256:"))
+
+	// a changed tarball must not pass the integrity check
+	version = "1.0.1"
+	_, err = ProcessFeatureID(featureID, devContainerConfig, lockfile, log.Discard, true)
+	assert.ErrorContains(t, err, "integrity check of feature")
+
+	// upgrading updates the lockfile
+	lockfile, err = Lock(devContainerConfig, lockfile, true, nil, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, lockfile.Features[featureID].Version, "1.0.1")
+}
+
+func featureTarball(t *testing.T, version string) []byte {
+	content := []byte(`{"id": "hello", "version": "` + version + `"}`)
+
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name: config.DEVCONTAINER_FEATURE_FILE_NAME,
+		Mode: 0644,
+		Size: int64(len(content)),
+	})
+	assert.NilError(t, err)
+	_, err = tarWriter.Write(content)
+	assert.NilError(t, err)
+	assert.NilError(t, tarWriter.Close())
+	assert.NilError(t, gzipWriter.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,100 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/metadata"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/driver/host"
+	"github.com/pkg/errors"
+)
+
+// runsOnHost returns true if the driver runs the devcontainer directly on the host
+func (r *runner) runsOnHost() bool {
+	hostDriver, ok := r.Driver.(driver.HostDriver)
+	return ok && hostDriver.RunsOnHost()
+}
+
+// runHost sets up the workspace on the host. Nothing is built, the parts of the devcontainer.json that
+// need a container are reported and the rest is applied like for a container.
+func (r *runner) runHost(
+	ctx context.Context,
+	parsedConfig *config.SubstitutedConfig,
+	substitutionContext *config.SubstitutionContext,
+	options UpOptions,
+	timeout time.Duration,
+) (*config.Result, error) {
+	if options.FromSnapshot != "" {
+		return nil, fmt.Errorf("snapshots are not supported when the workspace runs on the host")
+	}
+
+	currentUser, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+	unsupported := host.UnsupportedConfig(parsedConfig.Config, currentUser.Username)
+	if len(unsupported) > 0 {
+		r.Log.Warnf("The workspace runs on the host, the following parts of the devcontainer.json are not applied:\n  - %s", strings.Join(unsupported, "\n  - "))
+	}
+
+	imageMetadataConfig, err := metadata.GetDevContainerMetadata(substitutionContext, &config.ImageMetadataConfig{}, parsedConfig, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "get dev container metadata")
+	}
+	mergedConfig, err := config.MergeConfiguration(parsedConfig.Config, imageMetadataConfig.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge config")
+	}
+	mergedConfig.ContainerUser = currentUser.Username
+	mergedConfig.RemoteUser = currentUser.Username
+
+	containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find dev container: %w", err)
+	}
+	if containerDetails != nil && options.Recreate {
+		err = r.Driver.DeleteDevContainer(ctx, r.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete devcontainer")
+		}
+		containerDetails = nil
+	}
+
+	if containerDetails == nil {
+		err = r.validateHostRequirements(ctx, parsedConfig.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		uid := ""
+		if r.WorkspaceConfig != nil && r.WorkspaceConfig.Workspace != nil {
+			uid = r.WorkspaceConfig.Workspace.UID
+		}
+		err = r.Driver.RunDevContainer(ctx, r.ID, &driver.RunOptions{
+			UID:    uid,
+			User:   currentUser.Username,
+			Env:    r.addExtraEnvVars(mergedConfig.ContainerEnv),
+			Labels: []string{config.UserLabel + "=" + currentUser.Username},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "start dev container")
+		}
+	} else if strings.ToLower(containerDetails.State.Status) != "running" {
+		err = r.Driver.StartDevContainer(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	containerDetails, err = r.Driver.FindDevContainer(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find dev container: %w", err)
+	}
+
+	return r.setupContainer(ctx, parsedConfig.Raw, containerDetails, mergedConfig, substitutionContext, timeout)
+}
@@ -0,0 +1,171 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/devpod/pkg/devcontainer/metadata"
+	"github.com/loft-sh/devpod/pkg/dockerfile"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"github.com/pkg/errors"
+)
+
+// Plan resolves what devpod up would build and start for the given workspace without creating anything.
+// No driver is used, images are inspected directly in their registries.
+func Plan(ctx context.Context, workspaceConfig *provider2.AgentWorkspaceInfo, options provider2.CLIOptions, log log.Logger) (*config.Plan, error) {
+	r := &runner{
+		WorkspaceConfig:      workspaceConfig,
+		LocalWorkspaceFolder: workspaceConfig.ContentFolder,
+		ID:                   GetRunnerIDFromWorkspace(workspaceConfig.Workspace),
+		DryRun:               true,
+		Log:                  log,
+	}
+
+	return r.plan(ctx, options)
+}
+
+func (r *runner) plan(ctx context.Context, options provider2.CLIOptions) (*config.Plan, error) {
+	substitutedConfig, substitutionContext, err := r.getSubstitutedConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &config.Plan{
+		Config: substitutedConfig.Config,
+	}
+	if substitutedConfig.Config.ContainerID != "" || isDockerComposeConfig(substitutedConfig.Config) {
+		if substitutedConfig.Config.ContainerID != "" {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("workspace uses the existing container %s, nothing would be built", substitutedConfig.Config.ContainerID))
+		} else {
+			plan.Warnings = append(plan.Warnings, "build and run options of docker compose services are not part of the plan")
+		}
+
+		mergedConfig, err := config.MergeConfiguration(substitutedConfig.Config, []*config.ImageMetadata{metadata.DevContainerConfigToImageMetadata(substitutedConfig.Config)})
+		if err != nil {
+			return nil, errors.Wrap(err, "merge config")
+		}
+
+		plan.LifecycleHooks = planLifecycleHooks(substitutedConfig.Config, mergedConfig)
+		return plan, nil
+	}
+
+	// resolve the image the dev container is based on
+	var (
+		imageBuildInfo    *config.ImageBuildInfo
+		imageBase         string
+		dockerfilePath    string
+		dockerfileContent string
+	)
+	plan.Build = &config.PlanBuild{}
+	if isDockerFileConfig(substitutedConfig.Config) {
+		dockerfilePath, err = r.getDockerfilePath(substitutedConfig.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := os.ReadFile(dockerfilePath)
+		if err != nil {
+			return nil, err
+		}
+		dockerfileContent = string(content)
+
+		imageBase = substitutedConfig.Config.GetTarget()
+		if imageBase == "" {
+			lastTargetName, modifiedDockerfileContents, err := dockerfile.EnsureDockerfileHasFinalStageName(dockerfileContent, config.DockerfileDefaultTarget)
+			if err != nil {
+				return nil, err
+			} else if modifiedDockerfileContents != "" {
+				dockerfileContent = modifiedDockerfileContents
+			}
+
+			imageBase = lastTargetName
+		}
+
+		imageBuildInfo, err = r.getImageBuildInfoFromDockerfile(substitutionContext, dockerfileContent, substitutedConfig.Config.GetArgs(), substitutedConfig.Config.GetTarget())
+		if err != nil {
+			return nil, errors.Wrap(err, "get image build info")
+		}
+
+		plan.Build.Image = imageBuildInfo.Dockerfile.FindBaseImage(substitutedConfig.Config.GetArgs(), substitutedConfig.Config.GetTarget())
+		plan.Build.Dockerfile = dockerfilePath
+		plan.Build.Target = substitutedConfig.Config.GetTarget()
+		plan.Build.Args = substitutedConfig.Config.GetArgs()
+		plan.Build.Required = true
+	} else {
+		imageBase = substitutedConfig.Config.Image
+		imageBuildInfo, err = r.getImageBuildInfoFromImage(ctx, substitutionContext, imageBase)
+		if err != nil {
+			return nil, errors.Wrap(err, "get image build info")
+		}
+
+		plan.Build.Image = imageBase
+	}
+
+	// resolve features in install order
+	extendedBuildInfo, err := feature.GetPlannedBuildInfo(substitutionContext, imageBuildInfo, imageBase, substitutedConfig, r.Log)
+	if err != nil {
+		return nil, errors.Wrap(err, "get extended build info")
+	}
+	for _, featureSet := range extendedBuildInfo.Features {
+		planFeature := config.PlanFeature{
+			ID:      featureSet.ConfigID,
+			Options: featureSet.Options,
+		}
+		if featureSet.Config != nil {
+			planFeature.Version = featureSet.Config.Version
+		}
+
+		plan.Features = append(plan.Features, planFeature)
+	}
+	if extendedBuildInfo.FeaturesBuildInfo != nil {
+		_, plan.Build.ExtendedDockerfile = r.extendedDockerfile(extendedBuildInfo.FeaturesBuildInfo, dockerfilePath, dockerfileContent)
+		plan.Build.Required = true
+	}
+
+	// resolve how the container would be started
+	mergedConfig, err := config.MergeConfiguration(substitutedConfig.Config, extendedBuildInfo.MetadataConfig.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge config")
+	}
+
+	user := imageBuildInfo.User
+	if mergedConfig.ContainerUser != "" {
+		user = mergedConfig.ContainerUser
+	}
+	env := map[string]string{}
+	for k, v := range mergedConfig.ContainerEnv {
+		env[k] = v
+	}
+
+	plan.Container = &config.PlanContainer{
+		User:            user,
+		RemoteUser:      mergedConfig.RemoteUser,
+		WorkspaceMount:  substitutionContext.WorkspaceMount,
+		WorkspaceFolder: substitutionContext.ContainerWorkspaceFolder,
+		Mounts:          mergedConfig.Mounts,
+		RunArgs:         substitutedConfig.Config.RunArgs,
+		Env:             r.addExtraEnvVars(env),
+		CapAdd:          mergedConfig.CapAdd,
+		SecurityOpt:     mergedConfig.SecurityOpt,
+		Privileged:      mergedConfig.Privileged,
+		Init:            mergedConfig.Init,
+		Entrypoints:     mergedConfig.Entrypoints,
+	}
+	plan.LifecycleHooks = planLifecycleHooks(substitutedConfig.Config, mergedConfig)
+	return plan, nil
+}
+
+func planLifecycleHooks(parsedConfig *config.DevContainerConfig, mergedConfig *config.MergedDevContainerConfig) *config.PlanLifecycleHooks {
+	return &config.PlanLifecycleHooks{
+		Initialize:    parsedConfig.InitializeCommand,
+		OnCreate:      mergedConfig.OnCreateCommands,
+		UpdateContent: mergedConfig.UpdateContentCommands,
+		PostCreate:    mergedConfig.PostCreateCommands,
+		PostStart:     mergedConfig.PostStartCommands,
+		PostAttach:    mergedConfig.PostAttachCommands,
+	}
+}
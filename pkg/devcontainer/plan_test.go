@@ -0,0 +1,54 @@
+package devcontainer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestPlanDockerCompose(t *testing.T) {
+	contentFolder := t.TempDir()
+	err := os.WriteFile(filepath.Join(contentFolder, ".devcontainer.json"), []byte(`{
+  "dockerComposeFile": "docker-compose.yaml",
+  "service": "app",
+  "postCreateCommand": "echo created"
+}`), 0o600)
+	assert.NilError(t, err)
+
+	plan, err := Plan(context.Background(), &provider2.AgentWorkspaceInfo{
+		Workspace: &provider2.Workspace{
+			ID:     "test",
+			Source: provider2.WorkspaceSource{LocalFolder: contentFolder},
+		},
+		ContentFolder: contentFolder,
+	}, provider2.CLIOptions{}, log.Discard)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.Build == nil)
+	assert.Equal(t, plan.Config.Service, "app")
+	assert.Equal(t, len(plan.LifecycleHooks.PostCreate), 1)
+	assert.Equal(t, len(plan.Warnings), 1)
+}
+
+func TestPlanExistingContainer(t *testing.T) {
+	contentFolder := t.TempDir()
+	err := os.WriteFile(filepath.Join(contentFolder, ".devcontainer.json"), []byte(`{
+  "image": "alpine"
+}`), 0o600)
+	assert.NilError(t, err)
+
+	plan, err := Plan(context.Background(), &provider2.AgentWorkspaceInfo{
+		Workspace: &provider2.Workspace{
+			ID:     "test",
+			Source: provider2.WorkspaceSource{Container: "my-container"},
+		},
+		ContentFolder: contentFolder,
+	}, provider2.CLIOptions{}, log.Discard)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.Build == nil)
+	assert.Equal(t, len(plan.Warnings), 1)
+}
@@ -37,11 +37,23 @@ type Runner interface {
 		stderr io.Writer,
 	) error
 
+	Exec(ctx context.Context, options *driver.ExecOptions) error
+
 	Stop(ctx context.Context) error
 
+	StopComposeOnExit(ctx context.Context) error
+
 	Delete(ctx context.Context) error
 
-	Logs(ctx context.Context, writer io.Writer) error
+	Logs(ctx context.Context, follow bool, writer io.Writer) error
+
+	Diff(ctx context.Context, options provider2.CLIOptions) (*config.DiffResult, error)
+
+	CreateSnapshot(ctx context.Context, name string) (*driver.Snapshot, error)
+
+	ListSnapshots(ctx context.Context) ([]*driver.Snapshot, error)
+
+	DeleteSnapshot(ctx context.Context, name string) error
 }
 
 func NewRunner(
@@ -78,6 +90,9 @@ type runner struct {
 
 	ID string
 
+	// DryRun prevents the runner from writing into the workspace folder
+	DryRun bool
+
 	Log log.Logger
 }
 
@@ -108,6 +123,8 @@ func (r *runner) Up(ctx context.Context, options UpOptions, timeout time.Duratio
 	}
 
 	switch {
+	case r.runsOnHost():
+		return r.runHost(ctx, substitutedConfig, substitutionContext, options, timeout)
 	case isDockerFileConfig(substitutedConfig.Config),
 		substitutedConfig.Config.Image != "",
 		substitutedConfig.Config.ContainerID != "":
@@ -149,6 +166,17 @@ func (r *runner) runDefaultContainer(ctx context.Context, options UpOptions, sub
 	return r.runSingleContainer(ctx, substitutedConfig, substitutionContext, options, timeout)
 }
 
+// validateHostRequirements fails early if the driver's target cannot satisfy the hostRequirements
+// of the devcontainer.json
+func (r *runner) validateHostRequirements(ctx context.Context, parsedConfig *config.DevContainerConfig) error {
+	hostRequirementsDriver, ok := r.Driver.(driver.HostRequirementsDriver)
+	if !ok || parsedConfig.HostRequirements == nil {
+		return nil
+	}
+
+	return hostRequirementsDriver.ValidateHostRequirements(ctx, r.ID, parsedConfig.HostRequirements)
+}
+
 func (r *runner) Command(
 	ctx context.Context,
 	user string,
@@ -160,6 +188,21 @@ func (r *runner) Command(
 	return r.Driver.CommandDevContainer(ctx, r.ID, user, command, stdin, stdout, stderr)
 }
 
+// Exec runs the command with a pseudo terminal if one was requested and the driver supports it, otherwise
+// it runs the command like Command
+func (r *runner) Exec(ctx context.Context, options *driver.ExecOptions) error {
+	if options.TTY != nil {
+		ttyExecDriver, ok := r.Driver.(driver.TTYExecDriver)
+		if ok && ttyExecDriver.CanExecTTY() {
+			return ttyExecDriver.ExecDevContainer(ctx, r.ID, options)
+		}
+
+		r.Log.Debugf("Driver can't allocate a tty, run the command without one")
+	}
+
+	return r.Driver.CommandDevContainer(ctx, r.ID, options.User, options.Command, options.Stdin, options.Stdout, options.Stderr)
+}
+
 func (r *runner) Find(ctx context.Context) (*config.ContainerDetails, error) {
 	containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
 	if err != nil {
@@ -169,8 +212,17 @@ func (r *runner) Find(ctx context.Context) (*config.ContainerDetails, error) {
 	return containerDetails, nil
 }
 
-func (r *runner) Logs(ctx context.Context, writer io.Writer) error {
-	return r.Driver.GetDevContainerLogs(ctx, r.ID, writer, writer)
+func (r *runner) Logs(ctx context.Context, follow bool, writer io.Writer) error {
+	if !follow {
+		return r.Driver.GetDevContainerLogs(ctx, r.ID, writer, writer)
+	}
+
+	logsFollowDriver, ok := r.Driver.(driver.LogsFollowDriver)
+	if !ok {
+		return fmt.Errorf("the driver doesn't support following logs")
+	}
+
+	return logsFollowDriver.FollowDevContainerLogs(ctx, r.ID, writer, writer)
 }
 
 func isDockerFileConfig(config *config.DevContainerConfig) bool {
@@ -33,14 +33,16 @@ func (r *runner) setupContainer(
 	substitutionContext *config.SubstitutionContext,
 	timeout time.Duration,
 ) (*config.Result, error) {
-	// inject agent
-	err := agent.InjectAgent(ctx, func(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
-		return r.Driver.CommandDevContainer(ctx, r.ID, "root", command, stdin, stdout, stderr)
-	}, false, agent.ContainerDevPodHelperLocation, agent.DefaultAgentDownloadURL(), false, r.Log, timeout)
-	if err != nil {
-		return nil, errors.Wrap(err, "inject agent")
+	// inject agent, on the host the agent itself is used
+	if !r.runsOnHost() {
+		err := agent.InjectAgent(ctx, func(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+			return r.Driver.CommandDevContainer(ctx, r.ID, "root", command, stdin, stdout, stderr)
+		}, false, agent.ContainerDevPodHelperLocation, agent.DefaultAgentDownloadURL(), false, r.Log, timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "inject agent")
+		}
+		r.Log.Debugf("Injected into container")
 	}
-	r.Log.Debugf("Injected into container")
 	defer r.Log.Debugf("Done setting up container")
 
 	// compress info
@@ -72,13 +74,14 @@ func (r *runner) setupContainer(
 	}
 
 	workspaceConfig := &provider2.ContainerWorkspaceInfo{
-		IDE:              r.WorkspaceConfig.Workspace.IDE,
-		CLIOptions:       r.WorkspaceConfig.CLIOptions,
-		Dockerless:       r.WorkspaceConfig.Agent.Dockerless,
-		ContainerTimeout: r.WorkspaceConfig.Agent.ContainerTimeout,
-		Source:           r.WorkspaceConfig.Workspace.Source,
-		Agent:            r.WorkspaceConfig.Agent,
-		ContentFolder:    r.WorkspaceConfig.ContentFolder,
+		IDE:                 r.WorkspaceConfig.Workspace.IDE,
+		CLIOptions:          r.WorkspaceConfig.CLIOptions,
+		Dockerless:          r.WorkspaceConfig.Agent.Dockerless,
+		ContainerTimeout:    r.WorkspaceConfig.Agent.ContainerTimeout,
+		ShutdownGracePeriod: r.WorkspaceConfig.ShutdownGracePeriod,
+		Source:              r.WorkspaceConfig.Workspace.Source,
+		Agent:               r.WorkspaceConfig.Agent,
+		ContentFolder:       r.WorkspaceConfig.ContentFolder,
 	}
 	if crane.ShouldUse(&r.WorkspaceConfig.CLIOptions) && r.WorkspaceConfig.Workspace.Source.GitRepository != "" {
 		workspaceConfig.PullFromInsideContainer = "true"
@@ -95,6 +98,7 @@ func (r *runner) setupContainer(
 
 	// check if docker driver
 	_, isDockerDriver := r.Driver.(driver.DockerDriver)
+	runsOnHost := r.runsOnHost()
 
 	// setup container
 	r.Log.Infof("Setup container...")
@@ -105,10 +109,10 @@ func (r *runner) setupContainer(
 		compressed,
 		workspaceConfigCompressed,
 	)
-	if runtime.GOOS == "linux" || !isDockerDriver {
+	if (runtime.GOOS == "linux" || !isDockerDriver) && !runsOnHost {
 		setupCommand += " --chown-workspace"
 	}
-	if !isDockerDriver {
+	if !isDockerDriver && !runsOnHost {
 		setupCommand += " --stream-mounts"
 	}
 	if r.WorkspaceConfig.Agent.InjectGitCredentials != "false" {
@@ -3,74 +3,243 @@ package setup
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
 	"regexp"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/loft-sh/devpod/pkg/command"
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/devpod/pkg/types"
 	"github.com/loft-sh/log"
 	"github.com/sirupsen/logrus"
 )
 
-func RunLifecycleHooks(ctx context.Context, setupInfo *config.Result, log log.Logger) error {
+const LifecycleHooksStatusFile = "/var/devpod/lifecycle-hooks.json"
+
+// failed lifecycle commands are retried with an exponential backoff
+var (
+	lifecycleRetryBackoff    = time.Second
+	maxLifecycleRetryBackoff = 30 * time.Second
+)
+
+type lifecycleHook struct {
+	name     string
+	marker   string
+	commands []types.LifecycleHook
+	content  string
+	options  config.LifecycleHookOptions
+}
+
+type lifecycleHookRunner struct {
+	remoteUser      string
+	workspaceFolder string
+	remoteEnv       map[string]string
+	hooks           []lifecycleHook
+	waitFor         int
+}
+
+func newLifecycleHookRunner(ctx context.Context, setupInfo *config.Result, log log.Logger) *lifecycleHookRunner {
 	mergedConfig := setupInfo.MergedConfig
 	remoteUser := config.GetRemoteUser(setupInfo)
 	probedEnv, err := config.ProbeUserEnv(ctx, mergedConfig.UserEnvProbe, remoteUser, log)
 	if err != nil {
 		log.Errorf("failed to probe environment, this might lead to an incomplete setup of your workspace: %w", err)
 	}
-	remoteEnv := mergeRemoteEnv(mergedConfig.RemoteEnv, probedEnv, remoteUser)
 
-	workspaceFolder := setupInfo.SubstitutionContext.ContainerWorkspaceFolder
 	containerDetails := setupInfo.ContainerDetails
+	hooks := []lifecycleHook{
+		// only run once per container run
+		{name: config.OnCreateCommand, marker: "onCreateCommands", commands: mergedConfig.OnCreateCommands, content: containerDetails.Created},
+		// TODO: rerun when contents changed
+		{name: config.UpdateContentCommand, marker: "updateContentCommands", commands: mergedConfig.UpdateContentCommands, content: containerDetails.Created},
+		// only run once per container run
+		{name: config.PostCreateCommand, marker: "postCreateCommands", commands: mergedConfig.PostCreateCommands, content: containerDetails.Created},
+		// run when the container was restarted
+		{name: config.PostStartCommand, marker: "postStartCommands", commands: mergedConfig.PostStartCommands, content: containerDetails.State.StartedAt},
+		// run always when attaching to the container
+		{name: config.PostAttachCommand, marker: "postAttachCommands", commands: mergedConfig.PostAttachCommands},
+	}
 
-	// only run once per container run
-	err = run(mergedConfig.OnCreateCommands, remoteUser, workspaceFolder, remoteEnv,
-		"onCreateCommands", containerDetails.Created, log)
-	if err != nil {
-		return err
+	for i := range hooks {
+		hooks[i].options = config.GetLifecycleHookOptions(mergedConfig, hooks[i].name)
 	}
 
-	// TODO: rerun when contents changed
-	err = run(mergedConfig.UpdateContentCommands, remoteUser, workspaceFolder, remoteEnv,
-		"updateContentCommands", containerDetails.Created, log)
-	if err != nil {
-		return err
+	// without waitFor DevPod waits for all hooks, which differs from the spec default of updateContentCommand
+	// but keeps the IDE from opening in a workspace that is not set up yet
+	waitFor := len(hooks) - 1
+	if mergedConfig.WaitFor != "" {
+		index := slices.Index(config.LifecycleHookNames, mergedConfig.WaitFor)
+		if index == -1 {
+			log.Warnf("Unknown waitFor %s, waiting for all lifecycle hooks", mergedConfig.WaitFor)
+		} else {
+			waitFor = index
+		}
 	}
 
-	// only run once per container run
-	err = run(mergedConfig.PostCreateCommands, remoteUser, workspaceFolder, remoteEnv,
-		"postCreateCommands", containerDetails.Created, log)
-	if err != nil {
-		return err
+	return &lifecycleHookRunner{
+		remoteUser:      remoteUser,
+		workspaceFolder: setupInfo.SubstitutionContext.ContainerWorkspaceFolder,
+		remoteEnv:       mergeRemoteEnv(mergedConfig.RemoteEnv, probedEnv, remoteUser),
+		hooks:           hooks,
+		waitFor:         waitFor,
 	}
+}
 
-	// run when the container was restarted
-	err = run(mergedConfig.PostStartCommands, remoteUser, workspaceFolder, remoteEnv,
-		"postStartCommands", containerDetails.State.StartedAt, log)
-	if err != nil {
-		return err
+func (r *lifecycleHookRunner) run(ctx context.Context, hook lifecycleHook, log log.Logger) error {
+	return run(ctx, hook.commands, r.remoteUser, r.workspaceFolder, r.remoteEnv, hook.marker, hook.content, hook.options, log)
+}
+
+// backgroundHooks returns the hooks that run after the hook named in waitFor
+func (r *lifecycleHookRunner) backgroundHooks() []lifecycleHook {
+	hooks := []lifecycleHook{}
+	for _, hook := range r.hooks[r.waitFor+1:] {
+		if len(hook.commands) > 0 {
+			hooks = append(hooks, hook)
+		}
 	}
 
-	// run always when attaching to the container
-	err = run(mergedConfig.PostAttachCommands, remoteUser, workspaceFolder, remoteEnv,
-		"postAttachCommands", "", log)
-	if err != nil {
-		return err
+	return hooks
+}
+
+// RunLifecycleHooks runs the lifecycle hooks up to and including the hook named in waitFor and returns true if
+// the remaining hooks should be run in the background via RunBackgroundLifecycleHooks
+func RunLifecycleHooks(ctx context.Context, setupInfo *config.Result, log log.Logger) (bool, error) {
+	runner := newLifecycleHookRunner(ctx, setupInfo, log)
+	for _, hook := range runner.hooks[:runner.waitFor+1] {
+		err := runner.run(ctx, hook, log)
+		if err != nil {
+			return false, err
+		}
 	}
 
+	// remove the status of previous background hooks
+	backgroundHooks := runner.backgroundHooks()
+	if len(backgroundHooks) == 0 {
+		_ = os.Remove(hostdir.Path(LifecycleHooksStatusFile))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RunBackgroundLifecycleHooks runs the lifecycle hooks after the hook named in waitFor and keeps track of
+// their progress in the LifecycleHooksStatusFile
+func RunBackgroundLifecycleHooks(ctx context.Context, setupInfo *config.Result, log log.Logger) error {
+	runner := newLifecycleHookRunner(ctx, setupInfo, log)
+	backgroundHooks := runner.backgroundHooks()
+	if len(backgroundHooks) == 0 {
+		return nil
+	}
+
+	status := &config.LifecycleHooksStatus{
+		State:     config.LifecycleHooksStateRunning,
+		StartedAt: time.Now().Format(time.RFC3339),
+	}
+	for _, hook := range backgroundHooks {
+		status.Hooks = append(status.Hooks, hook.name)
+	}
+
+	for _, hook := range backgroundHooks {
+		status.Hook = hook.name
+		writeLifecycleHooksStatus(status, log)
+
+		log.Infof("Run %s in the background", hook.name)
+		err := runner.run(ctx, hook, log)
+		if err != nil {
+			status.State = config.LifecycleHooksStateFailed
+			status.Error = err.Error()
+			status.FinishedAt = time.Now().Format(time.RFC3339)
+			writeLifecycleHooksStatus(status, log)
+			return fmt.Errorf("run %s: %w", hook.name, err)
+		}
+	}
+
+	status.State = config.LifecycleHooksStateDone
+	status.Hook = ""
+	status.FinishedAt = time.Now().Format(time.RFC3339)
+	writeLifecycleHooksStatus(status, log)
+	log.Donef("Successfully ran lifecycle hooks in the background")
 	return nil
 }
 
-func run(commands []types.LifecycleHook, remoteUser, dir string, remoteEnv map[string]string, name, content string, log log.Logger) error {
+// RunLifecycleHook runs a single lifecycle hook in a running container. Hooks that already ran in the container
+// are skipped unless force is set. If only is set, just the named command of an object-form hook runs regardless
+// of the marker of the hook, which is left untouched.
+func RunLifecycleHook(ctx context.Context, setupInfo *config.Result, name string, force bool, only string, log log.Logger) error {
+	runner := newLifecycleHookRunner(ctx, setupInfo, log)
+	index := slices.IndexFunc(runner.hooks, func(hook lifecycleHook) bool { return hook.name == name })
+	if index == -1 {
+		return fmt.Errorf("unknown lifecycle hook %s, must be one of %s", name, strings.Join(config.LifecycleHookNames, ", "))
+	}
+
+	hook := runner.hooks[index]
+	if only != "" {
+		commands := []types.LifecycleHook{}
+		for _, cmd := range hook.commands {
+			if c, ok := cmd[only]; ok {
+				commands = append(commands, types.LifecycleHook{only: c})
+			}
+		}
+		if len(commands) == 0 {
+			return fmt.Errorf("%s has no command named %s", name, only)
+		}
+
+		hook.commands = commands
+	}
+	if len(hook.commands) == 0 {
+		log.Infof("No %s defined", name)
+		return nil
+	}
+
+	if !force && only == "" && hook.content != "" {
+		ran, err := markerFileMatches(hook.marker, hook.content)
+		if err != nil {
+			return err
+		} else if ran {
+			log.Infof("%s already ran in this container, use --force to run it again", name)
+			return nil
+		}
+	}
+	if only != "" {
+		hook.content = ""
+	} else if force {
+		err := removeMarkerFile(hook.marker)
+		if err != nil {
+			return fmt.Errorf("remove marker of %s: %w", name, err)
+		}
+	}
+
+	return runner.run(ctx, hook, log)
+}
+
+func writeLifecycleHooksStatus(status *config.LifecycleHooksStatus, log log.Logger) {
+	out, err := json.Marshal(status)
+	if err != nil {
+		log.Warnf("Error marshal lifecycle hooks status: %v", err)
+		return
+	}
+
+	statusFile := hostdir.Path(LifecycleHooksStatusFile)
+	_ = os.MkdirAll(filepath.Dir(statusFile), 0777)
+	err = os.WriteFile(statusFile, out, 0644)
+	if err != nil {
+		log.Warnf("Error write lifecycle hooks status to %s: %v", statusFile, err)
+	}
+}
+
+func run(ctx context.Context, commands []types.LifecycleHook, remoteUser, dir string, remoteEnv map[string]string, name, content string, options config.LifecycleHookOptions, log log.Logger) error {
 	if len(commands) == 0 {
 		return nil
 	}
@@ -85,83 +254,152 @@ func run(commands []types.LifecycleHook, remoteUser, dir string, remoteEnv map[s
 		}
 	}
 
+	if options.Timeout != "" {
+		timeout, err := time.ParseDuration(options.Timeout)
+		if err != nil {
+			return fmt.Errorf("parse timeout of %s: %w", name, err)
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	remoteEnvArr := []string{}
 	for k, v := range remoteEnv {
 		remoteEnvArr = append(remoteEnvArr, k+"="+v)
 	}
 
+	currentUser, err := user.Current()
+	if err != nil {
+		return err
+	}
+
 	for _, cmd := range commands {
 		if len(cmd) == 0 {
 			continue
 		}
 
-		for k, c := range cmd {
-			log.Infof("Run command %s: %s...", k, strings.Join(c, " "))
-			currentUser, err := user.Current()
-			if err != nil {
-				return err
-			}
+		// the named commands of an object run in parallel
+		names := make([]string, 0, len(cmd))
+		for k := range cmd {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		var (
+			wg     sync.WaitGroup
+			errsMu sync.Mutex
+			errs   []error
+		)
+		for _, k := range names {
 			args := []string{}
 			if remoteUser != currentUser.Username {
-				args = append(args, "su", remoteUser, "-c", command.Quote(c))
+				args = append(args, "su", remoteUser, "-c", command.Quote(cmd[k]))
 			} else {
-				args = append(args, "sh", "-c", command.Quote(c))
+				args = append(args, "sh", "-c", command.Quote(cmd[k]))
 			}
 
-			// create command
-			cmd := exec.Command(args[0], args[1:]...)
-			cmd.Dir = dir
-			cmd.Env = os.Environ()
-			cmd.Env = append(cmd.Env, remoteEnvArr...)
+			wg.Add(1)
+			go func(k string, c []string) {
+				defer wg.Done()
 
-			// Create pipes for stdout and stderr
-			stdoutPipe, err := cmd.StdoutPipe()
-			if err != nil {
-				return fmt.Errorf("failed to get stdout pipe: %w", err)
-			}
-			stderrPipe, err := cmd.StderrPipe()
-			if err != nil {
-				return fmt.Errorf("failed to get stderr pipe: %w", err)
-			}
+				err := runLifecycleCommand(ctx, k, c, args, dir, remoteEnvArr, options.Retries, log)
+				if err != nil {
+					errsMu.Lock()
+					defer errsMu.Unlock()
+					errs = append(errs, err)
+				}
+			}(k, cmd[k])
+		}
+		wg.Wait()
 
-			// Start the command
-			if err := cmd.Start(); err != nil {
-				return fmt.Errorf("failed to start command: %w", err)
+		if len(errs) > 0 {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%s timed out after %s: %w", name, options.Timeout, errors.Join(errs...))
 			}
 
-			// Use WaitGroup to wait for both stdout and stderr processing
-			var wg sync.WaitGroup
-			wg.Add(2)
+			return errors.Join(errs...)
+		}
+	}
 
-			go func() {
-				defer wg.Done()
-				logPipeOutput(log, stdoutPipe, logrus.InfoLevel)
-			}()
+	return nil
+}
 
-			go func() {
-				defer wg.Done()
-				logPipeOutput(log, stderrPipe, logrus.ErrorLevel)
-			}()
-
-			// Wait for command to finish
-			wg.Wait()
-			err = cmd.Wait()
-			if err != nil {
-				log.Debugf("Failed running postCreateCommand lifecycle script %s: %v", cmd.Args, err)
-				return fmt.Errorf("failed to run: %s, error: %w", strings.Join(c, " "), err)
-			}
+// runLifecycleCommand runs a single command of a lifecycle hook and retries it on failure
+func runLifecycleCommand(ctx context.Context, name string, c []string, args []string, dir string, env []string, retries int, log log.Logger) error {
+	// output of named commands is prefixed, because they run in parallel
+	prefix := ""
+	if name != "" {
+		prefix = "[" + name + "] "
+	}
 
-			log.Donef("Successfully ran command %s: %s", k, strings.Join(c, " "))
+	for attempt := 0; ; attempt++ {
+		log.Infof("Run command %s: %s...", name, strings.Join(c, " "))
+		err := runLifecycleCommandOnce(ctx, args, dir, env, prefix, log)
+		if err == nil {
+			log.Donef("Successfully ran command %s: %s", name, strings.Join(c, " "))
+			return nil
+		}
+
+		log.Debugf("Failed running lifecycle script %s: %v", args, err)
+		if attempt >= retries || ctx.Err() != nil {
+			return fmt.Errorf("failed to run: %s, error: %w", strings.Join(c, " "), err)
+		}
+
+		backoff := min(lifecycleRetryBackoff<<attempt, maxLifecycleRetryBackoff)
+		log.Warnf("Command %s failed, retrying in %s (%d/%d): %v", name, backoff, attempt+1, retries, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to run: %s, error: %w", strings.Join(c, " "), err)
+		case <-time.After(backoff):
 		}
 	}
+}
 
-	return nil
+func runLifecycleCommandOnce(ctx context.Context, args []string, dir string, env []string, prefix string, log log.Logger) error {
+	// create command
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, env...)
+
+	// processes started by the command might keep the output open after it was killed
+	setProcessGroup(cmd)
+	cmd.WaitDelay = time.Second
+
+	// Create pipes for stdout and stderr
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+
+	// Use WaitGroup to wait for both stdout and stderr processing
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		logPipeOutput(log, stdoutReader, prefix, logrus.InfoLevel)
+	}()
+
+	go func() {
+		defer wg.Done()
+		logPipeOutput(log, stderrReader, prefix, logrus.ErrorLevel)
+	}()
+
+	// Wait for command to finish
+	err := cmd.Run()
+	_ = stdoutWriter.Close()
+	_ = stderrWriter.Close()
+	wg.Wait()
+	return err
 }
 
-func logPipeOutput(log log.Logger, pipe io.ReadCloser, level logrus.Level) {
+func logPipeOutput(log log.Logger, pipe io.ReadCloser, prefix string, level logrus.Level) {
 	scanner := bufio.NewScanner(pipe)
 	for scanner.Scan() {
-		line := scanner.Text()
+		line := prefix + scanner.Text()
 		if level == logrus.InfoLevel {
 			log.Info(line)
 		} else if level == logrus.ErrorLevel {
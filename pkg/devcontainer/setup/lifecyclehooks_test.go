@@ -0,0 +1,215 @@
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/hostdir"
+	"github.com/loft-sh/devpod/pkg/types"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestRunParallelCommands(t *testing.T) {
+	currentUser, err := user.Current()
+	assert.NilError(t, err)
+
+	// each command waits for the marker of the other one, so they only finish if they run in parallel and
+	// time out otherwise
+	commands := []types.LifecycleHook{{
+		"first":  {"touch first && while [ ! -f second ]; do sleep 0.1; done && exit 1"},
+		"second": {"touch second && while [ ! -f first ]; do sleep 0.1; done && exit 2"},
+	}}
+	err = run(context.Background(), commands, currentUser.Username, t.TempDir(), nil, "postCreateCommands", "", config.LifecycleHookOptions{Timeout: "30s"}, log.Discard)
+	assert.ErrorContains(t, err, "exit 1")
+	assert.ErrorContains(t, err, "exit 2")
+	assert.Assert(t, !strings.Contains(err.Error(), "timed out"), err.Error())
+}
+
+func TestRunTimeoutAndRetries(t *testing.T) {
+	currentUser, err := user.Current()
+	assert.NilError(t, err)
+
+	defer func(backoff time.Duration) { lifecycleRetryBackoff = backoff }(lifecycleRetryBackoff)
+	lifecycleRetryBackoff = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	commands := []types.LifecycleHook{{"": {"echo x >> attempts && exit 1"}}}
+	start := time.Now()
+	err = run(context.Background(), commands, currentUser.Username, dir, nil, "postCreateCommands", "", config.LifecycleHookOptions{Retries: 2}, log.Discard)
+	assert.ErrorContains(t, err, "exit 1")
+	// retries back off 100ms and 200ms
+	assert.Assert(t, time.Since(start) >= 300*time.Millisecond)
+	attempts, err := os.ReadFile(filepath.Join(dir, "attempts"))
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(string(attempts), "x"), 3)
+
+	commands = []types.LifecycleHook{{"": {"sleep 10"}}}
+	err = run(context.Background(), commands, currentUser.Username, dir, nil, "postCreateCommands", "", config.LifecycleHookOptions{Timeout: "100ms"}, log.Discard)
+	assert.ErrorContains(t, err, "timed out after 100ms")
+}
+
+func TestRunTimeoutKillsProcessGroup(t *testing.T) {
+	currentUser, err := user.Current()
+	assert.NilError(t, err)
+
+	dir := t.TempDir()
+	commands := []types.LifecycleHook{{"": {"sleep 30 & echo $! > pid; wait"}}}
+	err = run(context.Background(), commands, currentUser.Username, dir, nil, "postCreateCommands", "", config.LifecycleHookOptions{Timeout: "500ms"}, log.Discard)
+	assert.ErrorContains(t, err, "timed out after 500ms")
+
+	out, err := os.ReadFile(filepath.Join(dir, "pid"))
+	assert.NilError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	assert.NilError(t, err)
+
+	// the background process is killed together with the command, it might linger as zombie until it is reaped
+	running := func() bool {
+		stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+		if err != nil {
+			return false
+		}
+
+		fields := strings.Fields(string(stat))
+		return len(fields) > 2 && fields[2] != "Z"
+	}
+	for i := 0; i < 20 && running(); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.Assert(t, !running(), "background process %d is still running", pid)
+}
+
+func newTestSetupInfo(t *testing.T, waitFor string) (*config.Result, string) {
+	currentUser, err := user.Current()
+	assert.NilError(t, err)
+
+	t.Setenv(hostdir.Env, t.TempDir())
+	dir := t.TempDir()
+	hook := func(name string) []types.LifecycleHook {
+		return []types.LifecycleHook{{"": {"touch " + name}}}
+	}
+
+	return &config.Result{
+		MergedConfig: &config.MergedDevContainerConfig{
+			DevContainerConfigBase: config.DevContainerConfigBase{
+				RemoteUser:   currentUser.Username,
+				UserEnvProbe: "none",
+				WaitFor:      waitFor,
+			},
+			UpdatedConfigProperties: config.UpdatedConfigProperties{
+				OnCreateCommands:      hook(config.OnCreateCommand),
+				UpdateContentCommands: hook(config.UpdateContentCommand),
+				PostCreateCommands:    hook(config.PostCreateCommand),
+				PostStartCommands:     hook(config.PostStartCommand),
+			},
+		},
+		ContainerDetails: &config.ContainerDetails{
+			Created: "created",
+			State:   config.ContainerDetailsState{StartedAt: "started"},
+		},
+		SubstitutionContext: &config.SubstitutionContext{ContainerWorkspaceFolder: dir},
+	}, dir
+}
+
+func ranHooks(t *testing.T, dir string) []string {
+	hooks := []string{}
+	for _, name := range config.LifecycleHookNames {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if err == nil {
+			hooks = append(hooks, name)
+		}
+	}
+
+	return hooks
+}
+
+func TestRunLifecycleHooksWaitFor(t *testing.T) {
+	setupInfo, dir := newTestSetupInfo(t, config.UpdateContentCommand)
+
+	background, err := RunLifecycleHooks(context.Background(), setupInfo, log.Discard)
+	assert.NilError(t, err)
+	assert.Assert(t, background)
+	assert.DeepEqual(t, ranHooks(t, dir), []string{config.OnCreateCommand, config.UpdateContentCommand})
+
+	err = RunBackgroundLifecycleHooks(context.Background(), setupInfo, log.Discard)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ranHooks(t, dir), []string{config.OnCreateCommand, config.UpdateContentCommand, config.PostCreateCommand, config.PostStartCommand})
+
+	out, err := os.ReadFile(hostdir.Path(LifecycleHooksStatusFile))
+	assert.NilError(t, err)
+	status := &config.LifecycleHooksStatus{}
+	assert.NilError(t, json.Unmarshal(out, status))
+	assert.Equal(t, status.State, config.LifecycleHooksStateDone)
+	assert.DeepEqual(t, status.Hooks, []string{config.PostCreateCommand, config.PostStartCommand})
+}
+
+func TestRunLifecycleHooksWithoutWaitFor(t *testing.T) {
+	for _, waitFor := range []string{"", "unknownCommand"} {
+		setupInfo, dir := newTestSetupInfo(t, waitFor)
+
+		background, err := RunLifecycleHooks(context.Background(), setupInfo, log.Discard)
+		assert.NilError(t, err)
+		assert.Assert(t, !background)
+		assert.DeepEqual(t, ranHooks(t, dir), []string{config.OnCreateCommand, config.UpdateContentCommand, config.PostCreateCommand, config.PostStartCommand})
+	}
+}
+
+func TestRunBackgroundLifecycleHooksFailed(t *testing.T) {
+	setupInfo, _ := newTestSetupInfo(t, config.OnCreateCommand)
+	setupInfo.MergedConfig.PostCreateCommands = []types.LifecycleHook{{"": {"exit 1"}}}
+
+	err := RunBackgroundLifecycleHooks(context.Background(), setupInfo, log.Discard)
+	assert.ErrorContains(t, err, config.PostCreateCommand)
+
+	out, err := os.ReadFile(hostdir.Path(LifecycleHooksStatusFile))
+	assert.NilError(t, err)
+	status := &config.LifecycleHooksStatus{}
+	assert.NilError(t, json.Unmarshal(out, status))
+	assert.Equal(t, status.State, config.LifecycleHooksStateFailed)
+	assert.Equal(t, status.Hook, config.PostCreateCommand)
+}
+
+func TestRunLifecycleHook(t *testing.T) {
+	setupInfo, dir := newTestSetupInfo(t, "")
+	setupInfo.MergedConfig.PostCreateCommands = []types.LifecycleHook{{
+		"first":  {"echo x >> first"},
+		"second": {"echo x >> second"},
+	}}
+	count := func(name string) int {
+		out, _ := os.ReadFile(filepath.Join(dir, name))
+		return strings.Count(string(out), "x")
+	}
+
+	// runs once, afterwards the marker skips it
+	err := RunLifecycleHook(context.Background(), setupInfo, config.PostCreateCommand, false, "", log.Discard)
+	assert.NilError(t, err)
+	err = RunLifecycleHook(context.Background(), setupInfo, config.PostCreateCommand, false, "", log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, count("first"), 1)
+	assert.Equal(t, count("second"), 1)
+
+	// force reruns the whole hook
+	err = RunLifecycleHook(context.Background(), setupInfo, config.PostCreateCommand, true, "", log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, count("first"), 2)
+	assert.Equal(t, count("second"), 2)
+
+	// only runs the named command even though the hook already ran
+	err = RunLifecycleHook(context.Background(), setupInfo, config.PostCreateCommand, false, "first", log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, count("first"), 3)
+	assert.Equal(t, count("second"), 2)
+
+	err = RunLifecycleHook(context.Background(), setupInfo, config.PostCreateCommand, false, "third", log.Discard)
+	assert.ErrorContains(t, err, "has no command named third")
+	err = RunLifecycleHook(context.Background(), setupInfo, "unknownCommand", false, "", log.Discard)
+	assert.ErrorContains(t, err, "unknown lifecycle hook")
+}
@@ -0,0 +1,7 @@
+//go:build windows
+
+package setup
+
+import "os/exec"
+
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -19,6 +19,7 @@ import (
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/envfile"
 	"github.com/loft-sh/devpod/pkg/gitcredentials"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/log"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/tools/clientcmd"
@@ -29,43 +30,52 @@ const (
 	ResultLocation = "/var/run/devpod/result.json"
 )
 
-func SetupContainer(ctx context.Context, setupInfo *config.Result, extraWorkspaceEnv []string, chownProjects bool, platformOptions *devpod.PlatformOptions, tunnelClient tunnel.TunnelClient, log log.Logger) error {
+// SetupContainer sets up the container and runs the lifecycle hooks up to the hook named in waitFor. It returns
+// true if the remaining lifecycle hooks need to run in the background.
+func SetupContainer(ctx context.Context, setupInfo *config.Result, extraWorkspaceEnv []string, chownProjects bool, platformOptions *devpod.PlatformOptions, tunnelClient tunnel.TunnelClient, log log.Logger) (bool, error) {
 	// write result to ResultLocation
 	WriteResult(setupInfo, log)
 
+	// on the host, the workspace belongs to the current user and the system files are left untouched
+	onHost := hostdir.Dir() != ""
+
 	// chown user dir
-	err := ChownWorkspace(setupInfo, chownProjects, log)
-	if err != nil {
-		return errors.Wrap(err, "chown workspace")
+	if !onHost {
+		err := ChownWorkspace(setupInfo, chownProjects, log)
+		if err != nil {
+			return false, errors.Wrap(err, "chown workspace")
+		}
 	}
 
 	// patch remote env
 	log.Debugf("Patch etc environment & profile...")
-	err = PatchEtcEnvironment(setupInfo.MergedConfig, log)
+	err := PatchEtcEnvironment(setupInfo.MergedConfig, log)
 	if err != nil {
-		return errors.Wrap(err, "patch etc environment")
+		return false, errors.Wrap(err, "patch etc environment")
 	}
 	err = PatchEtcEnvironmentFlags(extraWorkspaceEnv, log)
 	if err != nil {
-		return errors.Wrap(err, "patch etc environment from flags")
+		return false, errors.Wrap(err, "patch etc environment from flags")
 	}
 
-	// patch etc profile
-	err = PatchEtcProfile()
-	if err != nil {
-		return errors.Wrap(err, "patch etc profile")
-	}
+	if !onHost {
+		// patch etc profile
+		err = PatchEtcProfile()
+		if err != nil {
+			return false, errors.Wrap(err, "patch etc profile")
+		}
 
-	// link /home/root to root if necessary
-	err = LinkRootHome(setupInfo)
-	if err != nil {
-		log.Errorf("Error linking /home/root: %v", err)
-	}
+		// link /home/root to root if necessary
+		err = LinkRootHome(setupInfo)
+		if err != nil {
+			log.Errorf("Error linking /home/root: %v", err)
+		}
 
-	// chown agent sock file
-	err = ChownAgentSock(setupInfo)
-	if err != nil {
-		return errors.Wrap(err, "chown ssh agent sock file")
+		// chown agent sock file
+		err = ChownAgentSock(setupInfo)
+		if err != nil {
+			return false, errors.Wrap(err, "chown ssh agent sock file")
+		}
 	}
 
 	// setup kube config
@@ -82,13 +92,13 @@ func SetupContainer(ctx context.Context, setupInfo *config.Result, extraWorkspac
 
 	// run commands
 	log.Debugf("Run lifecycle hooks commands...")
-	err = RunLifecycleHooks(ctx, setupInfo, log)
+	runInBackground, err := RunLifecycleHooks(ctx, setupInfo, log)
 	if err != nil {
-		return errors.Wrap(err, "lifecycle hooks")
+		return false, errors.Wrap(err, "lifecycle hooks")
 	}
 
 	log.Debugf("Done setting up environment")
-	return nil
+	return runInBackground, nil
 }
 
 func WriteResult(setupInfo *config.Result, log log.Logger) {
@@ -98,20 +108,21 @@ func WriteResult(setupInfo *config.Result, log log.Logger) {
 		return
 	}
 
-	existing, _ := os.ReadFile(ResultLocation)
+	resultLocation := hostdir.Path(ResultLocation)
+	existing, _ := os.ReadFile(resultLocation)
 	if string(rawBytes) == string(existing) {
 		return
 	}
 
-	err = os.MkdirAll(filepath.Dir(ResultLocation), 0777)
+	err = os.MkdirAll(filepath.Dir(resultLocation), 0777)
 	if err != nil {
-		log.Warnf("Error create %s: %v", filepath.Dir(ResultLocation), err)
+		log.Warnf("Error create %s: %v", filepath.Dir(resultLocation), err)
 		return
 	}
 
-	err = os.WriteFile(ResultLocation, rawBytes, 0600)
+	err = os.WriteFile(resultLocation, rawBytes, 0600)
 	if err != nil {
-		log.Warnf("Error write result to %s: %v", ResultLocation, err)
+		log.Warnf("Error write result to %s: %v", resultLocation, err)
 		return
 	}
 }
@@ -330,15 +341,13 @@ func SetupKubeConfig(ctx context.Context, setupInfo *config.Result, tunnelClient
 }
 
 func markerFileExists(markerName string, markerContent string) (bool, error) {
-	markerName = filepath.Join("/var/devpod", markerName+".marker")
-	t, err := os.ReadFile(markerName)
-	if err != nil && !os.IsNotExist(err) {
-		return false, err
-	} else if err == nil && (markerContent == "" || string(t) == markerContent) {
-		return true, nil
+	exists, err := markerFileMatches(markerName, markerContent)
+	if err != nil || exists {
+		return exists, err
 	}
 
 	// write marker
+	markerName = markerFilePath(markerName)
 	_ = os.MkdirAll(filepath.Dir(markerName), 0777)
 	err = os.WriteFile(markerName, []byte(markerContent), 0644)
 	if err != nil {
@@ -348,14 +357,39 @@ func markerFileExists(markerName string, markerContent string) (bool, error) {
 	return false, nil
 }
 
+// markerFileMatches returns true if the marker file exists with the given content without writing it
+func markerFileMatches(markerName string, markerContent string) (bool, error) {
+	t, err := os.ReadFile(markerFilePath(markerName))
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	} else if err == nil && (markerContent == "" || string(t) == markerContent) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func removeMarkerFile(markerName string) error {
+	err := os.Remove(markerFilePath(markerName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func markerFilePath(markerName string) string {
+	return hostdir.Path(filepath.Join("/var/devpod", markerName+".marker"))
+}
+
 func setupPlatformGitCredentials(userName string, platformOptions *devpod.PlatformOptions, log log.Logger) error {
 	// platform is not enabled, skip
 	if !platformOptions.Enabled {
 		return nil
 	}
 
-	// setup platform git user
-	if platformOptions.UserCredentials.GitUser != "" && platformOptions.UserCredentials.GitEmail != "" {
+	// setup platform git user, on the host the git config of the user is left untouched
+	if platformOptions.UserCredentials.GitUser != "" && platformOptions.UserCredentials.GitEmail != "" && hostdir.Dir() == "" {
 		gitUser, err := gitcredentials.GetUser(userName)
 		if err == nil && gitUser.Name == "" && gitUser.Email == "" {
 			log.Info("Setup workspace git user and email")
@@ -0,0 +1,79 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/pkg/errors"
+)
+
+var composeShutdownPollInterval = 5 * time.Second
+
+// StopComposeOnExit waits until the dev container of a docker compose workspace stopped and stops the other
+// services of the compose project afterwards. This applies the stopCompose shutdownAction from the host, the dev
+// container itself only terminates its own container once the last session disconnected.
+func (r *runner) StopComposeOnExit(ctx context.Context) error {
+	containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
+	if err != nil {
+		return errors.Wrap(err, "find dev container")
+	} else if containerDetails == nil {
+		return nil
+	}
+
+	isDockerCompose, projectName := getDockerComposeProject(containerDetails)
+	if !isDockerCompose {
+		return fmt.Errorf("dev container is not part of a docker compose project")
+	}
+
+	stopped, err := r.waitForDevContainerStop(ctx, containerDetails.ID)
+	if err != nil || !stopped {
+		return err
+	}
+
+	r.Log.Infof("Dev container stopped, stopping docker compose project %s", projectName)
+	return r.stopDockerCompose(ctx, projectName)
+}
+
+// waitForDevContainerStop polls the dev container until it stopped. Returns false if the dev container was removed.
+func (r *runner) waitForDevContainerStop(ctx context.Context, containerID string) (bool, error) {
+	ticker := time.NewTicker(composeShutdownPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+
+		containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
+		if err != nil {
+			r.Log.Debugf("Error finding dev container: %v", err)
+			continue
+		}
+
+		var stopped bool
+		containerID, stopped = composeShutdownState(containerID, containerDetails)
+		if stopped {
+			return true, nil
+		} else if containerID == "" {
+			return false, nil
+		}
+	}
+}
+
+// composeShutdownState returns the id of the dev container to keep watching and if the compose project should
+// be stopped. A removed dev container means the workspace was deleted, so there is nothing left to watch.
+// A recreated dev container is watched instead of the old one.
+func composeShutdownState(containerID string, containerDetails *config.ContainerDetails) (string, bool) {
+	if containerDetails == nil {
+		return "", false
+	} else if containerDetails.ID != containerID {
+		return containerDetails.ID, false
+	}
+
+	return containerID, strings.ToLower(containerDetails.State.Status) != "running"
+}
@@ -0,0 +1,94 @@
+package devcontainer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+type fakeDriver struct {
+	driver.Driver
+
+	containers []*config.ContainerDetails
+}
+
+func (f *fakeDriver) FindDevContainer(ctx context.Context, workspaceID string) (*config.ContainerDetails, error) {
+	if len(f.containers) == 0 {
+		return nil, nil
+	}
+
+	containerDetails := f.containers[0]
+	if len(f.containers) > 1 {
+		f.containers = f.containers[1:]
+	}
+	return containerDetails, nil
+}
+
+func container(id, status string) *config.ContainerDetails {
+	return &config.ContainerDetails{ID: id, State: config.ContainerDetailsState{Status: status}}
+}
+
+func TestComposeShutdownState(t *testing.T) {
+	containerID, stop := composeShutdownState("abc", container("abc", "running"))
+	assert.Equal(t, containerID, "abc")
+	assert.Assert(t, !stop)
+
+	containerID, stop = composeShutdownState("abc", container("abc", "exited"))
+	assert.Equal(t, containerID, "abc")
+	assert.Assert(t, stop)
+
+	containerID, stop = composeShutdownState("abc", container("def", "running"))
+	assert.Equal(t, containerID, "def")
+	assert.Assert(t, !stop)
+
+	containerID, stop = composeShutdownState("abc", nil)
+	assert.Equal(t, containerID, "")
+	assert.Assert(t, !stop)
+}
+
+func TestWaitForDevContainerStop(t *testing.T) {
+	composeShutdownPollInterval = time.Millisecond
+
+	testCases := []struct {
+		name string
+
+		containers []*config.ContainerDetails
+
+		expectedStopped bool
+	}{
+		{
+			name:            "stopped",
+			containers:      []*config.ContainerDetails{container("abc", "running"), container("abc", "running"), container("abc", "exited")},
+			expectedStopped: true,
+		},
+		{
+			name:       "removed",
+			containers: []*config.ContainerDetails{container("abc", "running"), nil},
+		},
+		{
+			name:            "recreated and stopped",
+			containers:      []*config.ContainerDetails{container("def", "running"), container("def", "exited")},
+			expectedStopped: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := &runner{Driver: &fakeDriver{containers: testCase.containers}, Log: log.Discard}
+			stopped, err := r.waitForDevContainerStop(context.Background(), "abc")
+			assert.NilError(t, err)
+			assert.Equal(t, stopped, testCase.expectedStopped)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := &runner{Driver: &fakeDriver{containers: []*config.ContainerDetails{container("abc", "running")}}, Log: log.Discard}
+	_, err := r.waitForDevContainerStop(ctx, "abc")
+	assert.Equal(t, err, context.Canceled)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,6 +54,10 @@ func (r *runner) runSingleContainer(
 	// if options.Recreate is true, and workspace is a running container, we should not rebuild
 	if options.Recreate && parsedConfig.Config.ContainerID != "" {
 		return nil, fmt.Errorf("cannot recreate container not created by DevPod")
+	} else if options.FromSnapshot != "" && parsedConfig.Config.ContainerID != "" {
+		return nil, fmt.Errorf("cannot restore a snapshot into a container not created by DevPod")
+	} else if options.FromSnapshot != "" && !options.Recreate && containerDetails != nil {
+		return nil, fmt.Errorf("workspace already has a dev container, use --recreate to restore snapshot %s", options.FromSnapshot)
 	} else if !options.Recreate && containerDetails != nil {
 		// start container if not running
 		if strings.ToLower(containerDetails.State.Status) != "running" {
@@ -91,19 +96,32 @@ func (r *runner) runSingleContainer(
 			}
 		}
 	} else {
-		// we need to build the container
-		buildInfo, err := r.build(ctx, parsedConfig, substitutionContext, provider2.BuildOptions{
-			CLIOptions: provider2.CLIOptions{
-				PrebuildRepositories: options.PrebuildRepositories,
-				ForceDockerless:      options.ForceDockerless,
-				Platform:             options.CLIOptions.Platform,
-			},
-			NoBuild:       options.NoBuild,
-			RegistryCache: options.RegistryCache,
-			ExportCache:   false,
-		})
-		if err != nil {
-			return nil, errors.Wrap(err, "build image")
+		// we need to build the container or start from a snapshot
+		var buildInfo *config.BuildInfo
+		if options.FromSnapshot != "" {
+			buildInfo, err = r.getSnapshotBuildInfo(ctx, substitutionContext, options.FromSnapshot)
+			if err != nil {
+				return nil, errors.Wrap(err, "restore snapshot")
+			}
+		} else {
+			err = r.validateHostRequirements(ctx, parsedConfig.Config)
+			if err != nil {
+				return nil, err
+			}
+
+			buildInfo, err = r.build(ctx, parsedConfig, substitutionContext, provider2.BuildOptions{
+				CLIOptions: provider2.CLIOptions{
+					PrebuildRepositories: options.PrebuildRepositories,
+					ForceDockerless:      options.ForceDockerless,
+					Platform:             options.CLIOptions.Platform,
+				},
+				NoBuild:       options.NoBuild,
+				RegistryCache: options.RegistryCache,
+				ExportCache:   false,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "build image")
+			}
 		}
 
 		// delete container on recreation
@@ -140,7 +158,7 @@ func (r *runner) runSingleContainer(
 		}
 
 		// run dev container
-		err = r.runContainer(ctx, parsedConfig, substitutionContext, mergedConfig, buildInfo)
+		err = r.runContainer(ctx, parsedConfig, substitutionContext, mergedConfig, buildInfo, options.FromSnapshot)
 		if err != nil {
 			return nil, errors.Wrap(err, "start dev container")
 		}
@@ -164,6 +182,7 @@ func (r *runner) runContainer(
 	substitutionContext *config.SubstitutionContext,
 	mergedConfig *config.MergedDevContainerConfig,
 	buildInfo *config.BuildInfo,
+	fromSnapshot string,
 ) error {
 	var err error
 
@@ -184,6 +203,28 @@ func (r *runner) runContainer(
 
 	runOptions.Env = r.addExtraEnvVars(runOptions.Env)
 
+	// add the other services of a docker compose devcontainer
+	if isDockerComposeConfig(parsedConfig.Config) {
+		err = r.addComposeServices(ctx, parsedConfig, runOptions)
+		if err != nil {
+			return fmt.Errorf("add docker compose services: %w", err)
+		}
+	}
+
+	// restore snapshot state before the container is started
+	if fromSnapshot != "" {
+		snapshotDriver, err := r.snapshotDriver()
+		if err != nil {
+			return err
+		}
+
+		err = snapshotDriver.RestoreSnapshot(ctx, r.ID, fromSnapshot, runOptions)
+		if err != nil {
+			return fmt.Errorf("restore snapshot: %w", err)
+		}
+		runOptions.Labels = append(runOptions.Labels, config.SnapshotLabel+"="+fromSnapshot)
+	}
+
 	// check if docker
 	dockerDriver, ok := r.Driver.(driver.DockerDriver)
 	if ok {
@@ -274,9 +315,11 @@ func (r *runner) getDockerlessRunOptions(
 			metadata.ImageMetadataLabel + "=" + string(marshalled),
 			config.UserLabel + "=" + buildInfo.Dockerless.User,
 		},
-		Privileged:     mergedConfig.Privileged,
-		WorkspaceMount: &workspaceMountParsed,
-		Mounts:         mounts,
+		Privileged:       mergedConfig.Privileged,
+		WorkspaceMount:   &workspaceMountParsed,
+		Mounts:           mounts,
+		HostRequirements: mergedConfig.HostRequirements,
+		ForwardPorts:     getForwardPorts(mergedConfig),
 	}, nil
 }
 
@@ -299,6 +342,10 @@ func (r *runner) getRunOptions(
 	labels := []string{
 		metadata.ImageMetadataLabel + "=" + string(marshalled),
 		config.UserLabel + "=" + buildInfo.ImageDetails.Config.User,
+		config.ImageLabel + "=" + buildInfo.ImageName,
+	}
+	if buildInfo.PrebuildHash != "" {
+		labels = append(labels, config.PrebuildHashLabel+"="+buildInfo.PrebuildHash)
 	}
 
 	user := buildInfo.ImageDetails.Config.User
@@ -312,21 +359,48 @@ func (r *runner) getRunOptions(
 	}
 
 	return &driver.RunOptions{
-		UID:            uid,
-		Image:          buildInfo.ImageName,
-		User:           user,
-		Entrypoint:     entrypoint,
-		Cmd:            cmd,
-		Env:            mergedConfig.ContainerEnv,
-		CapAdd:         mergedConfig.CapAdd,
-		Labels:         labels,
-		Privileged:     mergedConfig.Privileged,
-		WorkspaceMount: &workspaceMountParsed,
-		SecurityOpt:    mergedConfig.SecurityOpt,
-		Mounts:         mergedConfig.Mounts,
+		UID:              uid,
+		Image:            buildInfo.ImageName,
+		User:             user,
+		Entrypoint:       entrypoint,
+		Cmd:              cmd,
+		Env:              mergedConfig.ContainerEnv,
+		CapAdd:           mergedConfig.CapAdd,
+		Labels:           labels,
+		Privileged:       mergedConfig.Privileged,
+		WorkspaceMount:   &workspaceMountParsed,
+		SecurityOpt:      mergedConfig.SecurityOpt,
+		Mounts:           mergedConfig.Mounts,
+		HostRequirements: mergedConfig.HostRequirements,
+		ForwardPorts:     getForwardPorts(mergedConfig),
 	}, nil
 }
 
+// getForwardPorts returns the forwardPorts of the devcontainer itself, ports of other hosts such as
+// another docker compose service are skipped
+func getForwardPorts(mergedConfig *config.MergedDevContainerConfig) []driver.ForwardPort {
+	forwardPorts := []driver.ForwardPort{}
+	for _, forwardPort := range mergedConfig.ForwardPorts {
+		host, port, found := strings.Cut(forwardPort, ":")
+		if !found {
+			host, port = "localhost", forwardPort
+		}
+		portNumber, err := strconv.Atoi(port)
+		if err != nil || (host != "localhost" && host != "127.0.0.1") {
+			continue
+		}
+
+		attribute := config.GetPortAttribute(mergedConfig.PortsAttributes, mergedConfig.OtherPortsAttributes, portNumber)
+		forwardPorts = append(forwardPorts, driver.ForwardPort{
+			Port:     portNumber,
+			Label:    attribute.Label,
+			Protocol: attribute.Protocol,
+		})
+	}
+
+	return forwardPorts
+}
+
 // add environment variables that signals that we are in a remote container
 // (vscode compatibility) and specifically that we are using devpod.
 func (r *runner) addExtraEnvVars(env map[string]string) map[string]string {
@@ -0,0 +1,85 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/pkg/errors"
+)
+
+func (r *runner) CreateSnapshot(ctx context.Context, name string) (*driver.Snapshot, error) {
+	snapshotDriver, err := r.snapshotDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	containerDetails, err := r.Driver.FindDevContainer(ctx, r.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find dev container")
+	} else if containerDetails == nil {
+		return nil, fmt.Errorf("couldn't find dev container for workspace %s", r.ID)
+	} else if isDockerCompose, _ := getDockerComposeProject(containerDetails); isDockerCompose {
+		return nil, fmt.Errorf("snapshots are not supported for docker compose workspaces")
+	}
+
+	return snapshotDriver.CreateSnapshot(ctx, r.ID, name)
+}
+
+func (r *runner) ListSnapshots(ctx context.Context) ([]*driver.Snapshot, error) {
+	snapshotDriver, err := r.snapshotDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotDriver.ListSnapshots(ctx)
+}
+
+func (r *runner) DeleteSnapshot(ctx context.Context, name string) error {
+	snapshotDriver, err := r.snapshotDriver()
+	if err != nil {
+		return err
+	}
+
+	return snapshotDriver.DeleteSnapshot(ctx, name)
+}
+
+func (r *runner) snapshotDriver() (driver.SnapshotDriver, error) {
+	snapshotDriver, ok := r.Driver.(driver.SnapshotDriver)
+	if !ok {
+		return nil, fmt.Errorf("driver %s doesn't support snapshots", r.WorkspaceConfig.Agent.Driver)
+	}
+
+	return snapshotDriver, nil
+}
+
+// getSnapshotBuildInfo returns the build info for the image the given snapshot was taken from
+// instead of building the dev container again.
+func (r *runner) getSnapshotBuildInfo(ctx context.Context, substitutionContext *config.SubstitutionContext, name string) (*config.BuildInfo, error) {
+	snapshotDriver, err := r.snapshotDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := snapshotDriver.GetSnapshot(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "get snapshot")
+	} else if snapshot == nil {
+		return nil, fmt.Errorf("snapshot %s doesn't exist", name)
+	} else if snapshot.Image == "" {
+		return nil, fmt.Errorf("snapshot %s has no image", name)
+	}
+
+	r.Log.Infof("Using snapshot %s of workspace %s", snapshot.Name, snapshot.WorkspaceID)
+	imageBuildInfo, err := r.getImageBuildInfoFromImage(ctx, substitutionContext, snapshot.Image)
+	if err != nil {
+		return nil, errors.Wrap(err, "get image build info")
+	}
+
+	return &config.BuildInfo{
+		ImageDetails:  imageBuildInfo.ImageDetails,
+		ImageMetadata: imageBuildInfo.Metadata,
+		ImageName:     snapshot.Image,
+	}, nil
+}
@@ -67,6 +67,30 @@ func (r *DockerHelper) GPUSupportEnabled() (bool, error) {
 	return strings.Contains(string(out), "nvidia-container-runtime"), nil
 }
 
+// DockerInfo is the subset of docker info DevPod uses
+type DockerInfo struct {
+	// NCPU is the number of CPUs of the docker host
+	NCPU int `json:"NCPU,omitempty"`
+
+	// MemTotal is the total memory of the docker host in bytes
+	MemTotal int64 `json:"MemTotal,omitempty"`
+}
+
+func (r *DockerHelper) Info(ctx context.Context) (*DockerInfo, error) {
+	out, err := r.buildCmd(ctx, "info", "-f", "{{json .}}").Output()
+	if err != nil {
+		return nil, command.WrapCommandError(out, err)
+	}
+
+	info := &DockerInfo{}
+	err = json.Unmarshal(out, info)
+	if err != nil {
+		return nil, perrors.Wrap(err, "parse docker info")
+	}
+
+	return info, nil
+}
+
 func (r *DockerHelper) FindDevContainer(ctx context.Context, labels []string) (*config.ContainerDetails, error) {
 	containers, err := r.FindContainer(ctx, labels)
 	if err != nil {
@@ -87,7 +111,7 @@ func (r *DockerHelper) FindContainerByID(ctx context.Context, containerIds []str
 	// find matching container
 	for _, details := range containerDetails {
 		if strings.ToLower(details.State.Status) != "removing" {
-			details.State.Status = strings.ToLower(details.State.Status)
+			details.State.Status = normalizeContainerStatus(details.State.Status)
 			return &details, nil
 		}
 	}
@@ -95,6 +119,20 @@ func (r *DockerHelper) FindContainerByID(ctx context.Context, containerIds []str
 	return nil, nil
 }
 
+// normalizeContainerStatus maps the container states of docker compatible CLIs to the docker ones. Podman
+// reports containers that were never started as configured or initialized and stopped containers as stopped.
+func normalizeContainerStatus(status string) string {
+	status = strings.ToLower(status)
+	switch status {
+	case "configured", "initialized":
+		return "created"
+	case "stopped":
+		return "exited"
+	}
+
+	return status
+}
+
 func (r *DockerHelper) DeleteVolume(ctx context.Context, volume string) error {
 	if volume == "" {
 		return nil
@@ -338,6 +376,11 @@ func (r *DockerHelper) GetContainerLogs(ctx context.Context, id string, stdout i
 	return cmd.Run()
 }
 
+// Command returns a command for the docker CLI with the environment of the helper
+func (r *DockerHelper) Command(ctx context.Context, args ...string) *exec.Cmd {
+	return r.buildCmd(ctx, args...)
+}
+
 func (r *DockerHelper) buildCmd(ctx context.Context, args ...string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, r.DockerCommand, args...)
 	if r.Environment != nil {
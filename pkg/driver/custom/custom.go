@@ -22,6 +22,10 @@ import (
 )
 
 func NewCustomDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) driver.Driver {
+	if len(workspaceInfo.Agent.Custom.Command) > 0 {
+		return newRPCDriver(workspaceInfo, log)
+	}
+
 	return &customDriver{
 		log:           log,
 		workspaceInfo: workspaceInfo,
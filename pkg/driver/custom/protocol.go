@@ -0,0 +1,115 @@
+package custom
+
+import (
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+)
+
+// ProtocolVersion is the version of the custom driver protocol. Version 1 is the command per operation mode,
+// version 2 is a long-lived driver process that speaks JSON-RPC 2.0 over stdio. Every message is a single line of JSON.
+//
+// DevPod sends requests for the methods below and the driver answers each with a response of the same id.
+// Output of exec and logs is streamed as "stream" notifications before the response, input of exec is sent the
+// same way from DevPod to the driver together with "resize" notifications. Cancelled requests are signalled with
+// a "$/cancelRequest" notification. The driver can send "log" notifications at any time and should exit once its
+// stdin is closed. The notifications are described in the jsonrpc package.
+const ProtocolVersion = 2
+
+const (
+	MethodInitialize         = "initialize"
+	MethodFindDevContainer   = "findDevContainer"
+	MethodRunDevContainer    = "runDevContainer"
+	MethodStartDevContainer  = "startDevContainer"
+	MethodStopDevContainer   = "stopDevContainer"
+	MethodDeleteDevContainer = "deleteDevContainer"
+	MethodTargetArchitecture = "targetArchitecture"
+	MethodExec               = "exec"
+	MethodLogs               = "logs"
+	MethodBuild              = "build"
+)
+
+type InitializeParams struct {
+	// ProtocolVersion is the highest protocol version DevPod supports
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+type InitializeResult struct {
+	// ProtocolVersion is the protocol version the driver speaks
+	ProtocolVersion int `json:"protocolVersion"`
+
+	// Capabilities are the optional methods the driver implements
+	Capabilities Capabilities `json:"capabilities,omitempty"`
+}
+
+type Capabilities struct {
+	// Build is true if the driver implements build
+	Build bool `json:"build,omitempty"`
+
+	// TTY is true if exec supports allocating a pseudo terminal
+	TTY bool `json:"tty,omitempty"`
+
+	// Reprovision is true if the driver can reprovision the devcontainer
+	Reprovision bool `json:"reprovision,omitempty"`
+}
+
+// WorkspaceParams are the params of the find, start, stop, delete and target architecture methods
+type WorkspaceParams struct {
+	WorkspaceID string `json:"workspaceId"`
+}
+
+type RunParams struct {
+	WorkspaceID string             `json:"workspaceId"`
+	Options     *driver.RunOptions `json:"options,omitempty"`
+}
+
+type ExecParams struct {
+	WorkspaceID string `json:"workspaceId"`
+	User        string `json:"user,omitempty"`
+	Command     string `json:"command"`
+
+	// Stream identifies the stream notifications of this exec
+	Stream string `json:"stream"`
+
+	// TTY requests a pseudo terminal of the given size, window size changes are sent as resize notifications
+	TTY *driver.TTY `json:"tty,omitempty"`
+}
+
+type ExecResult struct {
+	ExitCode int `json:"exitCode"`
+}
+
+type LogsParams struct {
+	WorkspaceID string `json:"workspaceId"`
+	Follow      bool   `json:"follow,omitempty"`
+
+	// Stream identifies the stream notifications of these logs
+	Stream string `json:"stream"`
+}
+
+// BuildParams describe the build of the devcontainer image, the dockerfile already contains the features
+type BuildParams struct {
+	WorkspaceID  string `json:"workspaceId"`
+	PrebuildHash string `json:"prebuildHash"`
+	Platform     string `json:"platform,omitempty"`
+
+	Dockerfile string            `json:"dockerfile"`
+	Context    string            `json:"context"`
+	Contexts   map[string]string `json:"contexts,omitempty"`
+	Target     string            `json:"target,omitempty"`
+	BuildArgs  map[string]string `json:"buildArgs,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CliOpts    []string          `json:"cliOpts,omitempty"`
+
+	// Images are the suggested names of the image
+	Images    []string `json:"images,omitempty"`
+	CacheFrom []string `json:"cacheFrom,omitempty"`
+	CacheTo   []string `json:"cacheTo,omitempty"`
+}
+
+type BuildResult struct {
+	// ImageName is the image the devcontainer should be run with
+	ImageName string `json:"imageName"`
+
+	// ImageDetails is the configuration of the built image
+	ImageDetails *config.ImageDetails `json:"imageDetails,omitempty"`
+}
@@ -0,0 +1,371 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/loft-sh/devpod/pkg/client/clientimplementation"
+	"github.com/loft-sh/devpod/pkg/devcontainer/build"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/jsonrpc"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"github.com/sirupsen/logrus"
+)
+
+func newRPCDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) *rpcDriver {
+	return &rpcDriver{
+		log:           log,
+		workspaceInfo: workspaceInfo,
+	}
+}
+
+var _ driver.ReprovisioningDriver = (*rpcDriver)(nil)
+var _ driver.BuildDriver = (*rpcDriver)(nil)
+var _ driver.TTYExecDriver = (*rpcDriver)(nil)
+var _ driver.LogsFollowDriver = (*rpcDriver)(nil)
+
+var (
+	rpcDriversMutex sync.Mutex
+	rpcDrivers      = map[*rpcDriver]bool{}
+)
+
+// CloseDrivers stops all driver processes that were started by this process
+func CloseDrivers() {
+	rpcDriversMutex.Lock()
+	drivers := rpcDrivers
+	rpcDrivers = map[*rpcDriver]bool{}
+	rpcDriversMutex.Unlock()
+
+	for r := range drivers {
+		r.Close()
+	}
+}
+
+// rpcDriver talks to a long-lived driver process through the JSON-RPC protocol, see ProtocolVersion
+type rpcDriver struct {
+	log log.Logger
+
+	workspaceInfo *provider2.AgentWorkspaceInfo
+
+	mutex        sync.Mutex
+	client       *jsonrpc.Client
+	capabilities Capabilities
+}
+
+// Close stops the driver process, it is started again on the next use
+func (r *rpcDriver) Close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.client != nil {
+		_ = r.client.Close()
+		r.client = nil
+	}
+}
+
+// connect starts the driver process on first use and negotiates the protocol version. A driver that exited
+// is started again.
+func (r *rpcDriver) connect(ctx context.Context) (*jsonrpc.Client, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.client != nil && !r.client.Closed() {
+		return r.client, nil
+	} else if r.client != nil {
+		r.log.Debugf("Driver exited, starting it again")
+		_ = r.client.Close()
+		r.client = nil
+	}
+
+	environ, err := ToEnvironWithBinaries(r.workspaceInfo, r.log)
+	if err != nil {
+		return nil, err
+	}
+	if r.log.GetLevel() == logrus.DebugLevel {
+		environ = append(environ, clientimplementation.DevPodDebug+"=true")
+	}
+
+	r.log.Debugf("Start driver: %s", r.workspaceInfo.Agent.Custom.Command)
+	client, err := jsonrpc.Start("driver", func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+		stderr := r.log.Writer(logrus.DebugLevel, false)
+		defer stderr.Close()
+
+		return clientimplementation.RunCommand(ctx, r.workspaceInfo.Agent.Custom.Command, environ, stdin, stdout, stderr)
+	}, r.log)
+	if err != nil {
+		return nil, err
+	}
+	result := &InitializeResult{}
+	err = client.Call(ctx, MethodInitialize, &InitializeParams{ProtocolVersion: ProtocolVersion}, result)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("initialize driver: %w", err)
+	} else if result.ProtocolVersion != ProtocolVersion {
+		_ = client.Close()
+		return nil, fmt.Errorf("driver speaks protocol version %d, DevPod supports version %d", result.ProtocolVersion, ProtocolVersion)
+	}
+
+	r.client = client
+	r.capabilities = result.Capabilities
+	rpcDriversMutex.Lock()
+	rpcDrivers[r] = true
+	rpcDriversMutex.Unlock()
+	return client, nil
+}
+
+func (r *rpcDriver) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	client, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.Call(ctx, method, params, result)
+}
+
+// FindDevContainer returns a running devcontainer details
+func (r *rpcDriver) FindDevContainer(ctx context.Context, workspaceId string) (*config.ContainerDetails, error) {
+	var containerDetails *config.ContainerDetails
+	err := r.call(ctx, MethodFindDevContainer, &WorkspaceParams{WorkspaceID: workspaceId}, &containerDetails)
+	if err != nil {
+		return nil, fmt.Errorf("error finding dev container: %w", err)
+	}
+
+	return containerDetails, nil
+}
+
+// CommandDevContainer runs the given command inside the devcontainer
+func (r *rpcDriver) CommandDevContainer(ctx context.Context, workspaceId, user, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	return r.ExecDevContainer(ctx, workspaceId, &driver.ExecOptions{
+		User:    user,
+		Command: command,
+		Stdin:   stdin,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	})
+}
+
+// ExecDevContainer runs the given command inside the devcontainer, optionally with a pseudo terminal
+func (r *rpcDriver) ExecDevContainer(ctx context.Context, workspaceId string, options *driver.ExecOptions) error {
+	client, err := r.connect(ctx)
+	if err != nil {
+		return err
+	} else if options.TTY != nil && !r.capabilities.TTY {
+		return fmt.Errorf("driver doesn't support allocating a tty")
+	}
+
+	stream := client.NewStream(options.Stdout, options.Stderr)
+	defer client.RemoveStream(stream)
+
+	// input is only sent once the driver knows the stream
+	request, err := client.Request(MethodExec, &ExecParams{
+		WorkspaceID: workspaceId,
+		User:        options.User,
+		Command:     options.Command,
+		Stream:      stream,
+		TTY:         options.TTY,
+	})
+	if err != nil {
+		return err
+	}
+
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if options.Stdin != nil {
+		go client.ForwardStdin(execCtx, stream, options.Stdin)
+	} else {
+		err = client.Notify(jsonrpc.NotificationStream, &jsonrpc.StreamParams{Stream: stream, Name: jsonrpc.StreamStdin, EOF: true})
+		if err != nil {
+			return err
+		}
+	}
+	if options.Resize != nil {
+		go func() {
+			for {
+				select {
+				case <-execCtx.Done():
+					return
+				case size := <-options.Resize:
+					_ = client.Notify(jsonrpc.NotificationResize, &jsonrpc.ResizeParams{Stream: stream, Width: size.Width, Height: size.Height})
+				}
+			}
+		}()
+	}
+
+	result := &ExecResult{}
+	err = request.Wait(ctx, result)
+	if err != nil {
+		return err
+	} else if result.ExitCode != 0 {
+		return fmt.Errorf("command exited with code %d", result.ExitCode)
+	}
+
+	return nil
+}
+
+// TargetArchitecture returns the architecture of the container runtime. e.g. amd64 or arm64
+func (r *rpcDriver) TargetArchitecture(ctx context.Context, workspaceId string) (string, error) {
+	targetArchitecture := ""
+	err := r.call(ctx, MethodTargetArchitecture, &WorkspaceParams{WorkspaceID: workspaceId}, &targetArchitecture)
+	if err != nil {
+		return "", fmt.Errorf("error getting target architecture: %w", err)
+	} else if targetArchitecture != "amd64" && targetArchitecture != "arm64" {
+		return "", fmt.Errorf("invalid target architecture %s, expected either arm64 or amd64", targetArchitecture)
+	}
+
+	return targetArchitecture, nil
+}
+
+// DeleteDevContainer deletes the devcontainer
+func (r *rpcDriver) DeleteDevContainer(ctx context.Context, workspaceId string) error {
+	err := r.call(ctx, MethodDeleteDevContainer, &WorkspaceParams{WorkspaceID: workspaceId}, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting devcontainer: %w", err)
+	}
+
+	return nil
+}
+
+// StartDevContainer starts the devcontainer
+func (r *rpcDriver) StartDevContainer(ctx context.Context, workspaceId string) error {
+	err := r.call(ctx, MethodStartDevContainer, &WorkspaceParams{WorkspaceID: workspaceId}, nil)
+	if err != nil {
+		return fmt.Errorf("error starting devcontainer: %w", err)
+	}
+
+	return nil
+}
+
+// StopDevContainer stops the devcontainer
+func (r *rpcDriver) StopDevContainer(ctx context.Context, workspaceId string) error {
+	err := r.call(ctx, MethodStopDevContainer, &WorkspaceParams{WorkspaceID: workspaceId}, nil)
+	if err != nil {
+		return fmt.Errorf("error stopping devcontainer: %w", err)
+	}
+
+	return nil
+}
+
+// RunDevContainer runs a devcontainer
+func (r *rpcDriver) RunDevContainer(ctx context.Context, workspaceId string, options *driver.RunOptions) error {
+	err := r.call(ctx, MethodRunDevContainer, &RunParams{WorkspaceID: workspaceId, Options: options}, nil)
+	if err != nil {
+		return fmt.Errorf("error running devcontainer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *rpcDriver) GetDevContainerLogs(ctx context.Context, workspaceId string, stdout io.Writer, stderr io.Writer) error {
+	return r.logs(ctx, workspaceId, false, stdout, stderr)
+}
+
+// FollowDevContainerLogs streams the logs of the devcontainer until the context is cancelled
+func (r *rpcDriver) FollowDevContainerLogs(ctx context.Context, workspaceId string, stdout io.Writer, stderr io.Writer) error {
+	return r.logs(ctx, workspaceId, true, stdout, stderr)
+}
+
+func (r *rpcDriver) logs(ctx context.Context, workspaceId string, follow bool, stdout io.Writer, stderr io.Writer) error {
+	client, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream := client.NewStream(stdout, stderr)
+	defer client.RemoveStream(stream)
+
+	err = client.Call(ctx, MethodLogs, &LogsParams{WorkspaceID: workspaceId, Follow: follow, Stream: stream}, nil)
+	if err != nil {
+		return fmt.Errorf("error getting devcontainer logs: %w", err)
+	}
+
+	return nil
+}
+
+// CanExecTTY returns true if the driver can allocate a pseudo terminal
+func (r *rpcDriver) CanExecTTY() bool {
+	_, err := r.connect(context.Background())
+	if err != nil {
+		r.log.Debugf("Error connecting to driver: %v", err)
+		return false
+	}
+
+	return r.capabilities.TTY
+}
+
+func (r *rpcDriver) CanReprovision() bool {
+	_, err := r.connect(context.Background())
+	if err != nil {
+		r.log.Debugf("Error connecting to driver: %v", err)
+		return false
+	}
+
+	return r.capabilities.Reprovision
+}
+
+// CanBuild returns true if the driver implements the build method
+func (r *rpcDriver) CanBuild() bool {
+	_, err := r.connect(context.Background())
+	if err != nil {
+		r.log.Debugf("Error connecting to driver: %v", err)
+		return false
+	}
+
+	return r.capabilities.Build
+}
+
+// BuildDevContainer passes the build with the features already applied to the dockerfile to the driver
+func (r *rpcDriver) BuildDevContainer(
+	ctx context.Context,
+	prebuildHash string,
+	parsedConfig *config.SubstitutedConfig,
+	extendedBuildInfo *feature.ExtendedBuildInfo,
+	dockerfilePath,
+	dockerfileContent string,
+	localWorkspaceFolder string,
+	options provider2.BuildOptions,
+) (*config.BuildInfo, error) {
+	// check if we shouldn't build
+	if options.NoBuild {
+		return nil, fmt.Errorf("you cannot build in this mode. Please run 'devpod up' to rebuild the container")
+	}
+
+	buildOptions, err := build.NewOptions(dockerfilePath, dockerfileContent, parsedConfig, extendedBuildInfo, build.GetImageName(localWorkspaceFolder, prebuildHash), options, prebuildHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildResult{}
+	err = r.call(ctx, MethodBuild, &BuildParams{
+		WorkspaceID:  r.workspaceInfo.Workspace.ID,
+		PrebuildHash: prebuildHash,
+		Platform:     options.Platform,
+		Dockerfile:   buildOptions.Dockerfile,
+		Context:      buildOptions.Context,
+		Contexts:     buildOptions.Contexts,
+		Target:       buildOptions.Target,
+		BuildArgs:    buildOptions.BuildArgs,
+		Labels:       buildOptions.Labels,
+		CliOpts:      buildOptions.CliOpts,
+		Images:       buildOptions.Images,
+		CacheFrom:    buildOptions.CacheFrom,
+		CacheTo:      buildOptions.CacheTo,
+	}, result)
+	if err != nil {
+		return nil, fmt.Errorf("error building devcontainer: %w", err)
+	} else if result.ImageName == "" || result.ImageDetails == nil {
+		return nil, fmt.Errorf("driver returned no image for the build")
+	}
+
+	return &config.BuildInfo{
+		ImageDetails:  result.ImageDetails,
+		ImageMetadata: extendedBuildInfo.MetadataConfig,
+		ImageName:     result.ImageName,
+		PrebuildHash:  prebuildHash,
+		RegistryCache: options.RegistryCache,
+		Tags:          options.Tag,
+	}, nil
+}
@@ -0,0 +1,225 @@
+package custom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/jsonrpc"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/types"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+// fakeDriver answers exec requests by echoing stdin to stdout and the command to stderr
+func fakeDriver(t *testing.T, reader io.Reader, writer io.Writer) {
+	encoder := json.NewEncoder(writer)
+	send := func(message *jsonrpc.Message) {
+		message.JSONRPC = jsonrpc.Version
+		assert.NilError(t, encoder.Encode(message))
+	}
+
+	var exec *jsonrpc.Message
+	execParams := &ExecParams{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		message := &jsonrpc.Message{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), message))
+		switch message.Method {
+		case MethodExec:
+			exec = message
+			assert.NilError(t, json.Unmarshal(message.Params, execParams))
+			params, _ := json.Marshal(&jsonrpc.StreamParams{Stream: execParams.Stream, Name: jsonrpc.StreamStderr, Data: []byte(execParams.Command)})
+			send(&jsonrpc.Message{Method: jsonrpc.NotificationStream, Params: params})
+		case jsonrpc.NotificationStream:
+			params := &jsonrpc.StreamParams{}
+			assert.NilError(t, json.Unmarshal(message.Params, params))
+			if params.EOF {
+				send(&jsonrpc.Message{ID: exec.ID, Result: json.RawMessage(`{"exitCode":3}`)})
+				continue
+			}
+
+			params.Name = jsonrpc.StreamStdout
+			out, _ := json.Marshal(params)
+			send(&jsonrpc.Message{Method: jsonrpc.NotificationStream, Params: out})
+		case MethodFindDevContainer:
+			send(&jsonrpc.Message{ID: message.ID, Error: &jsonrpc.Error{Code: 1, Message: "not found"}})
+		}
+	}
+}
+
+func TestRPCClientExec(t *testing.T) {
+	driverStdinReader, driverStdinWriter := io.Pipe()
+	driverStdoutReader, driverStdoutWriter := io.Pipe()
+	go fakeDriver(t, driverStdinReader, driverStdoutWriter)
+
+	client := jsonrpc.NewClient("driver", driverStdinWriter, driverStdoutReader, func() {}, log.Discard)
+	defer client.Close()
+	r := &rpcDriver{log: log.Discard, client: client}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := r.CommandDevContainer(context.Background(), "test", "root", "cat", bytes.NewBufferString("hello"), stdout, stderr)
+	assert.Error(t, err, "command exited with code 3")
+	assert.Equal(t, stdout.String(), "hello")
+	assert.Equal(t, stderr.String(), "cat")
+
+	_, err = r.FindDevContainer(context.Background(), "test")
+	assert.Error(t, err, "error finding dev container: not found (code 1)")
+}
+
+const standInDriverEnv = "DEVPOD_TEST_STAND_IN_DRIVER"
+
+// TestStandInDriver is the driver process the tests start. It implements find and exits after it in the
+// exit mode, the mode is the last argument. Exec writes its tty and the window size changes to stdout and
+// the input once stdin is closed.
+func TestStandInDriver(t *testing.T) {
+	if os.Getenv(standInDriverEnv) == "" {
+		t.Skip("only runs as stand-in driver")
+	}
+
+	runStandInDriver(os.Args[len(os.Args)-1])
+	os.Exit(0)
+}
+
+func runStandInDriver(mode string) {
+	encoder := json.NewEncoder(os.Stdout)
+	respond := func(id *int64, result interface{}) {
+		out, _ := json.Marshal(result)
+		_ = encoder.Encode(&jsonrpc.Message{JSONRPC: jsonrpc.Version, ID: id, Result: out})
+	}
+
+	stdout := func(stream string, data string) {
+		out, _ := json.Marshal(&jsonrpc.StreamParams{Stream: stream, Name: jsonrpc.StreamStdout, Data: []byte(data)})
+		_ = encoder.Encode(&jsonrpc.Message{JSONRPC: jsonrpc.Version, Method: jsonrpc.NotificationStream, Params: out})
+	}
+
+	execs := map[string]*int64{}
+	stdin := map[string]string{}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		message := &jsonrpc.Message{}
+		if json.Unmarshal(scanner.Bytes(), message) != nil {
+			continue
+		}
+
+		switch message.Method {
+		case MethodInitialize:
+			respond(message.ID, &InitializeResult{ProtocolVersion: ProtocolVersion, Capabilities: Capabilities{TTY: true}})
+		case MethodExec:
+			params := &ExecParams{}
+			_ = json.Unmarshal(message.Params, params)
+			execs[params.Stream] = message.ID
+			if params.TTY != nil {
+				stdout(params.Stream, fmt.Sprintf("tty %s %dx%d\n", params.TTY.Term, params.TTY.Width, params.TTY.Height))
+			}
+		case jsonrpc.NotificationResize:
+			params := &jsonrpc.ResizeParams{}
+			_ = json.Unmarshal(message.Params, params)
+			stdout(params.Stream, fmt.Sprintf("resize %dx%d\n", params.Width, params.Height))
+		case jsonrpc.NotificationStream:
+			params := &jsonrpc.StreamParams{}
+			_ = json.Unmarshal(message.Params, params)
+			stdin[params.Stream] += string(params.Data)
+			if params.EOF {
+				stdout(params.Stream, "stdin "+stdin[params.Stream])
+				respond(execs[params.Stream], &ExecResult{})
+			}
+		case MethodFindDevContainer:
+			respond(message.ID, &config.ContainerDetails{ID: strconv.Itoa(os.Getpid())})
+			if mode == "exit" {
+				return
+			}
+		}
+	}
+}
+
+// newStandInDriver returns a driver whose process is the stand-in driver in the given mode
+func newStandInDriver(t *testing.T, mode string) *rpcDriver {
+	t.Setenv(standInDriverEnv, "true")
+	r := newRPCDriver(&provider2.AgentWorkspaceInfo{
+		Workspace: &provider2.Workspace{ID: "test"},
+		Agent: provider2.ProviderAgentConfig{Custom: provider2.ProviderCustomDriverConfig{
+			Command: types.StrArray{os.Args[0], "-test.run=^TestStandInDriver$", mode},
+		}},
+		Origin: t.TempDir(),
+	}, log.Discard)
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func TestRPCDriverRestart(t *testing.T) {
+	r := newStandInDriver(t, "exit")
+
+	containerDetails, err := r.FindDevContainer(context.Background(), "test")
+	assert.NilError(t, err)
+	client := r.client
+	for start := time.Now(); !client.Closed() && time.Since(start) < 10*time.Second; {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Assert(t, client.Closed())
+
+	// the exited driver is started again
+	restartedContainerDetails, err := r.FindDevContainer(context.Background(), "test")
+	assert.NilError(t, err)
+	assert.Assert(t, containerDetails.ID != restartedContainerDetails.ID)
+
+	CloseDrivers()
+	assert.Assert(t, r.client == nil)
+}
+
+func TestRPCDriverExecTTY(t *testing.T) {
+	r := newStandInDriver(t, "serve")
+	assert.Assert(t, r.CanExecTTY())
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	resize := make(chan driver.TTY)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- r.ExecDevContainer(context.Background(), "test", &driver.ExecOptions{
+			Command: "sh",
+			Stdin:   stdinReader,
+			Stdout:  stdoutWriter,
+			Stderr:  io.Discard,
+			TTY:     &driver.TTY{Term: "xterm", Width: 80, Height: 24},
+			Resize:  resize,
+		})
+	}()
+
+	stdout := bufio.NewReader(stdoutReader)
+	line, err := stdout.ReadString('\n')
+	assert.NilError(t, err)
+	assert.Equal(t, line, "tty xterm 80x24\n")
+
+	resize <- driver.TTY{Width: 100, Height: 50}
+	line, err = stdout.ReadString('\n')
+	assert.NilError(t, err)
+	assert.Equal(t, line, "resize 100x50\n")
+
+	_, err = stdinWriter.Write([]byte("hello"))
+	assert.NilError(t, err)
+	assert.NilError(t, stdinWriter.Close())
+	out := make([]byte, len("stdin hello"))
+	_, err = io.ReadFull(stdout, out)
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), "stdin hello")
+	assert.NilError(t, <-errChan)
+
+	// without stdin the input is closed right away
+	buffer := &bytes.Buffer{}
+	err = r.ExecDevContainer(context.Background(), "test", &driver.ExecOptions{Command: "true", Stdout: buffer, Stderr: io.Discard})
+	assert.NilError(t, err)
+	assert.Equal(t, buffer.String(), "stdin ")
+}
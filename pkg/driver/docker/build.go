@@ -117,7 +117,7 @@ func (d *dockerDriver) buildxExists(ctx context.Context) bool {
 	buf := &bytes.Buffer{}
 	err := d.Docker.Run(ctx, []string{"buildx", "version"}, nil, buf, buf)
 
-	return (err == nil) || d.Docker.IsPodman()
+	return (err == nil) || d.Docker.IsPodman() || d.Docker.IsNerdctl()
 }
 
 func (d *dockerDriver) internalBuild(ctx context.Context, writer io.Writer, platform string, options *build.BuildOptions) error {
@@ -142,15 +142,16 @@ func (d *dockerDriver) internalBuild(ctx context.Context, writer io.Writer, plat
 }
 
 func (d *dockerDriver) buildxBuild(ctx context.Context, writer io.Writer, platform string, options *build.BuildOptions) error {
-	// build args
-	args := []string{
-		"buildx",
-		"build",
-		"-f", options.Dockerfile,
+	// nerdctl has no buildx, `nerdctl build` builds through buildkitd and loads the image into containerd by default
+	nerdctl := d.Docker.IsNerdctl()
+	args := []string{"buildx", "build"}
+	if nerdctl {
+		args = []string{"build"}
 	}
+	args = append(args, "-f", options.Dockerfile)
 
 	// add load
-	if options.Load {
+	if options.Load && !nerdctl {
 		args = append(args, "--load")
 	}
 
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/build"
+	"github.com/loft-sh/devpod/pkg/docker"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+// fakeBuilder writes a stand-in docker command that reports the given version and records the build arguments
+func fakeBuilder(t *testing.T, version string) (string, string) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	path := filepath.Join(dir, "docker")
+	err := os.WriteFile(path, []byte("#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then\n  echo \""+version+"\"\nelse\n  echo \"$@\" > "+argsFile+"\nfi\n"), 0o755)
+	assert.NilError(t, err)
+	return path, argsFile
+}
+
+func TestBuildxBuildArgs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		version string
+
+		expectedArgs string
+	}{
+		{
+			name:         "docker",
+			version:      "Docker version 27.0.3",
+			expectedArgs: "buildx build -f Dockerfile --load -t my-image .",
+		},
+		{
+			name:         "nerdctl",
+			version:      "nerdctl version 1.7.6",
+			expectedArgs: "build -f Dockerfile -t my-image .",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			command, argsFile := fakeBuilder(t, testCase.version)
+			d := &dockerDriver{
+				Docker: &docker.DockerHelper{DockerCommand: command, Log: log.Discard},
+				Log:    log.Discard,
+			}
+
+			err := d.buildxBuild(context.Background(), &bytes.Buffer{}, "", &build.BuildOptions{
+				Dockerfile: "Dockerfile",
+				Context:    ".",
+				Images:     []string{"my-image"},
+				Load:       true,
+			})
+			assert.NilError(t, err)
+
+			out, err := os.ReadFile(argsFile)
+			assert.NilError(t, err)
+			assert.Equal(t, strings.TrimSpace(string(out)), testCase.expectedArgs)
+		})
+	}
+}
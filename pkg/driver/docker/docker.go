@@ -52,22 +52,31 @@ func NewDockerDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger
 	}
 
 	log.Debugf("Using docker command '%s'", dockerCommand)
+	return NewDockerDriverWithHelper(&docker.DockerHelper{
+		DockerCommand: dockerCommand,
+		Environment:   makeEnvironment(workspaceInfo.Agent.Docker.Env, log),
+		ContainerID:   workspaceInfo.Workspace.Source.Container,
+		Builder:       builder,
+		Log:           log,
+	}, workspaceInfo.Agent.DataPath, log), nil
+}
+
+// NewDockerDriverWithHelper returns a docker driver that runs all commands through the given helper. Drivers
+// for docker compatible CLIs, such as podman, build on top of it.
+func NewDockerDriverWithHelper(helper *docker.DockerHelper, agentDataPath string, log log.Logger) driver.DockerDriver {
 	return &dockerDriver{
-		Docker: &docker.DockerHelper{
-			DockerCommand: dockerCommand,
-			Environment:   makeEnvironment(workspaceInfo.Agent.Docker.Env, log),
-			ContainerID:   workspaceInfo.Workspace.Source.Container,
-			Builder:       builder,
-			Log:           log,
-		},
-		Log: log,
-	}, nil
+		Docker:        helper,
+		AgentDataPath: agentDataPath,
+		Log:           log,
+	}
 }
 
 type dockerDriver struct {
 	Docker  *docker.DockerHelper
 	Compose *compose.ComposeHelper
 
+	AgentDataPath string
+
 	Log log.Logger
 }
 
@@ -295,7 +304,7 @@ func (d *dockerDriver) RunDockerDevContainer(
 	// the same of the external user.
 	// This will avoid problems of mismatching chowns on the
 	// project files.
-	if d.Docker.IsPodman() && os.Getuid() != 0 {
+	if d.Docker.IsPodman() && os.Getuid() != 0 && !hasUserNamespaceArg(parsedConfig.RunArgs) && !hasUserNamespaceEnv(d.Docker.Environment) {
 		args = append(args, "--userns", "keep-id")
 	}
 
@@ -361,6 +370,13 @@ func (d *dockerDriver) RunDockerDevContainer(
 		}
 	}
 
+	// limit cpus and memory to the host requirements
+	requirementsArgs, err := hostRequirementsArgs(parsedConfig.HostRequirements, parsedConfig.RunArgs)
+	if err != nil {
+		return err
+	}
+	args = append(args, requirementsArgs...)
+
 	args = append(args, parsedConfig.RunArgs...)
 
 	// run detached
@@ -567,6 +583,25 @@ func (d *dockerDriver) RunDockerDevContainer(
 	return nil
 }
 
+// hasUserNamespaceArg returns true if the runArgs of the devcontainer.json already configure the user namespace
+func hasUserNamespaceArg(runArgs []string) bool {
+	for _, arg := range runArgs {
+		if arg == "--userns" || strings.HasPrefix(arg, "--userns=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasUserNamespaceEnv returns true if podman already takes the user namespace from PODMAN_USERNS, e.g. because
+// the podman driver configured it for rootless podman
+func hasUserNamespaceEnv(environment []string) bool {
+	return os.Getenv("PODMAN_USERNS") != "" || slices.ContainsFunc(environment, func(env string) bool {
+		return strings.HasPrefix(env, "PODMAN_USERNS=")
+	})
+}
+
 func (d *dockerDriver) EnsureImage(
 	ctx context.Context,
 	options *driver.RunOptions,
@@ -615,3 +650,18 @@ func (d *dockerDriver) GetDevContainerLogs(ctx context.Context, workspaceId stri
 
 	return d.Docker.GetContainerLogs(ctx, container.ID, stdout, stderr)
 }
+
+// FollowDevContainerLogs streams the logs of the devcontainer until the context is cancelled
+func (d *dockerDriver) FollowDevContainerLogs(ctx context.Context, workspaceId string, stdout io.Writer, stderr io.Writer) error {
+	container, err := d.FindDevContainer(ctx, workspaceId)
+	if err != nil {
+		return err
+	} else if container == nil {
+		return fmt.Errorf("container not found")
+	}
+
+	cmd := d.Docker.Command(ctx, "logs", "--follow", container.ID)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
@@ -0,0 +1,21 @@
+package docker
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestHasUserNamespace(t *testing.T) {
+	t.Setenv("PODMAN_USERNS", "")
+
+	assert.Assert(t, hasUserNamespaceArg([]string{"--userns=auto"}))
+	assert.Assert(t, hasUserNamespaceArg([]string{"--userns", "auto"}))
+	assert.Assert(t, !hasUserNamespaceArg([]string{"--cpus=2"}))
+
+	assert.Assert(t, hasUserNamespaceEnv([]string{"PODMAN_USERNS=keep-id"}))
+	assert.Assert(t, !hasUserNamespaceEnv([]string{"FOO=bar"}))
+
+	t.Setenv("PODMAN_USERNS", "auto")
+	assert.Assert(t, hasUserNamespaceEnv(nil))
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+)
+
+// ValidateHostRequirements checks the requirements against the docker host. The container is started with
+// the required cpus and memory, see hostRequirementsArgs.
+func (d *dockerDriver) ValidateHostRequirements(ctx context.Context, workspaceID string, requirements *config.HostRequirements) error {
+	if requirements == nil {
+		return nil
+	}
+
+	info, err := d.Docker.Info(ctx)
+	if err != nil {
+		d.Log.Warnf("Skip validating hostRequirements, because docker info failed: %v", err)
+		return nil
+	}
+
+	available := &config.HostResources{
+		CPUs:   info.NCPU,
+		Memory: info.MemTotal,
+	}
+	if requirements.RequiresGPU() {
+		gpu, err := d.Docker.GPUSupportEnabled()
+		if err == nil {
+			available.GPU = &gpu
+		}
+	}
+	if requirements.Storage != "" {
+		d.Log.Debugf("Skip validating hostRequirements.storage, docker doesn't report the available disk space")
+	}
+
+	return config.ValidateHostRequirements(requirements, available, "docker host")
+}
+
+// hostRequirementsArgs returns the docker run flags that give the container the required cpus and memory.
+// Flags the user already set through runArgs take precedence.
+func hostRequirementsArgs(requirements *config.HostRequirements, runArgs []string) ([]string, error) {
+	if requirements == nil {
+		return nil, nil
+	}
+
+	args := []string{}
+	if requirements.CPUs > 0 && !hasRunArg(runArgs, "--cpus") {
+		args = append(args, "--cpus", strconv.Itoa(requirements.CPUs))
+	}
+	if requirements.Memory != "" && !hasRunArg(runArgs, "--memory", "-m") {
+		memory, err := requirements.MemoryBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, "--memory", strconv.FormatInt(memory, 10))
+	}
+
+	return args, nil
+}
+
+func hasRunArg(runArgs []string, names ...string) bool {
+	for _, runArg := range runArgs {
+		for _, name := range names {
+			if runArg == name || strings.HasPrefix(runArg, name+"=") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
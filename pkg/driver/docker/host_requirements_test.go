@@ -0,0 +1,50 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"gotest.tools/assert"
+)
+
+func TestHostRequirementsArgs(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		requirements *config.HostRequirements
+		runArgs      []string
+
+		expectedArgs []string
+	}{
+		{
+			name: "no requirements",
+		},
+		{
+			name:         "cpus and memory",
+			requirements: &config.HostRequirements{CPUs: 2, Memory: "4gb", Storage: "32gb"},
+
+			expectedArgs: []string{"--cpus", "2", "--memory", "4294967296"},
+		},
+		{
+			name:         "run args take precedence",
+			requirements: &config.HostRequirements{CPUs: 2, Memory: "4gb"},
+			runArgs:      []string{"--cpus=4", "-m", "8g"},
+
+			expectedArgs: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			args, err := hostRequirementsArgs(testCase.requirements, testCase.runArgs)
+			assert.NilError(t, err)
+			assert.Equal(t, len(args), len(testCase.expectedArgs))
+			for i := range args {
+				assert.Equal(t, args[i], testCase.expectedArgs[i])
+			}
+		})
+	}
+
+	_, err := hostRequirementsArgs(&config.HostRequirements{Memory: "lots"}, nil)
+	assert.ErrorContains(t, err, "memory")
+}
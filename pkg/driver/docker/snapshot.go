@@ -0,0 +1,321 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	snapshotImageRepository = "devpod-snapshot"
+	snapshotVolumeMountPath = "/devpod-volume"
+	snapshotInfoFile        = "snapshot.json"
+)
+
+type containerMounts struct {
+	Mounts []containerMount `json:"Mounts,omitempty"`
+}
+
+type containerMount struct {
+	Type        string `json:"Type,omitempty"`
+	Name        string `json:"Name,omitempty"`
+	Destination string `json:"Destination,omitempty"`
+}
+
+func (d *dockerDriver) CreateSnapshot(ctx context.Context, workspaceId, name string) (*driver.Snapshot, error) {
+	err := driver.ValidateSnapshotName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := d.GetSnapshot(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	container, err := d.FindDevContainer(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	} else if container == nil {
+		return nil, fmt.Errorf("container not found")
+	}
+
+	snapshotDir, err := d.snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Join(snapshotDir, "volumes"), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := d.createSnapshot(ctx, workspaceId, name, container.ID, snapshotDir)
+	if err != nil {
+		_ = os.RemoveAll(snapshotDir)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (d *dockerDriver) createSnapshot(ctx context.Context, workspaceId, name, containerID, snapshotDir string) (*driver.Snapshot, error) {
+	writer := d.Log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	// commit container filesystem
+	snapshot := &driver.Snapshot{
+		Name:              name,
+		WorkspaceID:       workspaceId,
+		Image:             snapshotImageRepository + ":" + name,
+		CreationTimestamp: types.Now(),
+	}
+	d.Log.Infof("Committing container %s to image %s", containerID, snapshot.Image)
+	args := []string{"commit", containerID, snapshot.Image}
+	d.Log.Debugf("Running docker command: %s %s", d.Docker.DockerCommand, strings.Join(args, " "))
+	err := d.Docker.Run(ctx, args, nil, writer, writer)
+	if err != nil {
+		return nil, errors.Wrap(err, "commit container")
+	}
+
+	// archive volumes
+	mounts := []containerMounts{}
+	err = d.Docker.Inspect(ctx, []string{containerID}, "container", &mounts)
+	if err != nil {
+		return nil, err
+	} else if len(mounts) == 0 {
+		return nil, fmt.Errorf("couldn't inspect container %s", containerID)
+	}
+	for idx, mount := range mounts[0].Mounts {
+		if mount.Type != "volume" || mount.Name == "" {
+			continue
+		}
+
+		volume := driver.SnapshotVolume{
+			Name:   mount.Name,
+			Target: mount.Destination,
+			File:   filepath.Join(snapshotDir, "volumes", strconv.Itoa(idx)+".tar"),
+		}
+		d.Log.Infof("Archiving volume %s", volume.Name)
+		err = d.archiveVolume(ctx, snapshot.Image, volume)
+		if err != nil {
+			return nil, errors.Wrapf(err, "archive volume %s", volume.Name)
+		}
+
+		snapshot.Volumes = append(snapshot.Volumes, volume)
+	}
+
+	out, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(snapshotDir, snapshotInfoFile), out, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (d *dockerDriver) archiveVolume(ctx context.Context, image string, volume driver.SnapshotVolume) error {
+	file, err := os.Create(volume.File)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := d.Log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	args := []string{
+		"run", "--rm",
+		"-u", "root",
+		"-v", volume.Name + ":" + snapshotVolumeMountPath,
+		"--entrypoint", "tar",
+		image,
+		"-C", snapshotVolumeMountPath, "-cf", "-", ".",
+	}
+	d.Log.Debugf("Running docker command: %s %s", d.Docker.DockerCommand, strings.Join(args, " "))
+	return d.Docker.Run(ctx, args, nil, file, writer)
+}
+
+func (d *dockerDriver) GetSnapshot(ctx context.Context, name string) (*driver.Snapshot, error) {
+	snapshotDir, err := d.snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.ReadFile(filepath.Join(snapshotDir, snapshotInfoFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	snapshot := &driver.Snapshot{}
+	err = json.Unmarshal(out, snapshot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse snapshot %s", name)
+	}
+
+	return snapshot, nil
+}
+
+func (d *dockerDriver) ListSnapshots(ctx context.Context) ([]*driver.Snapshot, error) {
+	snapshotsDir, err := agent.GetAgentSnapshotsDir(d.AgentDataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(snapshotsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	snapshots := []*driver.Snapshot{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		snapshot, err := d.GetSnapshot(ctx, entry.Name())
+		if err != nil {
+			d.Log.Warnf("Error reading snapshot %s: %v", entry.Name(), err)
+			continue
+		} else if snapshot == nil {
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+func (d *dockerDriver) DeleteSnapshot(ctx context.Context, name string) error {
+	snapshot, err := d.GetSnapshot(ctx, name)
+	if err != nil {
+		return err
+	} else if snapshot == nil {
+		return fmt.Errorf("snapshot %s doesn't exist", name)
+	}
+
+	if snapshot.Image != "" {
+		writer := d.Log.Writer(logrus.DebugLevel, false)
+		defer writer.Close()
+
+		args := []string{"image", "rm", snapshot.Image}
+		d.Log.Debugf("Running docker command: %s %s", d.Docker.DockerCommand, strings.Join(args, " "))
+		err = d.Docker.Run(ctx, args, nil, writer, writer)
+		if err != nil {
+			d.Log.Warnf("Error removing snapshot image %s: %v", snapshot.Image, err)
+		}
+	}
+
+	snapshotDir, err := d.snapshotDir(name)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(snapshotDir)
+}
+
+func (d *dockerDriver) RestoreSnapshot(ctx context.Context, workspaceId, name string, options *driver.RunOptions) error {
+	snapshot, err := d.GetSnapshot(ctx, name)
+	if err != nil {
+		return err
+	} else if snapshot == nil {
+		return fmt.Errorf("snapshot %s doesn't exist", name)
+	}
+
+	// start from the committed image
+	options.Image = snapshot.Image
+
+	// restore volumes by their mount target, as volume names might differ between workspaces
+	for _, volume := range snapshot.Volumes {
+		var volumeName string
+		for _, mount := range options.Mounts {
+			if mount.Type == "volume" && mount.Target == volume.Target {
+				volumeName = mount.Source
+				break
+			}
+		}
+		if volumeName == "" {
+			d.Log.Warnf("Skip restoring volume %s, because no volume is mounted at %s anymore", volume.Name, volume.Target)
+			continue
+		}
+
+		d.Log.Infof("Restoring volume %s from snapshot %s", volumeName, name)
+		err = d.restoreVolume(ctx, snapshot.Image, volumeName, volume.File)
+		if err != nil {
+			return errors.Wrapf(err, "restore volume %s", volumeName)
+		}
+	}
+
+	return nil
+}
+
+func (d *dockerDriver) restoreVolume(ctx context.Context, image, volumeName, archive string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = d.Docker.DeleteVolume(ctx, volumeName)
+	if err != nil {
+		return err
+	}
+
+	writer := d.Log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	args := []string{"volume", "create", volumeName}
+	d.Log.Debugf("Running docker command: %s %s", d.Docker.DockerCommand, strings.Join(args, " "))
+	err = d.Docker.Run(ctx, args, nil, writer, writer)
+	if err != nil {
+		return errors.Wrap(err, "create volume")
+	}
+
+	args = []string{
+		"run", "--rm", "-i",
+		"-u", "root",
+		"-v", volumeName + ":" + snapshotVolumeMountPath,
+		"--entrypoint", "tar",
+		image,
+		"-C", snapshotVolumeMountPath, "-xf", "-",
+	}
+	d.Log.Debugf("Running docker command: %s %s", d.Docker.DockerCommand, strings.Join(args, " "))
+	return d.Docker.Run(ctx, args, file, writer, writer)
+}
+
+func (d *dockerDriver) snapshotDir(name string) (string, error) {
+	err := driver.ValidateSnapshotName(name)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotsDir, err := agent.GetAgentSnapshotsDir(d.AgentDataPath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(snapshotsDir, name), nil
+}
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func newSnapshotTestDriver(t *testing.T, snapshots ...*driver.Snapshot) *dockerDriver {
+	agentDataPath := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(agentDataPath, "contexts"), 0755))
+	for _, snapshot := range snapshots {
+		snapshotDir := filepath.Join(agentDataPath, "snapshots", snapshot.Name)
+		assert.NilError(t, os.MkdirAll(snapshotDir, 0755))
+		out, err := json.Marshal(snapshot)
+		assert.NilError(t, err)
+		assert.NilError(t, os.WriteFile(filepath.Join(snapshotDir, snapshotInfoFile), out, 0644))
+	}
+
+	return &dockerDriver{AgentDataPath: agentDataPath, Log: log.Discard}
+}
+
+func TestGetAndListSnapshots(t *testing.T) {
+	d := newSnapshotTestDriver(t,
+		&driver.Snapshot{Name: "first", WorkspaceID: "ws", Image: "devpod-snapshot:first"},
+		&driver.Snapshot{Name: "second", WorkspaceID: "ws", Image: "devpod-snapshot:second"},
+	)
+
+	snapshot, err := d.GetSnapshot(context.Background(), "first")
+	assert.NilError(t, err)
+	assert.Equal(t, snapshot.Image, "devpod-snapshot:first")
+
+	snapshot, err = d.GetSnapshot(context.Background(), "missing")
+	assert.NilError(t, err)
+	assert.Assert(t, snapshot == nil)
+
+	_, err = d.GetSnapshot(context.Background(), "../first")
+	assert.ErrorContains(t, err, "invalid snapshot name")
+
+	snapshots, err := d.ListSnapshots(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(snapshots), 2)
+}
+
+func TestRestoreSnapshotSkipsUnmountedVolumes(t *testing.T) {
+	d := newSnapshotTestDriver(t, &driver.Snapshot{
+		Name:    "first",
+		Image:   "devpod-snapshot:first",
+		Volumes: []driver.SnapshotVolume{{Name: "old-volume", Target: "/data", File: "0.tar"}},
+	})
+
+	options := &driver.RunOptions{
+		Image:  "ubuntu",
+		Mounts: []*config.Mount{{Type: "bind", Source: "/tmp", Target: "/data"}},
+	}
+	assert.NilError(t, d.RestoreSnapshot(context.Background(), "ws", "first", options))
+	assert.Equal(t, options.Image, "devpod-snapshot:first")
+
+	err := d.RestoreSnapshot(context.Background(), "ws", "missing", options)
+	assert.Error(t, err, "snapshot missing doesn't exist")
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/loft-sh/devpod/pkg/driver"
 	"github.com/loft-sh/devpod/pkg/driver/custom"
 	"github.com/loft-sh/devpod/pkg/driver/docker"
+	"github.com/loft-sh/devpod/pkg/driver/host"
 	"github.com/loft-sh/devpod/pkg/driver/kubernetes"
+	"github.com/loft-sh/devpod/pkg/driver/nerdctl"
+	"github.com/loft-sh/devpod/pkg/driver/podman"
 	provider2 "github.com/loft-sh/devpod/pkg/provider"
 	"github.com/loft-sh/log"
 )
@@ -15,12 +18,18 @@ func NewDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (dri
 	driver := workspaceInfo.Agent.Driver
 	if driver == "" || driver == provider2.DockerDriver {
 		return docker.NewDockerDriver(workspaceInfo, log)
+	} else if driver == provider2.PodmanDriver {
+		return podman.NewPodmanDriver(workspaceInfo, log)
+	} else if driver == provider2.NerdctlDriver {
+		return nerdctl.NewNerdctlDriver(workspaceInfo, log)
 	} else if driver == provider2.CustomDriver {
 		return custom.NewCustomDriver(workspaceInfo, log), nil
 	} else if driver == provider2.KubernetesDriver {
 		return kubernetes.NewKubernetesDriver(workspaceInfo, log)
+	} else if driver == provider2.HostDriver {
+		return host.NewHostDriver(workspaceInfo, log)
 	}
 
-	return nil, fmt.Errorf("unrecognized driver '%s', possible values are %s, %s or %s",
-		driver, provider2.DockerDriver, provider2.CustomDriver, provider2.KubernetesDriver)
+	return nil, fmt.Errorf("unrecognized driver '%s', possible values are %s, %s, %s, %s, %s or %s",
+		driver, provider2.DockerDriver, provider2.PodmanDriver, provider2.NerdctlDriver, provider2.CustomDriver, provider2.KubernetesDriver, provider2.HostDriver)
 }
@@ -0,0 +1,270 @@
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/hostdir"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+)
+
+const (
+	stateFile = "state.json"
+
+	// LogFile holds the output of the processes the workspace runs in the background
+	LogFile = "devcontainer.log"
+)
+
+var _ driver.HostDriver = (*hostDriver)(nil)
+
+func NewHostDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (driver.Driver, error) {
+	// workspace processes are found through /proc when the workspace stops
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("the %s driver is only supported on linux", provider2.HostDriver)
+	} else if workspaceInfo.Origin == "" {
+		return nil, fmt.Errorf("the %s driver requires the agent workspace folder", provider2.HostDriver)
+	}
+
+	return &hostDriver{
+		Dir:             filepath.Join(workspaceInfo.Origin, "host"),
+		WorkspaceFolder: workspaceInfo.ContentFolder,
+		Log:             log,
+	}, nil
+}
+
+// hostDriver runs the devcontainer as processes of the current user directly on the host. The workspace lives in
+// the content folder of the agent and DevPod's own state lives in a per-workspace folder next to it.
+type hostDriver struct {
+	Dir             string
+	WorkspaceFolder string
+
+	Log log.Logger
+}
+
+// state replaces the container of the other drivers
+type state struct {
+	Status    string            `json:"status,omitempty"`
+	Created   string            `json:"created,omitempty"`
+	StartedAt string            `json:"startedAt,omitempty"`
+	User      string            `json:"user,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+func (h *hostDriver) RunsOnHost() bool {
+	return true
+}
+
+func (h *hostDriver) FindDevContainer(ctx context.Context, workspaceID string) (*config.ContainerDetails, error) {
+	s, err := h.readState()
+	if err != nil || s == nil {
+		return nil, err
+	}
+
+	return &config.ContainerDetails{
+		ID:      workspaceID,
+		Created: s.Created,
+		State: config.ContainerDetailsState{
+			Status:    s.Status,
+			StartedAt: s.StartedAt,
+		},
+		Config: config.ContainerDetailsConfig{
+			Labels:     s.Labels,
+			WorkingDir: h.WorkspaceFolder,
+			LegacyUser: s.User,
+		},
+	}, nil
+}
+
+func (h *hostDriver) CommandDevContainer(ctx context.Context, workspaceID, user, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	s, err := h.readState()
+	if err != nil {
+		return err
+	} else if s == nil {
+		return fmt.Errorf("workspace %s doesn't exist on the host", workspaceID)
+	}
+	if user != "" && user != "root" && user != s.User {
+		h.Log.Debugf("Run command as %s instead of %s, the host driver runs everything as the current user", s.User, user)
+	}
+
+	// commands address the devpod binary at its location within a container, which is the agent itself on the host
+	binaryPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	command = strings.ReplaceAll(command, agent.ContainerDevPodHelperLocation, binaryPath)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = h.WorkspaceFolder
+	cmd.Env = append(os.Environ(), config.ObjectToList(s.Env)...)
+	cmd.Env = append(cmd.Env, hostdir.Env+"="+h.Dir)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+func (h *hostDriver) RunDevContainer(ctx context.Context, workspaceID string, options *driver.RunOptions) error {
+	if options == nil {
+		return h.StartDevContainer(ctx, workspaceID)
+	}
+
+	err := os.MkdirAll(h.Dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339Nano)
+	s := &state{
+		Status:    "running",
+		Created:   now,
+		StartedAt: now,
+		User:      options.User,
+		Env:       options.Env,
+		Labels:    config.ListToObject(options.Labels),
+	}
+
+	h.Log.Infof("Run workspace on the host in %s", h.WorkspaceFolder)
+	return h.writeState(s)
+}
+
+// ValidateHostRequirements checks the CPUs of the host, the other requirements are not known to the driver
+func (h *hostDriver) ValidateHostRequirements(ctx context.Context, workspaceID string, requirements *config.HostRequirements) error {
+	return config.ValidateHostRequirements(requirements, &config.HostResources{CPUs: runtime.NumCPU()}, "host")
+}
+
+func (h *hostDriver) TargetArchitecture(ctx context.Context, workspaceID string) (string, error) {
+	return runtime.GOARCH, nil
+}
+
+func (h *hostDriver) DeleteDevContainer(ctx context.Context, workspaceID string) error {
+	err := h.StopDevContainer(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	// the workspace content is removed together with the agent workspace folder
+	return os.RemoveAll(h.Dir)
+}
+
+func (h *hostDriver) StartDevContainer(ctx context.Context, workspaceID string) error {
+	s, err := h.readState()
+	if err != nil {
+		return err
+	} else if s == nil {
+		return fmt.Errorf("workspace %s doesn't exist on the host", workspaceID)
+	}
+
+	s.Status = "running"
+	s.StartedAt = time.Now().Format(time.RFC3339Nano)
+	return h.writeState(s)
+}
+
+// StopDevContainer stops all processes that were started for the workspace, e.g. SSH servers, IDE
+// servers and lifecycle hooks that run in the background
+func (h *hostDriver) StopDevContainer(ctx context.Context, workspaceID string) error {
+	s, err := h.readState()
+	if err != nil || s == nil {
+		return err
+	}
+
+	h.stopProcesses()
+	s.Status = "exited"
+	return h.writeState(s)
+}
+
+func (h *hostDriver) GetDevContainerLogs(ctx context.Context, workspaceID string, stdout io.Writer, stderr io.Writer) error {
+	f, err := os.Open(filepath.Join(h.Dir, LogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(stdout, f)
+	return err
+}
+
+// stopProcesses terminates the processes that carry the host folder of this workspace in their environment
+func (h *hostDriver) stopProcesses() {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		h.Log.Debugf("Skip stopping workspace processes: %v", err)
+		return
+	}
+
+	marker := hostdir.Env + "=" + h.Dir
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		environ, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "environ"))
+		if err != nil {
+			continue
+		}
+		for _, env := range strings.Split(string(environ), "\x00") {
+			if env == marker {
+				h.Log.Debugf("Stop workspace process %d", pid)
+				process, err := os.FindProcess(pid)
+				if err == nil {
+					_ = process.Signal(syscall.SIGTERM)
+				}
+				break
+			}
+		}
+	}
+}
+
+func (h *hostDriver) readState() (*state, error) {
+	out, err := os.ReadFile(filepath.Join(h.Dir, stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	s := &state{}
+	err = json.Unmarshal(out, s)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", stateFile, err)
+	}
+	if s.User == "" {
+		currentUser, err := user.Current()
+		if err == nil {
+			s.User = currentUser.Username
+		}
+	}
+
+	return s, nil
+}
+
+func (h *hostDriver) writeState(s *state) error {
+	out, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(h.Dir, stateFile), out, 0o600)
+}
@@ -0,0 +1,105 @@
+package host
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/hostdir"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func newTestDriver(t *testing.T) *hostDriver {
+	d, err := NewHostDriver(&provider2.AgentWorkspaceInfo{
+		Origin:        t.TempDir(),
+		ContentFolder: t.TempDir(),
+	}, log.Discard)
+	assert.NilError(t, err)
+	return d.(*hostDriver)
+}
+
+func TestNewHostDriverWithoutOrigin(t *testing.T) {
+	_, err := NewHostDriver(&provider2.AgentWorkspaceInfo{}, log.Discard)
+	assert.ErrorContains(t, err, "requires the agent workspace folder")
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	h := newTestDriver(t)
+
+	containerDetails, err := h.FindDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	assert.Assert(t, containerDetails == nil)
+
+	err = h.RunDevContainer(ctx, "test", &driver.RunOptions{
+		User:   "runner",
+		Env:    map[string]string{"FOO": "bar"},
+		Labels: []string{"devpod.user=runner"},
+	})
+	assert.NilError(t, err)
+
+	containerDetails, err = h.FindDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	assert.Equal(t, containerDetails.State.Status, "running")
+	assert.Equal(t, containerDetails.Config.WorkingDir, h.WorkspaceFolder)
+	assert.Equal(t, containerDetails.Config.Labels["devpod.user"], "runner")
+
+	stdout := &bytes.Buffer{}
+	err = h.CommandDevContainer(ctx, "test", "runner", `echo "$FOO $`+hostdir.Env+` $(pwd)"`, nil, stdout, os.Stderr)
+	assert.NilError(t, err)
+	assert.Equal(t, strings.TrimSpace(stdout.String()), "bar "+h.Dir+" "+h.WorkspaceFolder)
+
+	err = h.StopDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	containerDetails, err = h.FindDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	assert.Equal(t, containerDetails.State.Status, "exited")
+
+	err = h.StartDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	containerDetails, err = h.FindDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	assert.Equal(t, containerDetails.State.Status, "running")
+
+	err = h.DeleteDevContainer(ctx, "test")
+	assert.NilError(t, err)
+	_, err = os.Stat(h.Dir)
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestStopProcesses(t *testing.T) {
+	h := newTestDriver(t)
+	assert.NilError(t, os.MkdirAll(h.Dir, 0o755))
+
+	workspaceProcess := exec.Command("sleep", "60")
+	workspaceProcess.Env = append(os.Environ(), hostdir.Env+"="+h.Dir)
+	assert.NilError(t, workspaceProcess.Start())
+	defer func() { _ = workspaceProcess.Process.Kill() }()
+
+	otherProcess := exec.Command("sleep", "60")
+	otherProcess.Env = append(os.Environ(), hostdir.Env+"="+filepath.Join(h.Dir, "other"))
+	assert.NilError(t, otherProcess.Start())
+	defer func() { _ = otherProcess.Process.Kill() }()
+
+	h.stopProcesses()
+
+	done := make(chan error, 1)
+	go func() { done <- workspaceProcess.Wait() }()
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "terminated")
+	case <-time.After(5 * time.Second):
+		t.Fatal("workspace process was not stopped")
+	}
+
+	assert.NilError(t, otherProcess.Process.Signal(syscall.Signal(0)), "other workspace process must keep running")
+}
@@ -0,0 +1,58 @@
+package host
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+)
+
+// UnsupportedConfig returns the parts of the devcontainer.json the host driver cannot apply, because they
+// require a container. The workspace runs as the given user with the tools installed on the host.
+func UnsupportedConfig(devContainerConfig *config.DevContainerConfig, userName string) []string {
+	unsupported := []string{}
+	if devContainerConfig.Image != "" {
+		unsupported = append(unsupported, fmt.Sprintf("image %s is not used, the workspace uses the tools installed on the host", devContainerConfig.Image))
+	}
+	if devContainerConfig.GetDockerfile() != "" {
+		unsupported = append(unsupported, fmt.Sprintf("Dockerfile %s is not built, the workspace uses the tools installed on the host", devContainerConfig.GetDockerfile()))
+	}
+	if len(devContainerConfig.DockerComposeFile) > 0 {
+		unsupported = append(unsupported, "dockerComposeFile is not started, only the workspace itself runs on the host")
+	}
+	if len(devContainerConfig.Features) > 0 {
+		features := slices.Sorted(maps.Keys(devContainerConfig.Features))
+		unsupported = append(unsupported, fmt.Sprintf("features are not installed, because their install scripts expect a container: %s", strings.Join(features, ", ")))
+	}
+	if len(devContainerConfig.Mounts) > 0 {
+		unsupported = append(unsupported, "mounts are not mounted, the workspace sees the file system of the host")
+	}
+	if devContainerConfig.WorkspaceMount != "" || devContainerConfig.WorkspaceFolder != "" {
+		unsupported = append(unsupported, "workspaceMount and workspaceFolder are ignored, the workspace folder is used as is")
+	}
+	if len(devContainerConfig.RunArgs) > 0 {
+		unsupported = append(unsupported, "runArgs are ignored")
+	}
+	if len(devContainerConfig.AppPort) > 0 {
+		unsupported = append(unsupported, "appPort is not published, use forwardPorts instead")
+	}
+	if (devContainerConfig.Privileged != nil && *devContainerConfig.Privileged) || len(devContainerConfig.CapAdd) > 0 || len(devContainerConfig.SecurityOpt) > 0 {
+		unsupported = append(unsupported, "privileged, capAdd and securityOpt are ignored, processes run with the permissions of "+userName)
+	}
+	if devContainerConfig.Init != nil && *devContainerConfig.Init {
+		unsupported = append(unsupported, "init is ignored")
+	}
+	for _, configUser := range []string{devContainerConfig.ContainerUser, devContainerConfig.RemoteUser} {
+		if configUser != "" && configUser != userName {
+			unsupported = append(unsupported, fmt.Sprintf("containerUser and remoteUser are ignored, the workspace runs as %s", userName))
+			break
+		}
+	}
+	if devContainerConfig.ShutdownAction == config.ShutdownActionStopContainer || devContainerConfig.ShutdownAction == config.ShutdownActionStopCompose {
+		unsupported = append(unsupported, fmt.Sprintf("shutdownAction %s is not applied, use devpod stop instead", devContainerConfig.ShutdownAction))
+	}
+
+	return unsupported
+}
@@ -0,0 +1,30 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"gotest.tools/assert"
+)
+
+func TestUnsupportedConfig(t *testing.T) {
+	devContainerConfig := &config.DevContainerConfig{}
+	devContainerConfig.Image = "mcr.microsoft.com/devcontainers/go"
+	devContainerConfig.Features = map[string]interface{}{
+		"ghcr.io/devcontainers/features/node:1": map[string]interface{}{},
+		"ghcr.io/devcontainers/features/go:1":   map[string]interface{}{},
+	}
+	devContainerConfig.RemoteUser = "vscode"
+
+	assert.DeepEqual(t, UnsupportedConfig(devContainerConfig, "runner"), []string{
+		"image mcr.microsoft.com/devcontainers/go is not used, the workspace uses the tools installed on the host",
+		"features are not installed, because their install scripts expect a container: ghcr.io/devcontainers/features/go:1, ghcr.io/devcontainers/features/node:1",
+		"containerUser and remoteUser are ignored, the workspace runs as runner",
+	})
+
+	devContainerConfig.Image = ""
+	devContainerConfig.Features = nil
+	devContainerConfig.RemoteUser = "runner"
+	devContainerConfig.ForwardPorts = []string{"8080"}
+	assert.Equal(t, len(UnsupportedConfig(devContainerConfig, "runner")), 0)
+}
@@ -0,0 +1,309 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/build"
+	"github.com/loft-sh/devpod/pkg/devcontainer/buildkit"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/devpod/pkg/image"
+	"github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/stdio"
+	"github.com/loft-sh/log/hash"
+	buildkitclient "github.com/moby/buildkit/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	BuildkitContainerName = "buildkitd"
+	DefaultBuildkitImage  = "moby/buildkit:v0.20.1-rootless"
+)
+
+// CanBuild returns true if a build repository is configured, otherwise images are built with dockerless
+func (k *KubernetesDriver) CanBuild() bool {
+	return k.options.BuildRepository != ""
+}
+
+// BuildDevContainer builds the image with a rootless buildkit pod in the cluster and pushes it to the build repository.
+// Buildkit is reached through `buildctl dial-stdio` in the pod, so the build context and the registry credentials
+// are streamed from the local machine through the API server and no local docker is involved.
+func (k *KubernetesDriver) BuildDevContainer(
+	ctx context.Context,
+	prebuildHash string,
+	parsedConfig *config.SubstitutedConfig,
+	extendedBuildInfo *feature.ExtendedBuildInfo,
+	dockerfilePath,
+	dockerfileContent string,
+	localWorkspaceFolder string,
+	options provider.BuildOptions,
+) (*config.BuildInfo, error) {
+	targetArch, err := k.TargetArchitecture(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// images are tagged like prebuilds, so the next build with the same configuration can reuse them
+	imageName := k.options.BuildRepository + ":" + prebuildHash
+	if !options.ForceBuild {
+		imageDetails, err := getImageDetails(ctx, imageName, targetArch)
+		if err == nil {
+			k.Log.Infof("Found existing image %s, skipping build", imageName)
+			return &config.BuildInfo{
+				ImageDetails:  imageDetails,
+				ImageMetadata: extendedBuildInfo.MetadataConfig,
+				ImageName:     imageName,
+				PrebuildHash:  prebuildHash,
+				RegistryCache: options.RegistryCache,
+				Tags:          options.Tag,
+			}, nil
+		}
+		k.Log.Debugf("Error trying to find image %s: %v", imageName, err)
+	}
+
+	// check if we shouldn't build
+	if options.NoBuild {
+		return nil, fmt.Errorf("you cannot build in this mode. Please run 'devpod up' to rebuild the container")
+	}
+
+	// get build options
+	buildOptions, err := build.NewOptions(dockerfilePath, dockerfileContent, parsedConfig, extendedBuildInfo, imageName, options, prebuildHash)
+	if err != nil {
+		return nil, err
+	}
+	buildOptions.Load = false
+	buildOptions.Push = true
+
+	// start the builder, a builder left over from an interrupted build is replaced
+	podName := getBuildkitPodName(localWorkspaceFolder)
+	err = k.waitPodDeleted(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+	err = k.createBuildkitPod(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := k.client.Client().CoreV1().Pods(k.namespace).Delete(context.WithoutCancel(ctx), podName, metav1.DeleteOptions{
+			GracePeriodSeconds: ptr.To(int64(0)),
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			k.Log.Warnf("Error deleting buildkit pod '%s': %v", podName, err)
+		}
+	}()
+	_, err = k.waitPodRunning(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	buildkitClient, err := k.newBuildkitClient(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+	defer buildkitClient.Close()
+
+	platform := options.Platform
+	if platform == "" {
+		platform = "linux/" + targetArch
+	}
+
+	// build image
+	k.Log.Infof("Build %s in pod '%s'...", imageName, podName)
+	writer := k.Log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+	err = buildkit.Build(ctx, buildkitClient, writer, platform, buildOptions, k.Log)
+	if err != nil {
+		return nil, errors.Wrap(err, "build")
+	}
+
+	imageDetails, err := getImageDetails(ctx, imageName, targetArch)
+	if err != nil {
+		return nil, errors.Wrap(err, "get image details")
+	}
+
+	return &config.BuildInfo{
+		ImageDetails:  imageDetails,
+		ImageMetadata: extendedBuildInfo.MetadataConfig,
+		ImageName:     imageName,
+		PrebuildHash:  prebuildHash,
+		RegistryCache: options.RegistryCache,
+		Tags:          options.Tag,
+	}, nil
+}
+
+func (k *KubernetesDriver) createBuildkitPod(ctx context.Context, podName string) error {
+	// namespace
+	if k.namespace != "" && k.options.CreateNamespace == "true" {
+		err := k.createNamespace(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	nodeSelector, err := getNodeSelector(&corev1.Pod{}, k.options.NodeSelector)
+	if err != nil {
+		return err
+	}
+
+	k.Log.Infof("Create buildkit pod '%s'", podName)
+	_, err = k.client.Client().CoreV1().Pods(k.namespace).Create(ctx, getBuildkitPod(podName, k.options.BuildkitImage, nodeSelector), metav1.CreateOptions{})
+	if kerrors.IsForbidden(err) {
+		return fmt.Errorf("the buildkit pod was rejected, building in the cluster needs a namespace that allows unconfined seccomp and AppArmor profiles, e.g. with the privileged Pod Security Standard. Use another namespace or remove BUILD_REPOSITORY to build with dockerless: %w", err)
+	} else if err != nil {
+		return fmt.Errorf("create buildkit pod: %w", err)
+	}
+
+	return nil
+}
+
+func getBuildkitPod(podName, buildkitImage string, nodeSelector map[string]string) *corev1.Pod {
+	if buildkitImage == "" {
+		buildkitImage = DefaultBuildkitImage
+	}
+
+	labels := map[string]string{}
+	for k, v := range ExtraDevPodLabels {
+		labels[k] = v
+	}
+
+	// rootless buildkit needs to create user namespaces, which the default seccomp and AppArmor profiles forbid, see
+	// https://github.com/moby/buildkit/blob/master/docs/rootless.md#kubernetes. The unconfined profiles violate the
+	// baseline and restricted Pod Security Standards, so namespaces that enforce them reject the pod.
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   podName,
+			Labels: labels,
+			Annotations: map[string]string{
+				"container.apparmor.security.beta.kubernetes.io/" + BuildkitContainerName: "unconfined",
+				ClusterAutoscalerSaveToEvictAnnotation:                                    "false",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  nodeSelector,
+			Containers: []corev1.Container{
+				{
+					Name:  BuildkitContainerName,
+					Image: buildkitImage,
+					Args:  []string{"--oci-worker-no-process-sandbox"},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							Exec: &corev1.ExecAction{Command: []string{"buildctl", "debug", "workers"}},
+						},
+						PeriodSeconds: 2,
+					},
+					SecurityContext: &corev1.SecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+						RunAsUser:      ptr.To(int64(1000)),
+						RunAsGroup:     ptr.To(int64(1000)),
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "buildkitd",
+							MountPath: "/home/user/.local/share/buildkit",
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "buildkitd",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newBuildkitClient connects to buildkitd in the pod, every connection is a separate `buildctl dial-stdio` exec
+func (k *KubernetesDriver) newBuildkitClient(ctx context.Context, podName string) (*buildkitclient.Client, error) {
+	buildkitClient, err := buildkitclient.New(ctx, "", buildkitclient.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return k.dialBuildkit(ctx, podName), nil
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "create buildkit client")
+	}
+
+	// buildkitd needs a moment to create its socket after the pod is running
+	err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+		_, err := buildkitClient.ListWorkers(ctx)
+		if err != nil {
+			k.Log.Debugf("Waiting for buildkit in pod '%s': %v", podName, err)
+			return false, nil
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		_ = buildkitClient.Close()
+		return nil, fmt.Errorf("wait for buildkit in pod '%s': %w", podName, err)
+	}
+
+	return buildkitClient, nil
+}
+
+func (k *KubernetesDriver) dialBuildkit(ctx context.Context, podName string) net.Conn {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	go func() {
+		stderr := &bytes.Buffer{}
+		err := k.client.Exec(context.WithoutCancel(ctx), &ExecStreamOptions{
+			Pod:       podName,
+			Namespace: k.namespace,
+			Container: BuildkitContainerName,
+			Command:   []string{"buildctl", "dial-stdio"},
+			Stdin:     stdinReader,
+			Stdout:    stdoutWriter,
+			Stderr:    stderr,
+		})
+		if err != nil {
+			err = fmt.Errorf("buildctl dial-stdio: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		_ = stdinReader.CloseWithError(err)
+		_ = stdoutWriter.CloseWithError(err)
+	}()
+
+	return stdio.NewStdioStream(stdoutReader, stdinWriter, false, 0)
+}
+
+func getImageDetails(ctx context.Context, imageName, arch string) (*config.ImageDetails, error) {
+	img, err := image.GetImageForArch(ctx, imageName, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "config file")
+	}
+
+	return &config.ImageDetails{
+		ID: imageName,
+		Config: config.ImageDetailsConfig{
+			User:       imageConfig.Config.User,
+			Env:        imageConfig.Config.Env,
+			Labels:     imageConfig.Config.Labels,
+			Entrypoint: imageConfig.Config.Entrypoint,
+			Cmd:        imageConfig.Config.Cmd,
+		},
+	}, nil
+}
+
+func getBuildkitPodName(localWorkspaceFolder string) string {
+	return "devpod-buildkit-" + hash.String(localWorkspaceFolder)[:10]
+}
@@ -0,0 +1,43 @@
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetBuildkitPod(t *testing.T) {
+	pod := getBuildkitPod("devpod-buildkit-test", "", map[string]string{"kubernetes.io/arch": "amd64"})
+	assert.Equal(t, pod.Name, "devpod-buildkit-test")
+	assert.Equal(t, pod.Annotations["container.apparmor.security.beta.kubernetes.io/"+BuildkitContainerName], "unconfined")
+	assert.Equal(t, pod.Spec.RestartPolicy, corev1.RestartPolicyNever)
+	assert.DeepEqual(t, pod.Spec.NodeSelector, map[string]string{"kubernetes.io/arch": "amd64"})
+	assert.Equal(t, len(pod.Spec.Containers), 1)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, container.Name, BuildkitContainerName)
+	assert.Equal(t, container.Image, DefaultBuildkitImage)
+	assert.DeepEqual(t, container.Args, []string{"--oci-worker-no-process-sandbox"})
+	assert.Equal(t, container.SecurityContext.SeccompProfile.Type, corev1.SeccompProfileTypeUnconfined)
+	assert.Equal(t, *container.SecurityContext.RunAsUser, int64(1000))
+	assert.Equal(t, container.VolumeMounts[0].Name, pod.Spec.Volumes[0].Name)
+
+	pod = getBuildkitPod("devpod-buildkit-test", "my-registry/buildkit:rootless", nil)
+	assert.Equal(t, pod.Spec.Containers[0].Image, "my-registry/buildkit:rootless")
+}
+
+func TestCreateBuildkitPodRejected(t *testing.T) {
+	k := newTestDriver(t, &provider2.ProviderKubernetesDriverConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403,"message":"pods \"devpod-buildkit-test\" is forbidden: violates PodSecurity \"baseline:latest\""}`))
+	})
+
+	err := k.createBuildkitPod(context.Background(), "devpod-buildkit-test")
+	assert.ErrorContains(t, err, "the buildkit pod was rejected")
+	assert.ErrorContains(t, err, "violates PodSecurity")
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -15,7 +16,8 @@ import (
 )
 
 type Client struct {
-	client *kubernetes.Clientset
+	client        *kubernetes.Clientset
+	dynamicClient dynamic.Interface
 
 	config *rest.Config
 }
@@ -55,9 +57,15 @@ func NewClient(kubeConfig, kubeContext string) (*Client, string, error) {
 		return nil, "", err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, "", err
+	}
+
 	return &Client{
-		client: kubeClient,
-		config: clientConfig,
+		client:        kubeClient,
+		dynamicClient: dynamicClient,
+		config:        clientConfig,
 	}, namespace, nil
 }
 
@@ -65,6 +73,11 @@ func (c *Client) Client() *kubernetes.Clientset {
 	return c.client
 }
 
+// Dynamic returns a client for resources without typed clients, e.g. CSI VolumeSnapshots
+func (c *Client) Dynamic() dynamic.Interface {
+	return c.dynamicClient
+}
+
 func (c *Client) Config() *rest.Config {
 	return c.config
 }
@@ -36,9 +36,23 @@ func NewKubernetesDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Lo
 	}
 	log.Debugf("Use Kubernetes Namespace '%s'", namespace)
 
+	// the up flags take precedence over the provider options
+	if workspaceInfo.CLIOptions.PvcRetentionPolicy != "" {
+		options.PvcRetentionPolicy = workspaceInfo.CLIOptions.PvcRetentionPolicy
+	}
+	if workspaceInfo.CLIOptions.GoldenSnapshot != "" {
+		options.GoldenSnapshot = workspaceInfo.CLIOptions.GoldenSnapshot
+	}
+	switch options.PvcRetentionPolicy {
+	case "", PvcRetentionPolicyDelete, PvcRetentionPolicyRetain, PvcRetentionPolicySnapshot:
+	default:
+		return nil, fmt.Errorf("unknown pvc retention policy '%s', expected one of %s, %s or %s", options.PvcRetentionPolicy, PvcRetentionPolicyDelete, PvcRetentionPolicyRetain, PvcRetentionPolicySnapshot)
+	}
+
 	return &KubernetesDriver{
 		client:    client,
 		namespace: namespace,
+		workspace: workspaceInfo.Workspace,
 
 		options: &options,
 		Log:     log,
@@ -50,6 +64,8 @@ type KubernetesDriver struct {
 
 	client *Client
 
+	workspace *provider2.Workspace
+
 	options *provider2.ProviderKubernetesDriverConfig
 	Log     log.Logger
 }
@@ -100,6 +116,7 @@ func (k *KubernetesDriver) DeleteDevContainer(ctx context.Context, workspaceId s
 	k.Log.Debugf("Deleting devcontainer for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done deleting devcontainer for workspace '%s'", workspaceId)
 
+	rawWorkspaceId := workspaceId
 	workspaceId = getID(workspaceId)
 
 	// delete pod
@@ -109,13 +126,22 @@ func (k *KubernetesDriver) DeleteDevContainer(ctx context.Context, workspaceId s
 		return err
 	}
 
+	// delete service & ingress of the forwarded ports
+	err = k.deleteExposedPorts(ctx, workspaceId)
+	if err != nil {
+		return err
+	}
+
 	// delete pvc
-	k.Log.Infof("Delete persistent volume claim '%s'...", workspaceId)
-	err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Delete(ctx, workspaceId, metav1.DeleteOptions{
-		GracePeriodSeconds: &[]int64{5}[0],
-	})
-	if err != nil && !kerrors.IsNotFound(err) {
-		return perrors.Wrap(err, "delete pvc")
+	err = k.deletePersistentVolumeClaim(ctx, rawWorkspaceId)
+	if err != nil {
+		return err
+	}
+
+	// delete retained pvcs of other workspaces
+	err = k.deleteExpiredPersistentVolumeClaims(ctx)
+	if err != nil {
+		k.Log.Warnf("Error deleting expired persistent volume claims: %v", err)
 	}
 
 	// delete role binding & service account
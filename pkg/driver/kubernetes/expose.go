@@ -0,0 +1,200 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+)
+
+// exposePorts makes the forwardPorts of the workspace reachable through a Service and optionally an Ingress,
+// so they can be shared without a port forwarding. Objects are removed again if the ports are not exposed anymore.
+func (k *KubernetesDriver) exposePorts(ctx context.Context, id string, options *driver.RunOptions) error {
+	exposeService := k.options.ExposePorts == "true" || k.options.IngressHost != ""
+	if !exposeService || len(options.ForwardPorts) == 0 || options.UID == "" {
+		return k.deleteExposedPorts(ctx, id)
+	}
+
+	service := getService(id, options)
+	k.Log.Debugf("Expose ports of workspace through service '%s'", id)
+	err := k.applyService(ctx, service)
+	if err != nil {
+		return fmt.Errorf("apply service: %w", err)
+	}
+
+	if k.options.IngressHost == "" {
+		return k.deleteIngress(ctx, id)
+	}
+
+	annotations, err := parseLabels(k.options.IngressAnnotations)
+	if err != nil {
+		return fmt.Errorf("parse ingress annotations: %w", err)
+	}
+	ingress, err := getIngress(id, options.UID, options.ForwardPorts, k.options.IngressHost, k.options.IngressClassName, k.options.IngressTLSSecret, annotations)
+	if err != nil {
+		return err
+	}
+	err = k.applyIngress(ctx, ingress)
+	if err != nil {
+		return fmt.Errorf("apply ingress: %w", err)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		k.Log.Infof("Port %d is available at %s", rule.HTTP.Paths[0].Backend.Service.Port.Number, rule.Host)
+	}
+
+	return nil
+}
+
+// deleteExposedPorts ignores missing permissions, as workspaces that don't expose ports don't need them
+func (k *KubernetesDriver) deleteExposedPorts(ctx context.Context, id string) error {
+	err := k.deleteIngress(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = k.client.Client().CoreV1().Services(k.namespace).Delete(ctx, id, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsForbidden(err) {
+		return fmt.Errorf("delete service: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) deleteIngress(ctx context.Context, id string) error {
+	err := k.client.Client().NetworkingV1().Ingresses(k.namespace).Delete(ctx, id, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsForbidden(err) {
+		return fmt.Errorf("delete ingress: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) applyService(ctx context.Context, service *corev1.Service) error {
+	existing, err := k.client.Client().CoreV1().Services(k.namespace).Get(ctx, service.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = k.client.Client().CoreV1().Services(k.namespace).Create(ctx, service, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	existing.Labels = service.Labels
+	existing.Spec.Selector = service.Spec.Selector
+	existing.Spec.Ports = service.Spec.Ports
+	_, err = k.client.Client().CoreV1().Services(k.namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+func (k *KubernetesDriver) applyIngress(ctx context.Context, ingress *networkingv1.Ingress) error {
+	existing, err := k.client.Client().NetworkingV1().Ingresses(k.namespace).Get(ctx, ingress.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = k.client.Client().NetworkingV1().Ingresses(k.namespace).Create(ctx, ingress, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	existing.Labels = ingress.Labels
+	existing.Annotations = ingress.Annotations
+	existing.Spec = ingress.Spec
+	_, err = k.client.Client().NetworkingV1().Ingresses(k.namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+func getService(id string, options *driver.RunOptions) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   id,
+			Labels: getExposeLabels(options.UID),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				DevPodWorkspaceUIDLabel: options.UID,
+			},
+		},
+	}
+	for _, port := range options.ForwardPorts {
+		servicePort := corev1.ServicePort{
+			Name:       "port-" + strconv.Itoa(port.Port),
+			Port:       int32(port.Port),
+			TargetPort: intstr.FromInt32(int32(port.Port)),
+		}
+		if port.Protocol != "" {
+			servicePort.AppProtocol = ptr.To(port.Protocol)
+		}
+		service.Spec.Ports = append(service.Spec.Ports, servicePort)
+	}
+
+	return service
+}
+
+// getIngress creates a rule for every port, the host template supports {port} and {workspace}
+func getIngress(id, uid string, ports []driver.ForwardPort, hostTemplate, ingressClassName, tlsSecret string, annotations map[string]string) (*networkingv1.Ingress, error) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        id,
+			Labels:      getExposeLabels(uid),
+			Annotations: annotations,
+		},
+	}
+	if ingressClassName != "" {
+		ingress.Spec.IngressClassName = &ingressClassName
+	}
+
+	hosts := []string{}
+	for _, port := range ports {
+		host := strings.NewReplacer(
+			"{port}", strconv.Itoa(port.Port),
+			"{workspace}", strings.TrimPrefix(id, "devpod-"),
+		).Replace(hostTemplate)
+		if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+			return nil, fmt.Errorf("ingress host %s of port %d is no valid host name, check the INGRESS_HOST template %s: %s", host, port.Port, hostTemplate, strings.Join(errs, ", "))
+		}
+		hosts = append(hosts, host)
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: id,
+									Port: networkingv1.ServiceBackendPort{Number: int32(port.Port)},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	if tlsSecret != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: hosts, SecretName: tlsSecret}}
+	}
+
+	return ingress, nil
+}
+
+func getExposeLabels(uid string) map[string]string {
+	labels := map[string]string{}
+	for k, v := range ExtraDevPodLabels {
+		labels[k] = v
+	}
+	if uid != "" {
+		labels[DevPodWorkspaceUIDLabel] = uid
+	}
+
+	return labels
+}
@@ -0,0 +1,38 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	"gotest.tools/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestGetIngress(t *testing.T) {
+	ports := []driver.ForwardPort{{Port: 3000}, {Port: 8080, Protocol: "https"}}
+	ingress, err := getIngress("devpod-my-workspace", "uid", ports, "{port}-{workspace}.dev.example.com", "nginx", "dev-tls", map[string]string{"a": "b"})
+	assert.NilError(t, err)
+
+	assert.Equal(t, ingress.Name, "devpod-my-workspace")
+	assert.Equal(t, ingress.Labels[DevPodWorkspaceUIDLabel], "uid")
+	assert.Equal(t, *ingress.Spec.IngressClassName, "nginx")
+	assert.Equal(t, len(ingress.Spec.Rules), 2)
+	assert.Equal(t, ingress.Spec.Rules[0].Host, "3000-my-workspace.dev.example.com")
+	assert.Equal(t, ingress.Spec.Rules[1].Host, "8080-my-workspace.dev.example.com")
+	assert.DeepEqual(t, ingress.Spec.Rules[1].HTTP.Paths[0].Backend.Service, &networkingv1.IngressServiceBackend{
+		Name: "devpod-my-workspace",
+		Port: networkingv1.ServiceBackendPort{Number: 8080},
+	})
+	assert.DeepEqual(t, ingress.Spec.TLS, []networkingv1.IngressTLS{{
+		Hosts:      []string{"3000-my-workspace.dev.example.com", "8080-my-workspace.dev.example.com"},
+		SecretName: "dev-tls",
+	}})
+
+	_, err = getIngress("devpod-my-workspace", "uid", ports, "{port}_{workspace}.dev.example.com", "", "", nil)
+	assert.ErrorContains(t, err, "ingress host 3000_my-workspace.dev.example.com of port 3000 is no valid host name")
+
+	service := getService("devpod-my-workspace", &driver.RunOptions{UID: "uid", ForwardPorts: ports})
+	assert.Equal(t, service.Spec.Selector[DevPodWorkspaceUIDLabel], "uid")
+	assert.Equal(t, service.Spec.Ports[1].Name, "port-8080")
+	assert.Equal(t, *service.Spec.Ports[1].AppProtocol, "https")
+}
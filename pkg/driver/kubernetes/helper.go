@@ -2,11 +2,8 @@ package kubernetes
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	"github.com/loft-sh/log"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -55,31 +52,6 @@ func parseResources(resourceString string, log log.Logger) corev1.ResourceRequir
 	}
 }
 
-func getPodTemplate(manifest string) (*corev1.Pod, error) {
-	// check if manifest is inline yaml
-	pod := &corev1.Pod{}
-	errInline := yaml.Unmarshal([]byte(manifest), pod)
-	if errInline == nil {
-		return pod, nil
-	}
-
-	// check if manifest is path
-	p, err := filepath.Abs(manifest)
-	if err != nil {
-		return nil, fmt.Errorf("parsing pod tempate failed failed: %w (inline) or %w (file)", errInline, err)
-	}
-	body, err := os.ReadFile(p)
-	if err != nil {
-		return nil, fmt.Errorf("parsing pod tempate failed failed: %w (inline) or %w (file)", errInline, err)
-	}
-	err = yaml.Unmarshal(body, pod)
-	if err == nil {
-		return pod, nil
-	}
-
-	return nil, fmt.Errorf("parsing pod tempate failed failed: %w (inline) or %w (file)", errInline, err)
-}
-
 func parseLabels(str string) (map[string]string, error) {
 	if str == "" {
 		return nil, nil
@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// ValidateHostRequirements checks if there is at least one schedulable node that can run the workspace. Storage
+// is not validated against the node, because the workspace lives on a persistent volume claim that is sized accordingly.
+func (k *KubernetesDriver) ValidateHostRequirements(ctx context.Context, workspaceID string, requirements *config.HostRequirements) error {
+	if requirements == nil || (requirements.CPUs == 0 && requirements.Memory == "" && !requirements.RequiresGPU()) {
+		return nil
+	}
+
+	nodeSelector, err := parseLabels(k.options.NodeSelector)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := k.client.Client().CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(nodeSelector).String(),
+	})
+	if err != nil {
+		if kerrors.IsForbidden(err) {
+			k.Log.Warnf("Skip validating hostRequirements, because you don't have permission to list nodes in the Kubernetes cluster")
+			return nil
+		}
+
+		return fmt.Errorf("list nodes: %w", err)
+	}
+
+	var firstErr error
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+
+		gpu := false
+		if quantity, ok := node.Status.Allocatable[GPUResourceName]; ok {
+			gpu = !quantity.IsZero()
+		}
+		err := config.ValidateHostRequirements(&config.HostRequirements{
+			CPUs:   requirements.CPUs,
+			Memory: requirements.Memory,
+			GPU:    requirements.GPU,
+		}, &config.HostResources{
+			CPUs:   int(node.Status.Allocatable.Cpu().Value()),
+			Memory: node.Status.Allocatable.Memory().Value(),
+			GPU:    &gpu,
+		}, "node "+node.Name)
+		if err == nil {
+			return nil
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		return fmt.Errorf("no schedulable node found in the Kubernetes cluster to validate the hostRequirements of the devcontainer.json against")
+	}
+
+	return fmt.Errorf("none of the Kubernetes nodes can run the workspace, e.g. %w", firstErr)
+}
+
+// applyHostRequirements raises the resource requests of the dev container to the host requirements. Limits
+// that are lower than the new requests are raised as well.
+func applyHostRequirements(resources corev1.ResourceRequirements, requirements *config.HostRequirements) (corev1.ResourceRequirements, error) {
+	if requirements == nil {
+		return resources, nil
+	}
+
+	memory, err := requirements.MemoryBytes()
+	if err != nil {
+		return resources, err
+	}
+
+	requests := corev1.ResourceList{}
+	if requirements.CPUs > 0 {
+		requests[corev1.ResourceCPU] = *resource.NewQuantity(int64(requirements.CPUs), resource.DecimalSI)
+	}
+	if memory > 0 {
+		requests[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+	}
+	if len(requests) == 0 && !requirements.RequiresGPU() {
+		return resources, nil
+	}
+
+	resources = *resources.DeepCopy()
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	for name, quantity := range requests {
+		if existing, ok := resources.Requests[name]; !ok || existing.Cmp(quantity) < 0 {
+			resources.Requests[name] = quantity
+		}
+		if existing, ok := resources.Limits[name]; ok && existing.Cmp(resources.Requests[name]) < 0 {
+			resources.Limits[name] = resources.Requests[name]
+		}
+	}
+
+	// extended resources such as GPUs are requested through limits
+	if requirements.RequiresGPU() {
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		if _, ok := resources.Limits[GPUResourceName]; !ok {
+			resources.Limits[GPUResourceName] = *resource.NewQuantity(1, resource.DecimalSI)
+		}
+	}
+
+	return resources, nil
+}
@@ -34,7 +34,9 @@ func (k *KubernetesDriver) getInitContainers(options *driver.RunOptions, pod *co
 		copyFrom := volumeMount.MountPath
 		volumeMount.MountPath = "/" + volumeMount.SubPath
 		volumeMounts = append(volumeMounts, volumeMount)
-		commands = append(commands, fmt.Sprintf(`cp -a %s/. %s/ || true`, strings.TrimRight(copyFrom, "/"), strings.TrimRight(volumeMount.MountPath, "/")))
+		// volumes restored from a snapshot already have their content
+		target := strings.TrimRight(volumeMount.MountPath, "/")
+		commands = append(commands, fmt.Sprintf(`[ -n "$(ls -A %s)" ] || cp -a %s/. %s/ || true`, target, strings.TrimRight(copyFrom, "/"), target))
 	}
 
 	retContainers := []corev1.Container{}
@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/template"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const (
+	PodPatchTypeStrategic = "strategic"
+	PodPatchTypeMerge     = "merge"
+	PodPatchTypeJSON      = "json"
+)
+
+// PodTemplateValues can be used in the pod manifest template and the pod patches, e.g. {{ .WorkspaceID }}
+type PodTemplateValues struct {
+	WorkspaceID  string
+	WorkspaceUID string
+
+	// User is the user the devcontainer runs as
+	User string
+
+	Repository string
+	Branch     string
+	Commit     string
+
+	// DevContainer are the run options of the devcontainer, e.g. {{ .DevContainer.Image }}
+	DevContainer *driver.RunOptions
+}
+
+// PodPatch is applied to the pod after DevPod built it
+type PodPatch struct {
+	// Type is one of strategic, merge or json, defaults to strategic
+	Type string `json:"type,omitempty"`
+
+	// Patch is a strategic merge patch, a JSON merge patch or a list of JSON patch operations
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+func (k *KubernetesDriver) getPodTemplateValues(id string, options *driver.RunOptions) *PodTemplateValues {
+	values := &PodTemplateValues{
+		WorkspaceID:  strings.TrimPrefix(id, "devpod-"),
+		WorkspaceUID: options.UID,
+		User:         options.User,
+		DevContainer: options,
+	}
+	if k.workspace != nil {
+		values.WorkspaceID = k.workspace.ID
+		values.Repository = k.workspace.Source.GitRepository
+		values.Branch = k.workspace.Source.GitBranch
+		values.Commit = k.workspace.Source.GitCommit
+	}
+
+	return values
+}
+
+func getPodTemplate(manifest string, values *PodTemplateValues) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	err := renderManifest(manifest, values, pod)
+	if err != nil {
+		return nil, fmt.Errorf("parse pod template: %w", err)
+	}
+
+	return pod, nil
+}
+
+func getPodPatches(manifest string, values *PodTemplateValues) ([]PodPatch, error) {
+	patches := []PodPatch{}
+	err := renderManifest(manifest, values, &patches)
+	if err != nil {
+		return nil, fmt.Errorf("parse pod patches: %w", err)
+	}
+
+	return patches, nil
+}
+
+// applyPodPatches applies the patches in order, so later patches see the changes of earlier ones
+func applyPodPatches(pod *corev1.Pod, patches []PodPatch) (*corev1.Pod, error) {
+	podJSON, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, patch := range patches {
+		switch patch.Type {
+		case "", PodPatchTypeStrategic:
+			podJSON, err = strategicpatch.StrategicMergePatch(podJSON, patch.Patch, corev1.Pod{})
+		case PodPatchTypeMerge:
+			podJSON, err = jsonpatch.MergePatch(podJSON, patch.Patch)
+		case PodPatchTypeJSON:
+			var jsonPatch jsonpatch.Patch
+			jsonPatch, err = jsonpatch.DecodePatch(patch.Patch)
+			if err == nil {
+				podJSON, err = jsonPatch.Apply(podJSON)
+			}
+		default:
+			err = fmt.Errorf("unknown patch type '%s', expected one of %s, %s or %s", patch.Type, PodPatchTypeStrategic, PodPatchTypeMerge, PodPatchTypeJSON)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("apply pod patch %d: %w", idx, err)
+		}
+	}
+
+	patchedPod := &corev1.Pod{}
+	err = json.Unmarshal(podJSON, patchedPod)
+	if err != nil {
+		return nil, fmt.Errorf("decode patched pod: %w", err)
+	}
+
+	return patchedPod, nil
+}
+
+// renderManifest renders the manifest as go template and decodes it into obj. The manifest is either
+// inline yaml or the path to a yaml file.
+func renderManifest(manifest string, values *PodTemplateValues, obj interface{}) error {
+	// check if manifest is inline yaml
+	errInline := renderYAML(manifest, values, obj)
+	if errInline == nil {
+		return nil
+	}
+
+	// check if manifest is path
+	p, err := filepath.Abs(manifest)
+	if err != nil {
+		return fmt.Errorf("%w (inline) or %w (file)", errInline, err)
+	}
+	body, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("%w (inline) or %w (file)", errInline, err)
+	}
+	err = renderYAML(string(body), values, obj)
+	if err != nil {
+		return fmt.Errorf("%w (inline) or %w (file)", errInline, err)
+	}
+
+	return nil
+}
+
+func renderYAML(manifest string, values *PodTemplateValues, obj interface{}) error {
+	rendered, err := template.FillTemplate(manifest, values)
+	if err != nil {
+		return fmt.Errorf("render template: %w", err)
+	}
+
+	return yaml.Unmarshal([]byte(rendered), obj)
+}
@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodTemplatePatches(t *testing.T) {
+	values := &PodTemplateValues{
+		WorkspaceID:  "my-workspace",
+		Repository:   "github.com/loft-sh/devpod",
+		DevContainer: &driver.RunOptions{Image: "golang:1.23"},
+	}
+
+	pod, err := getPodTemplate(`
+metadata:
+  labels:
+    workspace: "{{ .WorkspaceID }}"
+spec:
+  containers:
+  - name: devpod
+    image: "{{ .DevContainer.Image }}"
+  - name: sidecar
+    image: busybox
+`, values)
+	assert.NilError(t, err)
+	assert.Equal(t, pod.Labels["workspace"], "my-workspace")
+	assert.Equal(t, pod.Spec.Containers[0].Image, "golang:1.23")
+
+	patches, err := getPodPatches(`
+- patch:
+    metadata:
+      annotations:
+        repository: "{{ .Repository }}"
+    spec:
+      tolerations:
+      - key: gpu
+        operator: Exists
+      containers:
+      - name: devpod
+        workingDir: /workspaces
+- type: merge
+  patch:
+    spec:
+      priorityClassName: dev
+- type: json
+  patch:
+  - op: remove
+    path: /spec/containers/1
+`, values)
+	assert.NilError(t, err)
+
+	pod, err = applyPodPatches(pod, patches)
+	assert.NilError(t, err)
+	assert.Equal(t, pod.Annotations["repository"], "github.com/loft-sh/devpod")
+	assert.DeepEqual(t, pod.Spec.Tolerations, []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}})
+	assert.Equal(t, pod.Spec.PriorityClassName, "dev")
+	assert.Equal(t, len(pod.Spec.Containers), 1)
+	assert.Equal(t, pod.Spec.Containers[0].Image, "golang:1.23")
+	assert.Equal(t, pod.Spec.Containers[0].WorkingDir, "/workspaces")
+
+	_, err = applyPodPatches(pod, []PodPatch{{Type: "unknown", Patch: []byte("{}")}})
+	assert.Error(t, err, "apply pod patch 0: unknown patch type 'unknown', expected one of strategic, merge or json")
+}
@@ -4,12 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/loft-sh/devpod/pkg/driver"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	PvcRetentionPolicyDelete   = "Delete"
+	PvcRetentionPolicyRetain   = "Retain"
+	PvcRetentionPolicySnapshot = "Snapshot"
+
+	DevPodRetainedLabel                = "devpod.sh/retained"
+	DevPodRetainedAtAnnotation         = "devpod.sh/retained-at"
+	DevPodPvcRetentionPolicyAnnotation = "devpod.sh/pvc-retention-policy"
 )
 
 func (k *KubernetesDriver) createPersistentVolumeClaim(
@@ -46,6 +61,14 @@ func (k *KubernetesDriver) buildPersistentVolumeClaim(
 		return nil, errors.Wrapf(err, "parse persistent volume size '%s'", size)
 	}
 
+	// make sure the workspace gets at least the storage it requires
+	storage, err := options.HostRequirements.StorageBytes()
+	if err != nil {
+		return nil, err
+	} else if storage > quantity.Value() {
+		quantity = *resource.NewQuantity(storage, resource.BinarySI)
+	}
+
 	var storageClassName *string
 	if k.options.StorageClass != "" {
 		storageClassName = &k.options.StorageClass
@@ -74,6 +97,9 @@ func (k *KubernetesDriver) buildPersistentVolumeClaim(
 
 	annotations := map[string]string{}
 	annotations[DevPodInfoAnnotation] = containerInfo
+	if k.options.PvcRetentionPolicy != "" {
+		annotations[DevPodPvcRetentionPolicyAnnotation] = k.options.PvcRetentionPolicy
+	}
 	extraAnnotations, err := parseLabels(k.options.PvcAnnotations)
 	if err != nil {
 		k.Log.Error("Failed to parse annotations from PVC_ANNOTATIONS option: %v", err)
@@ -104,6 +130,144 @@ func (k *KubernetesDriver) buildPersistentVolumeClaim(
 	}, nil
 }
 
+// deletePersistentVolumeClaim applies the retention policy the workspace volume was created with. The workspace is
+// deleted without the up flags, so the policy is read from the persistent volume claim.
+func (k *KubernetesDriver) deletePersistentVolumeClaim(ctx context.Context, workspaceId string) error {
+	id := getID(workspaceId)
+	pvc, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrap(err, "get pvc")
+	}
+
+	switch getPvcRetentionPolicy(pvc, k.options.PvcRetentionPolicy) {
+	case PvcRetentionPolicyRetain:
+		k.Log.Infof("Keep persistent volume claim '%s'", id)
+		return k.patchPersistentVolumeClaimRetained(ctx, id, ptr.To(time.Now()))
+	case PvcRetentionPolicySnapshot:
+		err = k.archivePersistentVolumeClaim(ctx, workspaceId)
+		if err != nil {
+			return err
+		}
+	}
+
+	k.Log.Infof("Delete persistent volume claim '%s'...", id)
+	err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Delete(ctx, id, metav1.DeleteOptions{
+		GracePeriodSeconds: &[]int64{5}[0],
+	})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "delete pvc")
+	}
+
+	return nil
+}
+
+// getPvcRetentionPolicy returns the retention policy recorded on the persistent volume claim or the given default
+func getPvcRetentionPolicy(pvc *corev1.PersistentVolumeClaim, defaultPolicy string) string {
+	if policy := pvc.Annotations[DevPodPvcRetentionPolicyAnnotation]; policy != "" {
+		return policy
+	}
+
+	return defaultPolicy
+}
+
+// patchPersistentVolumeClaimRetained labels the persistent volume claim as retained at the given time, so it can be
+// cleaned up after the retention period. A nil time removes the label again when a workspace reuses the claim.
+func (k *KubernetesDriver) patchPersistentVolumeClaimRetained(ctx context.Context, id string, retainedAt *time.Time) error {
+	var label, annotation *string
+	if retainedAt != nil {
+		label, annotation = ptr.To("true"), ptr.To(retainedAt.UTC().Format(time.RFC3339))
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]*string{DevPodRetainedLabel: label},
+			"annotations": map[string]*string{DevPodRetainedAtAnnotation: annotation},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Patch(ctx, id, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrap(err, "patch pvc")
+	}
+
+	return nil
+}
+
+// deleteExpiredPersistentVolumeClaims deletes the retained persistent volume claims whose retention period is over
+func (k *KubernetesDriver) deleteExpiredPersistentVolumeClaims(ctx context.Context) error {
+	if k.options.PvcRetentionPeriod == "" {
+		return nil
+	}
+
+	period, err := time.ParseDuration(k.options.PvcRetentionPeriod)
+	if err != nil {
+		return errors.Wrapf(err, "parse pvc retention period '%s'", k.options.PvcRetentionPeriod)
+	}
+
+	pvcs, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: DevPodRetainedLabel + "=true",
+	})
+	if err != nil {
+		return errors.Wrap(err, "list retained pvcs")
+	}
+
+	for _, pvc := range pvcs.Items {
+		if !isRetentionExpired(&pvc, period, time.Now()) {
+			continue
+		}
+
+		k.Log.Infof("Delete persistent volume claim '%s', it was retained for more than %s", pvc.Name, period)
+		err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "delete pvc %s", pvc.Name)
+		}
+	}
+
+	return nil
+}
+
+// isRetentionExpired returns true if the persistent volume claim was retained longer than the period
+func isRetentionExpired(pvc *corev1.PersistentVolumeClaim, period time.Duration, now time.Time) bool {
+	retainedAt, err := time.Parse(time.RFC3339, pvc.Annotations[DevPodRetainedAtAnnotation])
+	return err == nil && now.Sub(retainedAt) > period
+}
+
+// archivePersistentVolumeClaim keeps the contents of the workspace volume as a snapshot before it is deleted
+func (k *KubernetesDriver) archivePersistentVolumeClaim(ctx context.Context, workspaceId string) error {
+	pvc, _, err := k.getDevContainerPvc(ctx, getID(workspaceId))
+	if err != nil {
+		return err
+	} else if pvc == nil {
+		return nil
+	}
+
+	name := getArchiveSnapshotName(workspaceId, time.Now())
+	k.Log.Infof("Archive persistent volume claim '%s' as snapshot '%s'", pvc.Name, name)
+	_, err = k.CreateSnapshot(ctx, workspaceId, name)
+	if err != nil {
+		return errors.Wrap(err, "archive pvc")
+	}
+
+	return nil
+}
+
+// getArchiveSnapshotName returns a snapshot name made of the workspace id and the time, shortened to a valid snapshot name
+func getArchiveSnapshotName(workspaceId string, now time.Time) string {
+	suffix := "-" + now.UTC().Format("20060102-150405")
+	if len(workspaceId)+len(suffix) > 63 {
+		workspaceId = strings.TrimRight(workspaceId[:63-len(suffix)], "-.")
+	}
+
+	return workspaceId + suffix
+}
+
 func (k *KubernetesDriver) getDevContainerInformation(
 	id string,
 	options *driver.RunOptions,
@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// newTestDriver returns a driver that talks to a stand-in api server with the given handler
+func newTestDriver(t *testing.T, options *provider2.ProviderKubernetesDriverConfig, handler http.HandlerFunc) *KubernetesDriver {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+
+	return &KubernetesDriver{
+		namespace: "test",
+		client:    &Client{client: clientset},
+		options:   options,
+		Log:       log.Discard,
+	}
+}
+
+func TestGetArchiveSnapshotName(t *testing.T) {
+	now := time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC)
+	assert.Equal(t, getArchiveSnapshotName("my-workspace", now), "my-workspace-20240517-083000")
+
+	name := getArchiveSnapshotName(strings.Repeat("a", 46)+"-"+strings.Repeat("b", 40), now)
+	assert.Equal(t, name, strings.Repeat("a", 46)+"-20240517-083000")
+	assert.NilError(t, driver.ValidateSnapshotName(name))
+}
+
+func TestGetPvcRetentionPolicy(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	assert.Equal(t, getPvcRetentionPolicy(pvc, PvcRetentionPolicyDelete), PvcRetentionPolicyDelete)
+
+	pvc.Annotations = map[string]string{DevPodPvcRetentionPolicyAnnotation: PvcRetentionPolicyRetain}
+	assert.Equal(t, getPvcRetentionPolicy(pvc, PvcRetentionPolicyDelete), PvcRetentionPolicyRetain)
+}
+
+func TestIsRetentionExpired(t *testing.T) {
+	now := time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{DevPodRetainedAtAnnotation: "2024-05-10T08:30:00Z"},
+		},
+	}
+	assert.Assert(t, isRetentionExpired(pvc, 24*time.Hour, now))
+	assert.Assert(t, !isRetentionExpired(pvc, 30*24*time.Hour, now))
+
+	pvc.Annotations = nil
+	assert.Assert(t, !isRetentionExpired(pvc, time.Hour, now))
+}
+
+func TestDeletePersistentVolumeClaimRetain(t *testing.T) {
+	requests := []string{}
+	var patch string
+	k := newTestDriver(t, &provider2.ProviderKubernetesDriverConfig{PvcRetentionPolicy: PvcRetentionPolicyDelete}, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		if r.Method == http.MethodPatch {
+			body, _ := io.ReadAll(r.Body)
+			patch = string(body)
+		}
+
+		// the policy recorded when the workspace was created applies
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"PersistentVolumeClaim","apiVersion":"v1","metadata":{"name":"my-workspace","annotations":{"devpod.sh/pvc-retention-policy":"Retain"}}}`))
+	})
+
+	err := k.deletePersistentVolumeClaim(context.Background(), "my-workspace")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requests, []string{http.MethodGet, http.MethodPatch})
+	assert.Assert(t, strings.Contains(patch, `"devpod.sh/retained":"true"`), patch)
+	assert.Assert(t, strings.Contains(patch, `"devpod.sh/retained-at":"`), patch)
+}
+
+func TestGetInitContainers(t *testing.T) {
+	k := &KubernetesDriver{options: &provider2.ProviderKubernetesDriverConfig{}}
+	options := &driver.RunOptions{Image: "golang"}
+	options.Mounts = append(options.Mounts, &config.Mount{Type: "volume", Source: "go-cache", Target: "/go/pkg/mod"})
+
+	initContainers, err := k.getInitContainers(options, &corev1.Pod{}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(initContainers), 1)
+	assert.DeepEqual(t, initContainers[0].Args, []string{"-c", `[ -n "$(ls -A /devpod/go-cache)" ] || cp -a /go/pkg/mod/. /devpod/go-cache/ || true` + "\n"})
+
+	initContainers, err = k.getInitContainers(options, &corev1.Pod{}, false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(initContainers), 0)
+}
@@ -66,13 +66,23 @@ func (k *KubernetesDriver) RunDevContainer(
 			return fmt.Errorf("no options provided and no persistent volume claim found for workspace '%s'", workspaceId)
 		}
 
-		// create persistent volume claim
-		err = k.createPersistentVolumeClaim(ctx, workspaceId, options)
+		// create persistent volume claim, the init container only fills the volumes a golden snapshot doesn't contain
+		if k.options.GoldenSnapshot != "" {
+			err = k.createPersistentVolumeClaimFromSnapshot(ctx, workspaceId, k.options.GoldenSnapshot, options)
+		} else {
+			err = k.createPersistentVolumeClaim(ctx, workspaceId, options)
+		}
 		if err != nil {
 			return err
 		}
 
 		initialize = true
+	} else if pvc.Labels[DevPodRetainedLabel] != "" {
+		// the workspace reuses a retained persistent volume claim
+		err = k.patchPersistentVolumeClaimRetained(ctx, workspaceId, nil)
+		if err != nil {
+			return err
+		}
 	}
 
 	// reuse driver.RunOptions from existing workspace if none provided
@@ -119,9 +129,10 @@ func (k *KubernetesDriver) runContainer(
 			RestartPolicy: corev1.RestartPolicyNever,
 		},
 	}
+	templateValues := k.getPodTemplateValues(id, options)
 	if len(k.options.PodManifestTemplate) > 0 {
 		k.Log.Debugf("trying to get pod template manifest from %s", k.options.PodManifestTemplate)
-		pod, err = getPodTemplate(k.options.PodManifestTemplate)
+		pod, err = getPodTemplate(k.options.PodManifestTemplate, templateValues)
 		if err != nil {
 			return err
 		}
@@ -133,6 +144,18 @@ func (k *KubernetesDriver) runContainer(
 		return errors.Wrap(err, "build init container")
 	}
 
+	// services of a docker compose devcontainer run as sidecars
+	var serviceVolumes []corev1.Volume
+	var serviceHostAliases []corev1.HostAlias
+	if len(options.Services) > 0 {
+		var serviceContainers []corev1.Container
+		serviceContainers, serviceVolumes, serviceHostAliases, err = k.getServiceContainers(options.Services)
+		if err != nil {
+			return errors.Wrap(err, "build service containers")
+		}
+		initContainers = append(initContainers, serviceContainers...)
+	}
+
 	// loop over volume mounts
 	volumeMounts := []corev1.VolumeMount{getVolumeMount(0, mount)}
 	for idx, mount := range options.Mounts {
@@ -201,6 +224,10 @@ func (k *KubernetesDriver) runContainer(
 	if k.options.Resources != "" {
 		resources = parseResources(k.options.Resources, k.Log)
 	}
+	resources, err = applyHostRequirements(resources, options.HostRequirements)
+	if err != nil {
+		return err
+	}
 
 	// ensure daemon config secret
 	daemonConfigSecretName := ""
@@ -229,7 +256,8 @@ func (k *KubernetesDriver) runContainer(
 	pod.Spec.NodeSelector = nodeSelector
 	pod.Spec.InitContainers = initContainers
 	pod.Spec.Containers = getContainers(pod, options.Image, options.Entrypoint, options.Cmd, envVars, volumeMounts, capabilities, resources, options.Privileged, k.options.StrictSecurity, daemonConfigSecretName)
-	pod.Spec.Volumes = getVolumes(pod, id, daemonConfigSecretName)
+	pod.Spec.Volumes = append(getVolumes(pod, id, daemonConfigSecretName), serviceVolumes...)
+	pod.Spec.HostAliases = append(pod.Spec.HostAliases, serviceHostAliases...)
 	// avoids a problem where attaching volumes with large repositories would cause an extremely long pod startup time
 	// because changing the ownership of all files takes longer than the kubelet expects it to
 	if pod.Spec.SecurityContext == nil {
@@ -241,6 +269,27 @@ func (k *KubernetesDriver) runContainer(
 		pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: getPullSecretsName(id)}}
 	}
 	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	// apply pod patches
+	if len(k.options.PodPatches) > 0 {
+		k.Log.Debugf("trying to get pod patches from %s", k.options.PodPatches)
+		patches, err := getPodPatches(k.options.PodPatches, templateValues)
+		if err != nil {
+			return err
+		}
+
+		pod, err = applyPodPatches(pod, patches)
+		if err != nil {
+			return err
+		}
+	}
+
+	// expose forwarded ports
+	err = k.exposePorts(ctx, id, options)
+	if err != nil {
+		return errors.Wrap(err, "expose ports")
+	}
+
 	// try to get existing pod
 	existingPod, err := k.getPod(ctx, id)
 	if err != nil {
@@ -0,0 +1,212 @@
+package kubernetes
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+)
+
+var invalidContainerNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// RunsComposeServices returns true, the services of a docker compose devcontainer run as sidecars in the devcontainer pod
+func (k *KubernetesDriver) RunsComposeServices() bool {
+	return true
+}
+
+// getServiceContainers translates the services into native sidecar containers. Kubernetes starts them in order
+// before the devcontainer and waits for each startup probe, so sorting them by their dependencies keeps the
+// startup order of docker compose. All containers of a pod share the network, a host alias makes the services
+// reachable under their names. Native sidecars need Kubernetes v1.29 or newer. As the network is shared, two
+// services can't listen on the same port.
+func (k *KubernetesDriver) getServiceContainers(services []*driver.ServiceOptions) ([]corev1.Container, []corev1.Volume, []corev1.HostAlias, error) {
+	services, err := sortServices(services)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	containers := []corev1.Container{}
+	volumes := []corev1.Volume{}
+	hostNames := []string{}
+	portServices := map[int]string{}
+	for _, service := range services {
+		name := getServiceContainerName(service.Name)
+		container := corev1.Container{
+			Name:          name,
+			Image:         service.Image,
+			Command:       service.Entrypoint,
+			Args:          service.Cmd,
+			WorkingDir:    service.WorkingDir,
+			RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+			StartupProbe:  getServiceStartupProbe(service.HealthCheck),
+		}
+		for _, envName := range slices.Sorted(maps.Keys(service.Env)) {
+			container.Env = append(container.Env, corev1.EnvVar{Name: envName, Value: service.Env[envName]})
+		}
+		for _, port := range service.Ports {
+			if other, ok := portServices[port]; ok && other != service.Name {
+				return nil, nil, nil, fmt.Errorf("services '%s' and '%s' both use port %d, but share the network of the devcontainer pod", other, service.Name, port)
+			}
+			portServices[port] = service.Name
+			container.Ports = append(container.Ports, corev1.ContainerPort{ContainerPort: int32(port)})
+		}
+
+		// only numeric users can be set in kubernetes
+		if service.User != "" {
+			user, group, _ := strings.Cut(service.User, ":")
+			uid, err := strconv.ParseInt(user, 10, 64)
+			if err != nil {
+				k.Log.Warnf("Service '%s' uses non numeric user '%s', will use the user of the image", service.Name, service.User)
+			} else {
+				container.SecurityContext = &corev1.SecurityContext{RunAsUser: &uid}
+				if gid, err := strconv.ParseInt(group, 10, 64); err == nil {
+					container.SecurityContext.RunAsGroup = &gid
+				}
+			}
+		}
+
+		// named volumes live on the workspace volume like the volumes of the devcontainer, the rest is scratch space
+		for idx, mount := range service.Mounts {
+			switch {
+			case mount.Type == "volume" && mount.Source != "":
+				container.VolumeMounts = append(container.VolumeMounts, getVolumeMount(idx, mount))
+			case mount.Type == "volume" || mount.Type == "tmpfs":
+				volumeName := fmt.Sprintf("%s-%d", name, idx)
+				volume := corev1.Volume{
+					Name: volumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				}
+				if mount.Type == "tmpfs" {
+					volume.EmptyDir.Medium = corev1.StorageMediumMemory
+				}
+				volumes = append(volumes, volume)
+				container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+					Name:      volumeName,
+					MountPath: mount.Target,
+				})
+			default:
+				k.Log.Warnf("Unsupported mount type '%s' in mount '%s' of service '%s', will skip", mount.Type, mount.String(), service.Name)
+			}
+		}
+
+		containers = append(containers, container)
+		for _, hostName := range append([]string{service.Name}, service.Aliases...) {
+			hostName = strings.ToLower(hostName)
+			if errs := validation.IsDNS1123Subdomain(hostName); len(errs) > 0 {
+				k.Log.Warnf("Service '%s' is not reachable as '%s', because it is not a valid host name: %s", service.Name, hostName, strings.Join(errs, ", "))
+				continue
+			} else if slices.Contains(hostNames, hostName) {
+				continue
+			}
+
+			hostNames = append(hostNames, hostName)
+		}
+	}
+
+	return containers, volumes, []corev1.HostAlias{{IP: "127.0.0.1", Hostnames: hostNames}}, nil
+}
+
+// sortServices orders the services so that every service comes after the services it depends on
+func sortServices(services []*driver.ServiceOptions) ([]*driver.ServiceOptions, error) {
+	byName := map[string]*driver.ServiceOptions{}
+	for _, service := range services {
+		byName[service.Name] = service
+	}
+
+	sorted := []*driver.ServiceOptions{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+	var visit func(service *driver.ServiceOptions, path []string) error
+	visit = func(service *driver.ServiceOptions, path []string) error {
+		if visited[service.Name] {
+			return nil
+		} else if visiting[service.Name] {
+			return fmt.Errorf("services have a circular dependency: %s", strings.Join(append(path, service.Name), " -> "))
+		}
+
+		visiting[service.Name] = true
+		for _, dependency := range service.DependsOn {
+			if byName[dependency] == nil {
+				continue
+			}
+
+			err := visit(byName[dependency], append(path, service.Name))
+			if err != nil {
+				return err
+			}
+		}
+		visited[service.Name] = true
+		sorted = append(sorted, service)
+		return nil
+	}
+
+	for _, service := range services {
+		err := visit(service, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+func getServiceStartupProbe(healthCheck *driver.ServiceHealthCheck) *corev1.Probe {
+	if healthCheck == nil {
+		return nil
+	}
+
+	// zero values fall back to the kubernetes defaults
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: healthCheck.Command},
+		},
+		PeriodSeconds:    durationSeconds(healthCheck.Interval),
+		TimeoutSeconds:   durationSeconds(healthCheck.Timeout),
+		FailureThreshold: int32(healthCheck.Retries),
+	}
+
+	// failures during the start period don't count
+	if healthCheck.StartPeriod > 0 {
+		if probe.FailureThreshold == 0 {
+			probe.FailureThreshold = 3
+		}
+		period := healthCheck.Interval
+		if period <= 0 {
+			period = 10 * time.Second
+		}
+		probe.FailureThreshold += int32(math.Ceil(float64(healthCheck.StartPeriod) / float64(period)))
+	}
+
+	return probe
+}
+
+func getServiceContainerName(serviceName string) string {
+	name := strings.Trim(invalidContainerNameChars.ReplaceAllString(strings.ToLower(serviceName), "-"), "-")
+	if name == DevContainerName || name == InitContainerName {
+		name = "service-" + name
+	}
+	if len(name) > 50 {
+		name = strings.TrimRight(name[:50], "-")
+	}
+
+	return name
+}
+
+func durationSeconds(duration time.Duration) int32 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return int32(math.Ceil(duration.Seconds()))
+}
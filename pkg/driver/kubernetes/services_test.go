@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSortServices(t *testing.T) {
+	services := []*driver.ServiceOptions{
+		{Name: "app", DependsOn: []string{"cache", "db"}},
+		{Name: "cache", DependsOn: []string{"db", "missing"}},
+		{Name: "db"},
+	}
+
+	sorted, err := sortServices(services)
+	assert.NilError(t, err)
+	names := []string{}
+	for _, service := range sorted {
+		names = append(names, service.Name)
+	}
+	assert.DeepEqual(t, names, []string{"db", "cache", "app"})
+
+	services[2].DependsOn = []string{"app"}
+	_, err = sortServices(services)
+	assert.Error(t, err, "services have a circular dependency: app -> cache -> db -> app")
+}
+
+func TestGetServiceContainers(t *testing.T) {
+	k := &KubernetesDriver{Log: log.Discard}
+	containers, volumes, hostAliases, err := k.getServiceContainers([]*driver.ServiceOptions{
+		{
+			Name:      "web_app",
+			Image:     "nginx",
+			DependsOn: []string{"db"},
+			Mounts: []*config.Mount{
+				{Type: "tmpfs", Target: "/tmp"},
+				{Type: "bind", Source: "./nginx.conf", Target: "/etc/nginx/nginx.conf"},
+			},
+		},
+		{
+			Name:    "db",
+			Aliases: []string{"Postgres", "postgres_db"},
+			Image:   "postgres:16",
+			User:    "999:999",
+			Env:     map[string]string{"POSTGRES_PASSWORD": "postgres"},
+			Ports:   []int{5432},
+			Mounts: []*config.Mount{
+				{Type: "volume", Source: "db-data", Target: "/var/lib/postgresql/data"},
+			},
+			HealthCheck: &driver.ServiceHealthCheck{
+				Command:     []string{"pg_isready"},
+				Interval:    5 * time.Second,
+				StartPeriod: 10 * time.Second,
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 2)
+
+	db := containers[0]
+	assert.Equal(t, db.Name, "db")
+	assert.Equal(t, *db.RestartPolicy, corev1.ContainerRestartPolicyAlways)
+	assert.Equal(t, *db.SecurityContext.RunAsUser, int64(999))
+	assert.DeepEqual(t, db.Env, []corev1.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "postgres"}})
+	assert.DeepEqual(t, db.VolumeMounts, []corev1.VolumeMount{{Name: "devpod", MountPath: "/var/lib/postgresql/data", SubPath: "devpod/db-data"}})
+	assert.DeepEqual(t, db.StartupProbe.Exec.Command, []string{"pg_isready"})
+	assert.Equal(t, db.StartupProbe.PeriodSeconds, int32(5))
+	assert.Equal(t, db.StartupProbe.FailureThreshold, int32(5))
+
+	web := containers[1]
+	assert.Equal(t, web.Name, "web-app")
+	assert.Assert(t, web.StartupProbe == nil)
+	assert.DeepEqual(t, web.VolumeMounts, []corev1.VolumeMount{{Name: "web-app-0", MountPath: "/tmp"}})
+	assert.Equal(t, len(volumes), 1)
+	assert.Equal(t, volumes[0].EmptyDir.Medium, corev1.StorageMediumMemory)
+
+	assert.DeepEqual(t, hostAliases, []corev1.HostAlias{{IP: "127.0.0.1", Hostnames: []string{"db", "postgres"}}})
+}
+
+func TestGetServiceContainersPortConflict(t *testing.T) {
+	k := &KubernetesDriver{Log: log.Discard}
+	_, _, _, err := k.getServiceContainers([]*driver.ServiceOptions{
+		{Name: "web", Image: "nginx", Ports: []int{8080}},
+		{Name: "api", Image: "my-api", Ports: []int{3000, 8080}},
+	})
+	assert.Error(t, err, "services 'web' and 'api' both use port 8080, but share the network of the devcontainer pod")
+}
@@ -0,0 +1,312 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	"github.com/loft-sh/devpod/pkg/types"
+	perrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DevPodSnapshotLabel = "devpod.sh/snapshot"
+
+	snapshotPrefix = "devpod-snapshot-"
+)
+
+func getSnapshotID(name string) string {
+	return snapshotPrefix + name
+}
+
+// CreateSnapshot clones the workspace persistent volume claim. The storage class needs to support volume cloning.
+// If a volume snapshot class is configured, a CSI VolumeSnapshot is created instead.
+func (k *KubernetesDriver) CreateSnapshot(ctx context.Context, workspaceId, name string) (*driver.Snapshot, error) {
+	err := driver.ValidateSnapshotName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := k.GetSnapshot(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	pvc, _, err := k.getDevContainerPvc(ctx, getID(workspaceId))
+	if err != nil {
+		return nil, err
+	} else if pvc == nil {
+		return nil, fmt.Errorf("persistent volume claim for workspace %s not found", workspaceId)
+	}
+
+	labels := map[string]string{}
+	for k, v := range ExtraDevPodLabels {
+		labels[k] = v
+	}
+	labels[DevPodSnapshotLabel] = name
+	labels[DevPodWorkspaceLabel] = workspaceId
+	annotations := map[string]string{
+		DevPodInfoAnnotation: pvc.Annotations[DevPodInfoAnnotation],
+	}
+
+	if k.useVolumeSnapshots() {
+		volumeSnapshot, err := k.createVolumeSnapshot(ctx, getSnapshotID(name), pvc.Name, labels, annotations)
+		if err != nil {
+			return nil, err
+		}
+
+		return k.snapshotFromObject(volumeSnapshot)
+	}
+
+	snapshotPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getSnapshotID(name),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      pvc.Spec.AccessModes,
+			Resources:        pvc.Spec.Resources,
+			StorageClassName: pvc.Spec.StorageClassName,
+			VolumeMode:       pvc.Spec.VolumeMode,
+			DataSource: &corev1.TypedLocalObjectReference{
+				Kind: "PersistentVolumeClaim",
+				Name: pvc.Name,
+			},
+		},
+	}
+
+	k.Log.Infof("Clone persistent volume claim '%s' to '%s'", pvc.Name, snapshotPvc.Name)
+	snapshotPvc, err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Create(ctx, snapshotPvc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, perrors.Wrap(err, "create snapshot pvc")
+	}
+
+	return k.snapshotFromObject(snapshotPvc)
+}
+
+func (k *KubernetesDriver) GetSnapshot(ctx context.Context, name string) (*driver.Snapshot, error) {
+	object, err := k.findSnapshot(ctx, name)
+	if err != nil || object == nil {
+		return nil, err
+	}
+
+	return k.snapshotFromObject(object)
+}
+
+// findSnapshot returns the persistent volume claim or volume snapshot that holds the snapshot or nil if it doesn't exist
+func (k *KubernetesDriver) findSnapshot(ctx context.Context, name string) (metav1.Object, error) {
+	err := driver.ValidateSnapshotName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Get(ctx, getSnapshotID(name), metav1.GetOptions{})
+	if err == nil && pvc.Labels[DevPodSnapshotLabel] == name {
+		return pvc, nil
+	} else if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	} else if !k.useVolumeSnapshots() {
+		return nil, nil
+	}
+
+	volumeSnapshot, err := k.getVolumeSnapshot(ctx, getSnapshotID(name))
+	if err != nil {
+		return nil, err
+	} else if volumeSnapshot == nil || volumeSnapshot.GetLabels()[DevPodSnapshotLabel] != name {
+		return nil, nil
+	}
+
+	return volumeSnapshot, nil
+}
+
+func (k *KubernetesDriver) ListSnapshots(ctx context.Context) ([]*driver.Snapshot, error) {
+	pvcs, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: DevPodSnapshotLabel,
+	})
+	if err != nil {
+		return nil, perrors.Wrap(err, "list snapshot pvcs")
+	}
+
+	objects := []metav1.Object{}
+	for i := range pvcs.Items {
+		objects = append(objects, &pvcs.Items[i])
+	}
+	if k.useVolumeSnapshots() {
+		volumeSnapshots, err := k.listVolumeSnapshots(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range volumeSnapshots {
+			objects = append(objects, &volumeSnapshots[i])
+		}
+	}
+
+	snapshots := []*driver.Snapshot{}
+	for _, object := range objects {
+		snapshot, err := k.snapshotFromObject(object)
+		if err != nil {
+			k.Log.Warnf("Error reading snapshot %s: %v", object.GetName(), err)
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+func (k *KubernetesDriver) DeleteSnapshot(ctx context.Context, name string) error {
+	object, err := k.findSnapshot(ctx, name)
+	if err != nil {
+		return err
+	} else if object == nil {
+		return fmt.Errorf("snapshot %s doesn't exist", name)
+	}
+
+	if _, ok := object.(*unstructured.Unstructured); ok {
+		return k.deleteVolumeSnapshot(ctx, object.GetName())
+	}
+
+	k.Log.Infof("Delete persistent volume claim '%s'...", object.GetName())
+	err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Delete(ctx, object.GetName(), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return perrors.Wrap(err, "delete snapshot pvc")
+	}
+
+	return nil
+}
+
+// RestoreSnapshot replaces the workspace persistent volume claim with a clone of the snapshot.
+// The pod needs to be deleted before.
+func (k *KubernetesDriver) RestoreSnapshot(ctx context.Context, workspaceId, name string, options *driver.RunOptions) error {
+	snapshot, err := k.GetSnapshot(ctx, name)
+	if err != nil {
+		return err
+	} else if snapshot == nil {
+		return fmt.Errorf("snapshot %s doesn't exist", name)
+	}
+	if snapshot.Image != "" {
+		options.Image = snapshot.Image
+	}
+
+	workspaceId = getID(workspaceId)
+	err = k.waitPersistentVolumeClaimDeleted(ctx, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	return k.createPersistentVolumeClaimFromSnapshot(ctx, workspaceId, name, options)
+}
+
+// createPersistentVolumeClaimFromSnapshot provisions the workspace persistent volume claim with the contents of the snapshot
+func (k *KubernetesDriver) createPersistentVolumeClaimFromSnapshot(ctx context.Context, id, name string, options *driver.RunOptions) error {
+	object, err := k.findSnapshot(ctx, name)
+	if err != nil {
+		return err
+	} else if object == nil {
+		return fmt.Errorf("snapshot %s doesn't exist", name)
+	}
+
+	pvc, err := k.buildPersistentVolumeClaim(id, options)
+	if err != nil {
+		return err
+	}
+
+	// the new volume can't be smaller than the volume the snapshot was taken from
+	var minSize *resource.Quantity
+	switch snapshot := object.(type) {
+	case *corev1.PersistentVolumeClaim:
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: snapshot.Name,
+		}
+		minSize = snapshot.Spec.Resources.Requests.Storage()
+	case *unstructured.Unstructured:
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(volumeSnapshotResource.Group),
+			Kind:     "VolumeSnapshot",
+			Name:     snapshot.GetName(),
+		}
+		minSize, err = getVolumeSnapshotRestoreSize(snapshot)
+		if err != nil {
+			return err
+		}
+	}
+	if minSize != nil && minSize.Cmp(pvc.Spec.Resources.Requests[corev1.ResourceStorage]) > 0 {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *minSize
+	}
+
+	k.Log.Infof("Create persistent volume claim '%s' from snapshot '%s'", id, name)
+	_, err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create pvc: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) waitPersistentVolumeClaimDeleted(ctx context.Context, id string) error {
+	err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Delete(ctx, id, metav1.DeleteOptions{
+		GracePeriodSeconds: &[]int64{5}[0],
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return perrors.Wrap(err, "delete pvc")
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute*2, true, func(ctx context.Context) (bool, error) {
+		_, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Get(ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return kerrors.IsNotFound(err), nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timeout waiting for pvc %s to be deleted", id)
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) snapshotFromObject(object metav1.Object) (*driver.Snapshot, error) {
+	snapshot := &driver.Snapshot{
+		Name:              object.GetLabels()[DevPodSnapshotLabel],
+		WorkspaceID:       object.GetLabels()[DevPodWorkspaceLabel],
+		CreationTimestamp: types.NewTime(object.GetCreationTimestamp().Time),
+		Volumes: []driver.SnapshotVolume{
+			{
+				Name: object.GetName(),
+				File: object.GetName(),
+			},
+		},
+	}
+
+	if object.GetAnnotations()[DevPodInfoAnnotation] != "" {
+		containerInfo := &DevContainerInfo{}
+		err := json.Unmarshal([]byte(object.GetAnnotations()[DevPodInfoAnnotation]), containerInfo)
+		if err != nil {
+			return nil, perrors.Wrap(err, "decode dev container info")
+		}
+		if containerInfo.Options != nil {
+			snapshot.Image = containerInfo.Options.Image
+		}
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,36 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/driver"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSnapshotFromObject(t *testing.T) {
+	info, err := json.Marshal(&DevContainerInfo{Options: &driver.RunOptions{Image: "ubuntu:22.04"}})
+	assert.NilError(t, err)
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: getSnapshotID("first"),
+			Labels: map[string]string{
+				DevPodSnapshotLabel:  "first",
+				DevPodWorkspaceLabel: "my-workspace",
+			},
+			Annotations: map[string]string{
+				DevPodInfoAnnotation: string(info),
+			},
+		},
+	}
+
+	snapshot, err := (&KubernetesDriver{}).snapshotFromObject(pvc)
+	assert.NilError(t, err)
+	assert.Equal(t, snapshot.Name, "first")
+	assert.Equal(t, snapshot.WorkspaceID, "my-workspace")
+	assert.Equal(t, snapshot.Image, "ubuntu:22.04")
+	assert.DeepEqual(t, snapshot.Volumes, []driver.SnapshotVolume{{Name: "devpod-snapshot-first", File: "devpod-snapshot-first"}})
+}
@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	perrors "github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+var volumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// useVolumeSnapshots returns true if snapshots are CSI VolumeSnapshots instead of persistent volume claim clones
+func (k *KubernetesDriver) useVolumeSnapshots() bool {
+	return k.options.VolumeSnapshotClass != ""
+}
+
+func (k *KubernetesDriver) volumeSnapshots() dynamic.ResourceInterface {
+	return k.client.Dynamic().Resource(volumeSnapshotResource).Namespace(k.namespace)
+}
+
+// createVolumeSnapshot snapshots the persistent volume claim and waits until the snapshot can be used
+func (k *KubernetesDriver) createVolumeSnapshot(ctx context.Context, id, pvcName string, labels, annotations map[string]string) (*unstructured.Unstructured, error) {
+	volumeSnapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": volumeSnapshotResource.GroupVersion().String(),
+			"kind":       "VolumeSnapshot",
+			"spec": map[string]interface{}{
+				"volumeSnapshotClassName": k.options.VolumeSnapshotClass,
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": pvcName,
+				},
+			},
+		},
+	}
+	volumeSnapshot.SetName(id)
+	volumeSnapshot.SetLabels(labels)
+	volumeSnapshot.SetAnnotations(annotations)
+
+	k.Log.Infof("Create volume snapshot '%s' of persistent volume claim '%s'", id, pvcName)
+	_, err := k.volumeSnapshots().Create(ctx, volumeSnapshot, metav1.CreateOptions{})
+	if err != nil {
+		return nil, perrors.Wrap(err, "create volume snapshot")
+	}
+
+	return k.waitVolumeSnapshotReady(ctx, id)
+}
+
+func (k *KubernetesDriver) waitVolumeSnapshotReady(ctx context.Context, id string) (*unstructured.Unstructured, error) {
+	var volumeSnapshot *unstructured.Unstructured
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, time.Minute*10, true, func(ctx context.Context) (bool, error) {
+		var err error
+		volumeSnapshot, err = k.volumeSnapshots().Get(ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		// errors are retried by the snapshot controller
+		message, _, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "error", "message")
+		if message != "" {
+			k.Log.Debugf("Volume snapshot '%s' is not ready yet: %s", id, message)
+		}
+
+		ready, _, _ := unstructured.NestedBool(volumeSnapshot.Object, "status", "readyToUse")
+		return ready, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wait for volume snapshot %s: %w", id, err)
+	}
+
+	return volumeSnapshot, nil
+}
+
+func (k *KubernetesDriver) getVolumeSnapshot(ctx context.Context, id string) (*unstructured.Unstructured, error) {
+	volumeSnapshot, err := k.volumeSnapshots().Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, perrors.Wrap(err, "get volume snapshot")
+	}
+
+	return volumeSnapshot, nil
+}
+
+func (k *KubernetesDriver) listVolumeSnapshots(ctx context.Context) ([]unstructured.Unstructured, error) {
+	volumeSnapshots, err := k.volumeSnapshots().List(ctx, metav1.ListOptions{
+		LabelSelector: DevPodSnapshotLabel,
+	})
+	if err != nil {
+		return nil, perrors.Wrap(err, "list volume snapshots")
+	}
+
+	return volumeSnapshots.Items, nil
+}
+
+func (k *KubernetesDriver) deleteVolumeSnapshot(ctx context.Context, id string) error {
+	k.Log.Infof("Delete volume snapshot '%s'...", id)
+	err := k.volumeSnapshots().Delete(ctx, id, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return perrors.Wrap(err, "delete volume snapshot")
+	}
+
+	return nil
+}
+
+// getVolumeSnapshotRestoreSize returns the minimum size of a volume restored from the snapshot or nil if unknown
+func getVolumeSnapshotRestoreSize(volumeSnapshot *unstructured.Unstructured) (*resource.Quantity, error) {
+	restoreSize, _, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "restoreSize")
+	if restoreSize == "" {
+		return nil, nil
+	}
+
+	quantity, err := resource.ParseQuantity(restoreSize)
+	if err != nil {
+		return nil, perrors.Wrapf(err, "parse restore size of volume snapshot %s", volumeSnapshot.GetName())
+	}
+
+	return &quantity, nil
+}
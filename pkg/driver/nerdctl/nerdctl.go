@@ -0,0 +1,63 @@
+package nerdctl
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/devpod/pkg/compose"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/docker"
+	"github.com/loft-sh/devpod/pkg/driver"
+	dockerdriver "github.com/loft-sh/devpod/pkg/driver/docker"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+)
+
+// dockerDriver is the docker driver the nerdctl driver builds on
+type dockerDriver interface {
+	driver.DockerDriver
+	driver.SnapshotDriver
+	driver.HostRequirementsDriver
+}
+
+func NewNerdctlDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (driver.DockerDriver, error) {
+	nerdctlConfig := workspaceInfo.Agent.Nerdctl
+	helper := &docker.DockerHelper{
+		DockerCommand: nerdctlConfig.Command(),
+		Environment:   config.ObjectToList(nerdctlConfig.Environment()),
+		ContainerID:   workspaceInfo.Workspace.Source.Container,
+		Builder:       docker.DockerBuilderBuildX,
+		Log:           log,
+	}
+	base, ok := dockerdriver.NewDockerDriverWithHelper(helper, workspaceInfo.Agent.DataPath, log).(dockerDriver)
+	if !ok {
+		return nil, fmt.Errorf("nerdctl driver doesn't support snapshots")
+	}
+
+	log.Debugf("Using nerdctl command '%s'", helper.DockerCommand)
+	return &nerdctlDriver{
+		dockerDriver: base,
+		Nerdctl:      helper,
+		Log:          log,
+	}, nil
+}
+
+// nerdctlDriver runs the devcontainer on containerd through nerdctl. nerdctl accepts the docker CLI, so the
+// docker driver does the heavy lifting. Images are built by buildkitd and compose projects run with nerdctl compose.
+type nerdctlDriver struct {
+	dockerDriver
+
+	Nerdctl *docker.DockerHelper
+	Compose *compose.ComposeHelper
+
+	Log log.Logger
+}
+
+func (n *nerdctlDriver) ComposeHelper() (*compose.ComposeHelper, error) {
+	if n.Compose != nil {
+		return n.Compose, nil
+	}
+
+	var err error
+	n.Compose, err = compose.NewNerdctlComposeHelper(n.Nerdctl)
+	return n.Compose, err
+}
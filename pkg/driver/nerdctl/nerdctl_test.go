@@ -0,0 +1,77 @@
+package nerdctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+// fakeNerdctl writes a stand-in nerdctl command that reports the given version and fails `compose version`
+// if compose is not available
+func fakeNerdctl(t *testing.T, version string, compose bool) string {
+	composeExit := "0"
+	if !compose {
+		composeExit = "1"
+	}
+
+	path := filepath.Join(t.TempDir(), "nerdctl")
+	err := os.WriteFile(path, []byte("#!/bin/sh\nif [ \"$1\" = \"compose\" ]; then\n  exit "+composeExit+"\nfi\necho \"nerdctl version "+version+"\"\n"), 0o755)
+	assert.NilError(t, err)
+	return path
+}
+
+func newTestDriver(t *testing.T, version string, compose bool) *nerdctlDriver {
+	driver, err := NewNerdctlDriver(&provider2.AgentWorkspaceInfo{
+		Workspace: &provider2.Workspace{},
+		Agent: provider2.ProviderAgentConfig{
+			Nerdctl: provider2.ProviderNerdctlDriverConfig{Path: fakeNerdctl(t, version, compose)},
+		},
+	}, log.Discard)
+	assert.NilError(t, err)
+	return driver.(*nerdctlDriver)
+}
+
+func TestComposeHelper(t *testing.T) {
+	testCases := []struct {
+		name    string
+		version string
+
+		expectedImage string
+	}{
+		{
+			name:          "current nerdctl",
+			version:       "1.7.6",
+			expectedImage: "project-app",
+		},
+		{
+			name:          "nerdctl before 0.23",
+			version:       "0.22.2",
+			expectedImage: "project_app",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			n := newTestDriver(t, testCase.version, true)
+			assert.Assert(t, n.Nerdctl.IsNerdctl())
+
+			composeHelper, err := n.ComposeHelper()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, composeHelper.Args, []string{"compose"})
+
+			image, err := composeHelper.GetDefaultImage("project", "app")
+			assert.NilError(t, err)
+			assert.Equal(t, image, testCase.expectedImage)
+		})
+	}
+}
+
+func TestComposeHelperUnavailable(t *testing.T) {
+	n := newTestDriver(t, "1.7.6", false)
+	_, err := n.ComposeHelper()
+	assert.ErrorContains(t, err, "compose' is not available")
+}
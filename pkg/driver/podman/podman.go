@@ -0,0 +1,201 @@
+package podman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/loft-sh/devpod/pkg/compose"
+	config2 "github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/docker"
+	"github.com/loft-sh/devpod/pkg/driver"
+	dockerdriver "github.com/loft-sh/devpod/pkg/driver/docker"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+)
+
+// UserNamespaceEnv is read by podman as the default for --userns of new containers, which also
+// covers containers created through compose
+const UserNamespaceEnv = "PODMAN_USERNS"
+
+// Info is the part of `podman info` the driver relies on
+type Info struct {
+	Host struct {
+		CPUs     int   `json:"cpus"`
+		MemTotal int64 `json:"memTotal"`
+		Security struct {
+			Rootless       bool `json:"rootless"`
+			SELinuxEnabled bool `json:"selinuxEnabled"`
+		} `json:"security"`
+	} `json:"host"`
+}
+
+// dockerDriver is the docker driver the podman driver builds on
+type dockerDriver interface {
+	driver.DockerDriver
+	driver.SnapshotDriver
+}
+
+func NewPodmanDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (driver.DockerDriver, error) {
+	podmanCommand := "podman"
+	if workspaceInfo.Agent.Podman.Path != "" {
+		podmanCommand = workspaceInfo.Agent.Podman.Path
+	}
+
+	helper := &docker.DockerHelper{
+		DockerCommand: podmanCommand,
+		Environment:   config.ObjectToList(workspaceInfo.Agent.Podman.Env),
+		ContainerID:   workspaceInfo.Workspace.Source.Container,
+		Log:           log,
+	}
+	base, ok := dockerdriver.NewDockerDriverWithHelper(helper, workspaceInfo.Agent.DataPath, log).(dockerDriver)
+	if !ok {
+		return nil, fmt.Errorf("podman driver doesn't support snapshots")
+	}
+
+	log.Debugf("Using podman command '%s'", podmanCommand)
+	return &podmanDriver{
+		dockerDriver: base,
+		Podman:       helper,
+		Log:          log,
+	}, nil
+}
+
+// podmanDriver runs the devcontainer with podman. It reuses the docker driver for everything podman's docker
+// compatible CLI handles the same way and takes care of rootless user namespaces, SELinux and compose.
+type podmanDriver struct {
+	dockerDriver
+
+	Podman  *docker.DockerHelper
+	Compose *compose.ComposeHelper
+
+	infoOnce sync.Once
+	info     *Info
+	infoErr  error
+
+	Log log.Logger
+}
+
+func (p *podmanDriver) Info(ctx context.Context) (*Info, error) {
+	p.infoOnce.Do(func() {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		err := p.Podman.Run(ctx, []string{"info", "--format", "json"}, nil, stdout, stderr)
+		if err != nil {
+			p.infoErr = fmt.Errorf("podman info: %s: %w", strings.TrimSpace(stderr.String()), err)
+			return
+		}
+
+		info := &Info{}
+		err = json.Unmarshal(stdout.Bytes(), info)
+		if err != nil {
+			p.infoErr = fmt.Errorf("parse podman info: %w", err)
+			return
+		}
+
+		p.info = info
+		if info.Host.Security.Rootless {
+			p.configureUserNamespace()
+		}
+	})
+
+	return p.info, p.infoErr
+}
+
+// configureUserNamespace maps the user running rootless podman to the same uid and gid inside the container,
+// so files in the workspace mount keep their owner. An explicitly configured user namespace is kept. This is the
+// only place the podman driver sets keep-id, the docker driver doesn't add --userns if PODMAN_USERNS is set.
+func (p *podmanDriver) configureUserNamespace() {
+	if os.Getenv(UserNamespaceEnv) != "" || slices.ContainsFunc(p.Podman.Environment, func(env string) bool {
+		return strings.HasPrefix(env, UserNamespaceEnv+"=")
+	}) {
+		return
+	}
+
+	p.Log.Debugf("Rootless podman detected, using %s=keep-id", UserNamespaceEnv)
+	p.Podman.Environment = append(p.Podman.Environment, UserNamespaceEnv+"=keep-id")
+}
+
+func (p *podmanDriver) ComposeHelper() (*compose.ComposeHelper, error) {
+	if p.Compose != nil {
+		return p.Compose, nil
+	}
+
+	_, err := p.Info(context.TODO())
+	if err != nil {
+		p.Log.Debugf("Error retrieving podman info: %v", err)
+	}
+
+	p.Compose, err = compose.NewPodmanComposeHelper(p.Podman)
+	return p.Compose, err
+}
+
+func (p *podmanDriver) RunDockerDevContainer(
+	ctx context.Context,
+	workspaceId string,
+	options *driver.RunOptions,
+	parsedConfig *config.DevContainerConfig,
+	init *bool,
+	ide string,
+	ideOptions map[string]config2.OptionValue,
+) error {
+	info, err := p.Info(ctx)
+	if err != nil {
+		p.Log.Debugf("Error retrieving podman info: %v", err)
+	} else if info.Host.Security.SELinuxEnabled {
+		// bind mounts need the container label to be readable with SELinux enforcing
+		runOptions := *options
+		runOptions.WorkspaceMount = relabelMount(options.WorkspaceMount)
+		runOptions.Mounts = make([]*config.Mount, 0, len(options.Mounts))
+		for _, mount := range options.Mounts {
+			runOptions.Mounts = append(runOptions.Mounts, relabelMount(mount))
+		}
+		options = &runOptions
+	}
+
+	return p.dockerDriver.RunDockerDevContainer(ctx, workspaceId, options, parsedConfig, init, ide, ideOptions)
+}
+
+func (p *podmanDriver) ValidateHostRequirements(ctx context.Context, workspaceID string, requirements *config.HostRequirements) error {
+	if requirements == nil {
+		return nil
+	}
+
+	info, err := p.Info(ctx)
+	if err != nil {
+		p.Log.Warnf("Skip validating hostRequirements, because podman info failed: %v", err)
+		return nil
+	}
+
+	return config.ValidateHostRequirements(requirements, &config.HostResources{
+		CPUs:   info.Host.CPUs,
+		Memory: info.Host.MemTotal,
+	}, "podman host")
+}
+
+// relabelMount adds the private SELinux relabel option (:Z) to bind mounts of directories, unless
+// the mount already sets one. Files and sockets are shared with the host and are never relabeled.
+func relabelMount(mount *config.Mount) *config.Mount {
+	if mount == nil || mount.Type != "bind" || mount.Source == "" {
+		return mount
+	}
+	for _, option := range mount.Other {
+		if strings.HasPrefix(option, "relabel=") || option == "z" || option == "Z" {
+			return mount
+		}
+	}
+	stat, err := os.Stat(mount.Source)
+	if err != nil || !stat.IsDir() {
+		return mount
+	}
+
+	relabeled := *mount
+	relabeled.Other = append(slices.Clone(mount.Other), "relabel=private")
+	return &relabeled
+}
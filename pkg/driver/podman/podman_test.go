@@ -0,0 +1,101 @@
+package podman
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestRelabelMount(t *testing.T) {
+	dir := t.TempDir()
+
+	mount := &config.Mount{Type: "bind", Source: dir, Target: "/workspaces/test"}
+	relabeled := relabelMount(mount)
+	assert.DeepEqual(t, relabeled.Other, []string{"relabel=private"})
+	assert.Assert(t, len(mount.Other) == 0, "original mount must not be changed")
+
+	shared := &config.Mount{Type: "bind", Source: dir, Target: "/data", Other: []string{"relabel=shared"}}
+	assert.Equal(t, relabelMount(shared), shared)
+
+	volume := &config.Mount{Type: "volume", Source: "data", Target: "/data"}
+	assert.Equal(t, relabelMount(volume), volume)
+
+	file := &config.Mount{Type: "bind", Source: "/does/not/exist", Target: "/data"}
+	assert.Equal(t, relabelMount(file), file)
+}
+
+// fakePodman writes a stand-in podman command that prints the given podman info
+func fakePodman(t *testing.T, info string) string {
+	path := filepath.Join(t.TempDir(), "podman")
+	err := os.WriteFile(path, []byte("#!/bin/sh\nif [ \"$1\" = \"info\" ]; then\n  echo '"+info+"'\nelse\n  echo \"podman version 5.0.0\"\nfi\n"), 0o755)
+	assert.NilError(t, err)
+	return path
+}
+
+func newTestDriver(t *testing.T, info string, env map[string]string) *podmanDriver {
+	driver, err := NewPodmanDriver(&provider2.AgentWorkspaceInfo{
+		Workspace: &provider2.Workspace{},
+		Agent: provider2.ProviderAgentConfig{
+			Podman: provider2.ProviderPodmanDriverConfig{Path: fakePodman(t, info), Env: env},
+		},
+	}, log.Discard)
+	assert.NilError(t, err)
+	return driver.(*podmanDriver)
+}
+
+func TestInfoRootless(t *testing.T) {
+	t.Setenv(UserNamespaceEnv, "")
+
+	p := newTestDriver(t, `{"host":{"cpus":4,"memTotal":8589934592,"security":{"rootless":true,"selinuxEnabled":true}}}`, nil)
+	info, err := p.Info(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, info.Host.CPUs, 4)
+	assert.Assert(t, info.Host.Security.SELinuxEnabled)
+	assert.DeepEqual(t, p.Podman.Environment, []string{UserNamespaceEnv + "=keep-id"})
+
+	// info is only retrieved once, so keep-id is only added once
+	_, err = p.Info(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, p.Podman.Environment, []string{UserNamespaceEnv + "=keep-id"})
+}
+
+func TestInfoUserNamespace(t *testing.T) {
+	t.Setenv(UserNamespaceEnv, "")
+
+	// rootful podman keeps the user namespace as is
+	p := newTestDriver(t, `{"host":{"security":{"rootless":false}}}`, nil)
+	_, err := p.Info(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(p.Podman.Environment), 0)
+
+	// an explicitly configured user namespace is kept
+	p = newTestDriver(t, `{"host":{"security":{"rootless":true}}}`, map[string]string{UserNamespaceEnv: "auto"})
+	_, err = p.Info(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, p.Podman.Environment, []string{UserNamespaceEnv + "=auto"})
+}
+
+func TestInfoError(t *testing.T) {
+	p := newTestDriver(t, `not json`, nil)
+	_, err := p.Info(context.Background())
+	assert.ErrorContains(t, err, "parse podman info")
+
+	// host requirements are not validated without podman info
+	err = p.ValidateHostRequirements(context.Background(), "test", &config.HostRequirements{CPUs: 64})
+	assert.NilError(t, err)
+}
+
+func TestValidateHostRequirements(t *testing.T) {
+	p := newTestDriver(t, `{"host":{"cpus":2,"memTotal":4294967296,"security":{"rootless":false}}}`, nil)
+	err := p.ValidateHostRequirements(context.Background(), "test", &config.HostRequirements{CPUs: 2, Memory: "4gb"})
+	assert.NilError(t, err)
+
+	err = p.ValidateHostRequirements(context.Background(), "test", &config.HostRequirements{CPUs: 4})
+	assert.ErrorContains(t, err, "podman host")
+}
@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/loft-sh/devpod/pkg/types"
+)
+
+var snapshotNameRegEx = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,61}[a-z0-9])?$`)
+
+// SnapshotDriver is implemented by drivers that can capture and restore the state of a devcontainer
+type SnapshotDriver interface {
+	Driver
+
+	// CreateSnapshot captures the devcontainer filesystem and its volumes under the given name
+	CreateSnapshot(ctx context.Context, workspaceID, name string) (*Snapshot, error)
+
+	// GetSnapshot returns the snapshot with the given name or nil if it doesn't exist
+	GetSnapshot(ctx context.Context, name string) (*Snapshot, error)
+
+	// ListSnapshots returns all snapshots known to the driver
+	ListSnapshots(ctx context.Context) ([]*Snapshot, error)
+
+	// DeleteSnapshot removes the snapshot with the given name
+	DeleteSnapshot(ctx context.Context, name string) error
+
+	// RestoreSnapshot prepares the workspace so that the next run starts from the given snapshot.
+	// Drivers are allowed to change the run options, e.g. to exchange the image.
+	RestoreSnapshot(ctx context.Context, workspaceID, name string, options *RunOptions) error
+}
+
+// Snapshot describes a captured devcontainer state
+type Snapshot struct {
+	// Name is the unique name of the snapshot
+	Name string `json:"name,omitempty"`
+
+	// WorkspaceID is the id of the workspace the snapshot was taken from
+	WorkspaceID string `json:"workspaceId,omitempty"`
+
+	// Image is the image that holds the container filesystem, if any
+	Image string `json:"image,omitempty"`
+
+	// Volumes are the volumes captured with the snapshot
+	Volumes []SnapshotVolume `json:"volumes,omitempty"`
+
+	// CreationTimestamp is the time the snapshot was taken
+	CreationTimestamp types.Time `json:"creationTimestamp,omitempty"`
+}
+
+// SnapshotVolume is a single volume captured within a snapshot
+type SnapshotVolume struct {
+	// Name is the name of the volume at the time of the snapshot
+	Name string `json:"name,omitempty"`
+
+	// Target is the path the volume was mounted to inside the container
+	Target string `json:"target,omitempty"`
+
+	// File is the archive or resource the volume contents were stored in
+	File string `json:"file,omitempty"`
+}
+
+// ValidateSnapshotName checks if the given name can be used as a snapshot name
+func ValidateSnapshotName(name string) error {
+	if !snapshotNameRegEx.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name '%s': must consist of lower case alphanumeric characters, '-' or '.', start and end with an alphanumeric character and be at most 63 characters long", name)
+	}
+
+	return nil
+}
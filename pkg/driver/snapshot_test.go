@@ -0,0 +1,17 @@
+package driver
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	for _, name := range []string{"a", "my-snapshot", "v1.2", strings.Repeat("a", 63)} {
+		assert.NilError(t, ValidateSnapshotName(name), name)
+	}
+	for _, name := range []string{"", "-a", "a-", "My-Snapshot", "a_b", "a/b", strings.Repeat("a", 64)} {
+		assert.Assert(t, ValidateSnapshotName(name) != nil, name)
+	}
+}
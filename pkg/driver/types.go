@@ -3,8 +3,11 @@ package driver
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/devcontainer/feature"
+	"github.com/loft-sh/devpod/pkg/provider"
 )
 
 // Driver is the default interface for DevPod drivers
@@ -41,6 +44,97 @@ type ReprovisioningDriver interface {
 	CanReprovision() bool
 }
 
+// HostRequirementsDriver is implemented by drivers that can check the hostRequirements of a devcontainer.json
+// against their target before anything is built
+type HostRequirementsDriver interface {
+	Driver
+
+	// ValidateHostRequirements returns an error if the target cannot satisfy the given requirements
+	ValidateHostRequirements(ctx context.Context, workspaceID string, requirements *config.HostRequirements) error
+}
+
+// HostDriver is implemented by drivers that run the devcontainer directly on the host instead of a container.
+// Everything that requires an image, such as building, features or mounts, is skipped for these drivers.
+type HostDriver interface {
+	Driver
+
+	// RunsOnHost returns true if the devcontainer runs on the host
+	RunsOnHost() bool
+}
+
+// BuildDriver is implemented by drivers without a local docker that can build the devcontainer image themselves.
+// The image needs to end up where the driver can run it from, e.g. a registry.
+type BuildDriver interface {
+	Driver
+
+	// CanBuild returns true if the driver is configured to build images
+	CanBuild() bool
+
+	// BuildDevContainer builds the devcontainer image
+	BuildDevContainer(
+		ctx context.Context,
+		prebuildHash string,
+		parsedConfig *config.SubstitutedConfig,
+		extendedBuildInfo *feature.ExtendedBuildInfo,
+		dockerfilePath,
+		dockerfileContent string,
+		localWorkspaceFolder string,
+		options provider.BuildOptions,
+	) (*config.BuildInfo, error)
+}
+
+// TTYExecDriver is implemented by drivers that can run a command in the devcontainer with a pseudo terminal
+// and forward window size changes to it
+type TTYExecDriver interface {
+	Driver
+
+	// CanExecTTY returns true if the driver can allocate a pseudo terminal
+	CanExecTTY() bool
+
+	// ExecDevContainer runs the given command inside the devcontainer, optionally with a pseudo terminal
+	ExecDevContainer(ctx context.Context, workspaceID string, options *ExecOptions) error
+}
+
+// LogsFollowDriver is implemented by drivers that can stream the logs of the devcontainer
+type LogsFollowDriver interface {
+	Driver
+
+	// FollowDevContainerLogs streams the logs of the devcontainer until the context is cancelled
+	FollowDevContainerLogs(ctx context.Context, workspaceID string, stdout io.Writer, stderr io.Writer) error
+}
+
+// ComposeDriver is implemented by drivers that run the services of a docker compose devcontainer themselves
+// instead of through docker compose. The services are passed in RunOptions.Services.
+type ComposeDriver interface {
+	Driver
+
+	// RunsComposeServices returns true if the driver starts RunOptions.Services next to the devcontainer
+	RunsComposeServices() bool
+}
+
+// ExecOptions are the options of an exec in the devcontainer
+type ExecOptions struct {
+	User    string
+	Command string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// TTY allocates a pseudo terminal of the given size
+	TTY *TTY
+
+	// Resize receives window size changes of the terminal
+	Resize <-chan TTY
+}
+
+// TTY is the type and size of a pseudo terminal
+type TTY struct {
+	Term   string `json:"term,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 // RunOptions are the options for running a container
 type RunOptions struct {
 	// UID is a unique identifier for this workspace
@@ -80,4 +174,72 @@ type RunOptions struct {
 	// Bind mounts are expected to get copied from local to remote once. Volume mounts are expected
 	// to be persisted for the lifetime of the container.
 	Mounts []*config.Mount `json:"mounts,omitempty"`
+
+	// HostRequirements are the minimum resources the container needs. Drivers translate them
+	// into resource requests of the container.
+	HostRequirements *config.HostRequirements `json:"hostRequirements,omitempty"`
+
+	// Services are additional containers that run next to the devcontainer, e.g. the other services
+	// of a docker compose devcontainer. Only drivers that implement ComposeDriver support these.
+	Services []*ServiceOptions `json:"services,omitempty"`
+
+	// ForwardPorts are the forwardPorts of the devcontainer with their portsAttributes. Drivers can use
+	// them to make the ports reachable without a port forwarding.
+	ForwardPorts []ForwardPort `json:"forwardPorts,omitempty"`
+}
+
+// ForwardPort is a port of the devcontainer listed in forwardPorts
+type ForwardPort struct {
+	Port     int    `json:"port"`
+	Label    string `json:"label,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// ServiceOptions are the options for running a service next to the devcontainer
+type ServiceOptions struct {
+	// Name is the name of the service, the service should be reachable under this name
+	Name string `json:"name"`
+
+	// Aliases are additional host names of the service
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Image is the image to run
+	Image string `json:"image"`
+
+	// User is the user to run the service as
+	User string `json:"user,omitempty"`
+
+	// Entrypoint overrides the entrypoint of the image
+	Entrypoint []string `json:"entrypoint,omitempty"`
+
+	// Cmd overrides the cmd of the image
+	Cmd []string `json:"cmd,omitempty"`
+
+	// WorkingDir overrides the working directory of the image
+	WorkingDir string `json:"workingDir,omitempty"`
+
+	// Env are additional environment variables to set
+	Env map[string]string `json:"env,omitempty"`
+
+	// Ports are the ports the service listens on
+	Ports []int `json:"ports,omitempty"`
+
+	// Mounts are the mounts of the service. Volume mounts with the same source are shared with the devcontainer
+	// and the other services, volume mounts without a source are scratch space.
+	Mounts []*config.Mount `json:"mounts,omitempty"`
+
+	// DependsOn are the services that need to be started before this one
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// HealthCheck is set if another service or the devcontainer waits for this service to be healthy
+	HealthCheck *ServiceHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// ServiceHealthCheck is a command that exits with 0 as soon as a service is healthy
+type ServiceHealthCheck struct {
+	Command     []string      `json:"command"`
+	Interval    time.Duration `json:"interval,omitempty"`
+	Timeout     time.Duration `json:"timeout,omitempty"`
+	StartPeriod time.Duration `json:"startPeriod,omitempty"`
+	Retries     int           `json:"retries,omitempty"`
 }
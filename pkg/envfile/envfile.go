@@ -3,7 +3,9 @@ package envfile
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/log"
 )
 
@@ -15,7 +17,7 @@ type EnvFile struct {
 }
 
 func Apply(log log.Logger) {
-	out, err := os.ReadFile(location)
+	out, err := os.ReadFile(hostdir.Path(location))
 	if err != nil {
 		if !os.IsNotExist(err) {
 			log.Debugf("Error reading envfile: %v", err)
@@ -42,7 +44,7 @@ func MergeAndApply(env map[string]string, log log.Logger) {
 	}
 
 	envFile := &EnvFile{}
-	out, err := os.ReadFile(location)
+	out, err := os.ReadFile(hostdir.Path(location))
 	if err != nil {
 		if !os.IsNotExist(err) {
 			log.Debugf("Error reading envfile: %v", err)
@@ -69,7 +71,8 @@ func MergeAndApply(env map[string]string, log log.Logger) {
 		return
 	}
 
-	err = os.WriteFile(location, out, 0666)
+	_ = os.MkdirAll(filepath.Dir(hostdir.Path(location)), 0777)
+	err = os.WriteFile(hostdir.Path(location), out, 0666)
 	if err != nil {
 		log.Debugf("Error writing envfile: %v", err)
 		return
@@ -0,0 +1,32 @@
+package hostdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Env is set for every command the host driver runs. It points to the per-workspace folder that holds the
+// state DevPod keeps in system folders such as /var/devpod when the workspace runs in a container.
+const Env = "DEVPOD_HOST_DIR"
+
+// Dir returns the per-workspace host folder or an empty string if the workspace runs in a container
+func Dir() string {
+	return os.Getenv(Env)
+}
+
+// Path returns the given absolute container path relocated into the host folder if the workspace runs on the
+// host, e.g. /var/devpod/x.marker becomes <host folder>/var/devpod/x.marker
+func Path(containerPath string) string {
+	dir := Dir()
+	if dir == "" {
+		return containerPath
+	}
+
+	return filepath.Join(dir, containerPath)
+}
+
+// ShellPath returns a quoted shell expression that resolves to Path(containerPath) in the shell of the workspace
+func ShellPath(containerPath string) string {
+	return fmt.Sprintf(`"${%s}%s"`, Env, containerPath)
+}
@@ -0,0 +1,30 @@
+package hostdir
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestPath(t *testing.T) {
+	t.Setenv(Env, "")
+	assert.Equal(t, Path("/var/devpod/x.marker"), "/var/devpod/x.marker")
+
+	dir := t.TempDir()
+	t.Setenv(Env, dir)
+	assert.Equal(t, Path("/var/devpod/x.marker"), filepath.Join(dir, "var", "devpod", "x.marker"))
+	assert.Assert(t, Path("/var/run/devpod/result.json") != Path("/var/devpod/result.json"))
+}
+
+func TestShellPath(t *testing.T) {
+	for _, dir := range []string{"", t.TempDir()} {
+		t.Setenv(Env, dir)
+
+		out, err := exec.Command("sh", "-c", "echo "+ShellPath("/var/run/devpod/result.json")).Output()
+		assert.NilError(t, err)
+		assert.Equal(t, strings.TrimSpace(string(out)), Path("/var/run/devpod/result.json"))
+	}
+}
@@ -0,0 +1,339 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/loft-sh/log"
+)
+
+// RunFunc runs the peer process with the given stdin and stdout until the context is cancelled
+type RunFunc func(ctx context.Context, stdin io.Reader, stdout io.Writer) error
+
+// Client is a JSON-RPC connection to a long-lived process that reads newline delimited messages
+// from its stdin and writes them to its stdout
+type Client struct {
+	name string
+	log  log.Logger
+
+	writeMutex sync.Mutex
+	writer     io.WriteCloser
+
+	mutex   sync.Mutex
+	nextID  int64
+	pending map[int64]chan *Message
+	streams map[string]*stream
+
+	nextStream atomic.Int64
+
+	closed   chan struct{}
+	closeErr error
+	cancel   context.CancelFunc
+}
+
+type stream struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Start starts the process with run, it runs until Close is called. Name is used in errors, e.g. driver.
+// Stdin is an os pipe, so a process started by run gets it directly and run returns as soon as the
+// process exits instead of once the next message is written.
+func Start(name string, run RunFunc, log log.Logger) (*Client, error) {
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("create %s stdin: %w", name, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stdoutReader, stdoutWriter := io.Pipe()
+	go func() {
+		err := run(ctx, stdinReader, stdoutWriter)
+		if err == nil {
+			err = fmt.Errorf("%s exited", name)
+		}
+		_ = stdinReader.Close()
+		_ = stdoutWriter.CloseWithError(err)
+	}()
+
+	return NewClient(name, stdinWriter, stdoutReader, cancel, log), nil
+}
+
+// NewClient creates a client that writes messages to writer and reads them from reader
+func NewClient(name string, writer io.WriteCloser, reader io.Reader, cancel context.CancelFunc, log log.Logger) *Client {
+	c := &Client{
+		name:    name,
+		log:     log,
+		writer:  writer,
+		pending: map[int64]chan *Message{},
+		streams: map[string]*stream{},
+		closed:  make(chan struct{}),
+		cancel:  cancel,
+	}
+	go c.read(reader)
+
+	return c
+}
+
+func (c *Client) Close() error {
+	c.cancel()
+	return c.writer.Close()
+}
+
+// Closed returns true once the peer exited or closed its stdout
+func (c *Client) Closed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Call sends a request and decodes the result of the response into result
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	request, err := c.Request(method, params)
+	if err != nil {
+		return err
+	}
+
+	return request.Wait(ctx, result)
+}
+
+// Request is a request that was sent to the peer and waits for its response
+type Request struct {
+	client   *Client
+	id       int64
+	method   string
+	response chan *Message
+}
+
+// Request sends a request without waiting for the response, e.g. to send input to a stream afterwards
+func (c *Client) Request(method string, params interface{}) (*Request, error) {
+	c.mutex.Lock()
+	c.nextID++
+	request := &Request{
+		client:   c,
+		id:       c.nextID,
+		method:   method,
+		response: make(chan *Message, 1),
+	}
+	c.pending[request.id] = request.response
+	c.mutex.Unlock()
+
+	err := c.send(&request.id, method, params)
+	if err != nil {
+		request.done()
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Wait waits for the response and decodes its result into result. If the context is cancelled the
+// request is cancelled at the peer.
+func (r *Request) Wait(ctx context.Context, result interface{}) error {
+	defer r.done()
+
+	var message *Message
+	select {
+	case message = <-r.response:
+	case <-ctx.Done():
+		_ = r.client.Notify(NotificationCancel, &CancelParams{ID: r.id})
+		return ctx.Err()
+	case <-r.client.closed:
+		// the response could have been read right before the peer exited
+		select {
+		case message = <-r.response:
+		default:
+			return fmt.Errorf("%s %s: %w", r.client.name, r.method, r.client.closeErr)
+		}
+	}
+
+	if message.Error != nil {
+		return message.Error
+	} else if result != nil && len(message.Result) > 0 {
+		err := json.Unmarshal(message.Result, result)
+		if err != nil {
+			return fmt.Errorf("decode %s result: %w", r.method, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Request) done() {
+	r.client.mutex.Lock()
+	defer r.client.mutex.Unlock()
+
+	delete(r.client.pending, r.id)
+}
+
+func (c *Client) Notify(method string, params interface{}) error {
+	return c.send(nil, method, params)
+}
+
+func (c *Client) send(id *int64, method string, params interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encode %s params: %w", method, err)
+	}
+
+	return c.write(&Message{
+		JSONRPC: Version,
+		ID:      id,
+		Method:  method,
+		Params:  rawParams,
+	})
+}
+
+func (c *Client) write(message *Message) error {
+	out, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	_, err = c.writer.Write(append(out, '\n'))
+	if err != nil {
+		return fmt.Errorf("write to %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// NewStream returns a new stream id and writes the output of the stream to stdout and stderr until
+// RemoveStream is called
+func (c *Client) NewStream(stdout io.Writer, stderr io.Writer) string {
+	id := strconv.FormatInt(c.nextStream.Add(1), 10)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.streams[id] = &stream{stdout: stdout, stderr: stderr}
+	return id
+}
+
+func (c *Client) RemoveStream(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.streams, id)
+}
+
+// ForwardStdin sends stdin to the stream until it is closed or the context is cancelled
+func (c *Client) ForwardStdin(ctx context.Context, id string, stdin io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stdin.Read(buf)
+		if ctx.Err() != nil {
+			return
+		}
+		if n > 0 {
+			notifyErr := c.Notify(NotificationStream, &StreamParams{Stream: id, Name: StreamStdin, Data: buf[:n]})
+			if notifyErr != nil {
+				c.log.Debugf("Error forwarding stdin: %v", notifyErr)
+				return
+			}
+		}
+		if err != nil {
+			_ = c.Notify(NotificationStream, &StreamParams{Stream: id, Name: StreamStdin, EOF: true})
+			return
+		}
+	}
+}
+
+// read dispatches the messages of the peer until it exits. Stream output is written before the
+// following response is dispatched, so all output of a request is written once the request returns.
+func (c *Client) read(reader io.Reader) {
+	bufReader := bufio.NewReader(reader)
+	for {
+		line, err := bufReader.ReadBytes('\n')
+		if len(line) > 0 {
+			c.handle(line)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("%s closed its stdout", c.name)
+			}
+
+			c.closeErr = err
+			close(c.closed)
+			return
+		}
+	}
+}
+
+func (c *Client) handle(line []byte) {
+	message := &Message{}
+	err := json.Unmarshal(line, message)
+	if err != nil {
+		c.log.Debugf("Invalid message from %s %s: %v", c.name, string(line), err)
+		return
+	}
+
+	switch {
+	case message.Method == "" && message.ID != nil:
+		c.mutex.Lock()
+		response := c.pending[*message.ID]
+		c.mutex.Unlock()
+		if response != nil {
+			response <- message
+		}
+	case message.Method != "" && message.ID != nil:
+		_ = c.write(&Message{
+			JSONRPC: Version,
+			ID:      message.ID,
+			Error:   &Error{Code: ErrMethodNotFound, Message: fmt.Sprintf("method %s not found", message.Method)},
+		})
+	case message.Method == NotificationStream:
+		params := &StreamParams{}
+		err = json.Unmarshal(message.Params, params)
+		if err != nil {
+			c.log.Debugf("Invalid stream notification from %s: %v", c.name, err)
+			return
+		}
+
+		c.mutex.Lock()
+		stream := c.streams[params.Stream]
+		c.mutex.Unlock()
+		if stream == nil || len(params.Data) == 0 {
+			return
+		}
+
+		writer := stream.stdout
+		if params.Name == StreamStderr {
+			writer = stream.stderr
+		}
+		if writer != nil {
+			_, _ = writer.Write(params.Data)
+		}
+	case message.Method == NotificationLog:
+		params := &LogParams{}
+		err = json.Unmarshal(message.Params, params)
+		if err != nil {
+			c.log.Debugf("Invalid log notification from %s: %v", c.name, err)
+			return
+		}
+
+		switch params.Level {
+		case "debug":
+			c.log.Debug(params.Message)
+		case "warn":
+			c.log.Warn(params.Message)
+		case "error":
+			c.log.Error(params.Message)
+		default:
+			c.log.Info(params.Message)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the JSON-RPC version of every message
+const Version = "2.0"
+
+// ErrMethodNotFound is the error code of a response to a method the peer doesn't implement
+const ErrMethodNotFound = -32601
+
+// Notifications that are shared by all DevPod protocols. Input and output of a long running request is
+// sent as stream notifications, window size changes as resize notifications and cancelled requests as
+// cancel notifications. Log notifications are printed to the DevPod log.
+const (
+	NotificationStream = "stream"
+	NotificationResize = "resize"
+	NotificationLog    = "log"
+	NotificationCancel = "$/cancelRequest"
+)
+
+const (
+	StreamStdin  = "stdin"
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// Message is a JSON-RPC 2.0 request, notification or response
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type StreamParams struct {
+	Stream string `json:"stream"`
+
+	// Name is one of stdin, stdout or stderr
+	Name string `json:"name"`
+	Data []byte `json:"data,omitempty"`
+
+	// EOF closes the stream
+	EOF bool `json:"eof,omitempty"`
+}
+
+type ResizeParams struct {
+	Stream string `json:"stream"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type LogParams struct {
+	// Level is one of debug, info, warn or error
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type CancelParams struct {
+	ID int64 `json:"id"`
+}
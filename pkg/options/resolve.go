@@ -149,6 +149,49 @@ func ResolveAndSaveOptionsProxy(
 	return ResolveAndSaveOptionsWorkspace(ctx, devConfig, provider, originalWorkspace, userOptions, log, resolver.WithResolveSubOptions())
 }
 
+// ResolveOptionsDryRun resolves all provider options of a workspace, or of its machine for machine providers,
+// without saving them. Workspace and machine don't need to exist yet.
+func ResolveOptionsDryRun(
+	ctx context.Context,
+	devConfig *config.Config,
+	provider *provider2.ProviderConfig,
+	workspace *provider2.Workspace,
+	machine *provider2.Machine,
+	userOptions map[string]string,
+	log log.Logger,
+) (map[string]config.OptionValue, error) {
+	// get binary paths
+	binaryPaths, err := binaries.GetBinaries(devConfig.DefaultContext, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// machine options take precedence
+	env := provider2.ToOptionsWorkspace(workspace)
+	if machine != nil {
+		env = provider2.ToOptionsMachine(machine)
+		workspace = nil
+	}
+
+	// resolve options
+	resolvedOptions, _, err := resolver.New(
+		userOptions,
+		provider2.Merge(env, binaryPaths),
+		log,
+		resolver.WithResolveLocal(),
+	).Resolve(
+		ctx,
+		devConfig.DynamicProviderOptionDefinitions(provider.Name),
+		provider.Options,
+		provider2.CombineOptions(workspace, machine, devConfig.ProviderOptions(provider.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolvedOptions, nil
+}
+
 func ResolveOptions(
 	ctx context.Context,
 	devConfig *config.Config,
@@ -235,6 +278,17 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Docker.Install = types.StrBool(resolver.ResolveDefaultValue(string(agentConfig.Docker.Install), options))
 	agentConfig.Docker.Env = resolver.ResolveDefaultValues(agentConfig.Docker.Env, options)
 
+	// podman driver
+	agentConfig.Podman.Path = resolver.ResolveDefaultValue(agentConfig.Podman.Path, options)
+	agentConfig.Podman.Env = resolver.ResolveDefaultValues(agentConfig.Podman.Env, options)
+
+	// nerdctl driver
+	agentConfig.Nerdctl.Path = resolver.ResolveDefaultValue(agentConfig.Nerdctl.Path, options)
+	agentConfig.Nerdctl.Address = resolver.ResolveDefaultValue(agentConfig.Nerdctl.Address, options)
+	agentConfig.Nerdctl.Namespace = resolver.ResolveDefaultValue(agentConfig.Nerdctl.Namespace, options)
+	agentConfig.Nerdctl.BuildKitHost = resolver.ResolveDefaultValue(agentConfig.Nerdctl.BuildKitHost, options)
+	agentConfig.Nerdctl.Env = resolver.ResolveDefaultValues(agentConfig.Nerdctl.Env, options)
+
 	// kubernetes driver
 	agentConfig.Kubernetes.KubernetesContext = resolver.ResolveDefaultValue(agentConfig.Kubernetes.KubernetesContext, options)
 	agentConfig.Kubernetes.KubernetesConfig = resolver.ResolveDefaultValue(agentConfig.Kubernetes.KubernetesConfig, options)
@@ -248,6 +302,7 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Kubernetes.Resources = resolver.ResolveDefaultValue(agentConfig.Kubernetes.Resources, options)
 	agentConfig.Kubernetes.WorkspaceVolumeMount = resolver.ResolveDefaultValue(agentConfig.Kubernetes.WorkspaceVolumeMount, options)
 	agentConfig.Kubernetes.PodManifestTemplate = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PodManifestTemplate, options)
+	agentConfig.Kubernetes.PodPatches = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PodPatches, options)
 	agentConfig.Kubernetes.Labels = resolver.ResolveDefaultValue(agentConfig.Kubernetes.Labels, options)
 	agentConfig.Kubernetes.StrictSecurity = resolver.ResolveDefaultValue(agentConfig.Kubernetes.StrictSecurity, options)
 	agentConfig.Kubernetes.CreateNamespace = resolver.ResolveDefaultValue(agentConfig.Kubernetes.CreateNamespace, options)
@@ -256,6 +311,17 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Kubernetes.PodTimeout = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PodTimeout, options)
 	agentConfig.Kubernetes.KubernetesPullSecretsEnabled = resolver.ResolveDefaultValue(agentConfig.Kubernetes.KubernetesPullSecretsEnabled, options)
 	agentConfig.Kubernetes.DiskSize = resolver.ResolveDefaultValue(agentConfig.Kubernetes.DiskSize, options)
+	agentConfig.Kubernetes.BuildRepository = resolver.ResolveDefaultValue(agentConfig.Kubernetes.BuildRepository, options)
+	agentConfig.Kubernetes.BuildkitImage = resolver.ResolveDefaultValue(agentConfig.Kubernetes.BuildkitImage, options)
+	agentConfig.Kubernetes.ExposePorts = resolver.ResolveDefaultValue(agentConfig.Kubernetes.ExposePorts, options)
+	agentConfig.Kubernetes.IngressHost = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IngressHost, options)
+	agentConfig.Kubernetes.IngressClassName = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IngressClassName, options)
+	agentConfig.Kubernetes.IngressAnnotations = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IngressAnnotations, options)
+	agentConfig.Kubernetes.IngressTLSSecret = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IngressTLSSecret, options)
+	agentConfig.Kubernetes.PvcRetentionPolicy = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PvcRetentionPolicy, options)
+	agentConfig.Kubernetes.PvcRetentionPeriod = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PvcRetentionPeriod, options)
+	agentConfig.Kubernetes.VolumeSnapshotClass = resolver.ResolveDefaultValue(agentConfig.Kubernetes.VolumeSnapshotClass, options)
+	agentConfig.Kubernetes.GoldenSnapshot = resolver.ResolveDefaultValue(agentConfig.Kubernetes.GoldenSnapshot, options)
 
 	agentConfig.DataPath = resolver.ResolveDefaultValue(agentConfig.DataPath, options)
 	agentConfig.Path = resolver.ResolveDefaultValue(agentConfig.Path, options)
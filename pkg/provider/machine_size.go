@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	devcontainerconfig "github.com/loft-sh/devpod/pkg/devcontainer/config"
+)
+
+type ProviderMachineSizes struct {
+	// Option is the provider option that selects the machine size, e.g. INSTANCE_TYPE
+	Option string `json:"option,omitempty"`
+
+	// Sizes maps the values of the option to the resources of the machine
+	Sizes map[string]*ProviderMachineSize `json:"sizes,omitempty"`
+}
+
+type ProviderMachineSize struct {
+	// Number of CPUs of the machine
+	CPUs int `json:"cpus,omitempty"`
+
+	// Memory of the machine. Supports units tb, gb, mb and kb.
+	Memory string `json:"memory,omitempty"`
+
+	// Disk space of the machine. Supports units tb, gb, mb and kb.
+	Storage string `json:"storage,omitempty"`
+
+	// GPU is true if the machine has a GPU
+	GPU bool `json:"gpu,omitempty"`
+}
+
+// MachineResources returns the resources of the machine size selected by the given options or nil if
+// the provider doesn't declare the size
+func (c *ProviderConfig) MachineResources(options map[string]config.OptionValue) (*devcontainerconfig.HostResources, error) {
+	if c.MachineSizes == nil {
+		return nil, nil
+	}
+
+	value := ""
+	if option, ok := options[c.MachineSizes.Option]; ok {
+		value = option.Value
+	} else if c.Options[c.MachineSizes.Option] != nil {
+		value = c.Options[c.MachineSizes.Option].Default
+	}
+	size := c.MachineSizes.Sizes[value]
+	if size == nil {
+		return nil, nil
+	}
+
+	// machine sizes use the same units as hostRequirements
+	requirements := &devcontainerconfig.HostRequirements{Memory: size.Memory, Storage: size.Storage}
+	memory, err := requirements.MemoryBytes()
+	if err != nil {
+		return nil, fmt.Errorf("machine size %s: %w", value, err)
+	}
+	storage, err := requirements.StorageBytes()
+	if err != nil {
+		return nil, fmt.Errorf("machine size %s: %w", value, err)
+	}
+
+	gpu := size.GPU
+	return &devcontainerconfig.HostResources{
+		CPUs:    size.CPUs,
+		Memory:  memory,
+		Storage: storage,
+		GPU:     &gpu,
+	}, nil
+}
+
+func validateMachineSizes(providerConfig *ProviderConfig) error {
+	machineSizes := providerConfig.MachineSizes
+	if machineSizes == nil {
+		return nil
+	} else if machineSizes.Option == "" {
+		return fmt.Errorf("machineSizes.option is required")
+	} else if providerConfig.Options[machineSizes.Option] == nil {
+		return fmt.Errorf("machineSizes.option '%s' is not a provider option", machineSizes.Option)
+	}
+
+	for value := range machineSizes.Sizes {
+		_, err := providerConfig.MachineResources(map[string]config.OptionValue{machineSizes.Option: {Value: value}})
+		if err != nil {
+			return fmt.Errorf("machineSizes.sizes: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -121,6 +121,11 @@ func validate(config *ProviderConfig) error {
 		return err
 	}
 
+	err = validateMachineSizes(config)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -147,6 +152,9 @@ func validateProviderType(config *ProviderConfig) error {
 		if len(config.Exec.Delete) > 0 {
 			return fmt.Errorf("exec.create is not allowed in proxy providers")
 		}
+		if len(config.Exec.Plugin) > 0 {
+			return fmt.Errorf("exec.plugin is not allowed in proxy providers")
+		}
 		if len(config.Exec.Proxy.Status) == 0 {
 			return fmt.Errorf("exec.proxy.status is required for proxy providers")
 		}
@@ -189,6 +197,9 @@ func validateProviderType(config *ProviderConfig) error {
 		if len(config.Exec.Delete) > 0 {
 			return fmt.Errorf("exec.create is not allowed in daemon providers")
 		}
+		if len(config.Exec.Plugin) > 0 {
+			return fmt.Errorf("exec.plugin is not allowed in daemon providers")
+		}
 		if len(config.Exec.Daemon.Start) == 0 {
 			return fmt.Errorf("exec.daemon.start is required for daemon providers")
 		}
@@ -197,12 +208,12 @@ func validateProviderType(config *ProviderConfig) error {
 	}
 
 	// validate driver
-	if config.Agent.Driver != "" && config.Agent.Driver != CustomDriver && config.Agent.Driver != DockerDriver && config.Agent.Driver != KubernetesDriver {
-		return fmt.Errorf("agent.driver can only be docker, kubernetes or custom")
+	if config.Agent.Driver != "" && config.Agent.Driver != CustomDriver && config.Agent.Driver != DockerDriver && config.Agent.Driver != PodmanDriver && config.Agent.Driver != NerdctlDriver && config.Agent.Driver != HostDriver && config.Agent.Driver != KubernetesDriver {
+		return fmt.Errorf("agent.driver can only be docker, podman, nerdctl, kubernetes, host or custom")
 	}
 
 	// validate custom driver
-	if config.Agent.Driver == CustomDriver {
+	if config.Agent.Driver == CustomDriver && len(config.Agent.Custom.Command) == 0 {
 		if len(config.Agent.Custom.TargetArchitecture) == 0 {
 			return fmt.Errorf("agent.custom.targetArchitecture is required")
 		}
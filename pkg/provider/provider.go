@@ -44,6 +44,10 @@ type ProviderConfig struct {
 
 	// Binaries is an optional field to specify a binary to execute the commands
 	Binaries map[string][]*ProviderBinary `json:"binaries,omitempty"`
+
+	// MachineSizes declares the resources of the machines the provider creates. DevPod validates
+	// the hostRequirements of a devcontainer.json against it before creating a machine.
+	MachineSizes *ProviderMachineSizes `json:"machineSizes,omitempty"`
 }
 
 type ProviderOptionGroup struct {
@@ -113,12 +117,19 @@ type ProviderAgentConfig struct {
 	Dockerless ProviderDockerlessOptions `json:"dockerless,omitempty"`
 
 	// Driver is the driver to use for deploying the devcontainer. Currently supports
-	// docker (default) or kubernetes (experimental)
+	// docker (default), podman, nerdctl, kubernetes (experimental) or host, which runs the
+	// workspace directly on the machine without a container
 	Driver string `json:"driver,omitempty"`
 
 	// Docker holds docker specific configuration
 	Docker ProviderDockerDriverConfig `json:"docker,omitempty"`
 
+	// Podman holds podman specific configuration
+	Podman ProviderPodmanDriverConfig `json:"podman,omitempty"`
+
+	// Nerdctl holds nerdctl specific configuration
+	Nerdctl ProviderNerdctlDriverConfig `json:"nerdctl,omitempty"`
+
 	// Custom holds custom driver specific configuration
 	Custom ProviderCustomDriverConfig `json:"custom,omitempty"`
 
@@ -149,11 +160,18 @@ func (a ProviderAgentConfig) IsDockerDriver() bool {
 
 const (
 	DockerDriver     = "docker"
+	PodmanDriver     = "podman"
+	NerdctlDriver    = "nerdctl"
+	HostDriver       = "host"
 	KubernetesDriver = "kubernetes"
 	CustomDriver     = "custom"
 )
 
 type ProviderCustomDriverConfig struct {
+	// Command starts a long-lived driver process that speaks the JSON-RPC protocol over stdio.
+	// If defined, the commands below are not used.
+	Command types.StrArray `json:"command,omitempty"`
+
 	// FindDevContainer is used to find an existing devcontainer
 	FindDevContainer types.StrArray `json:"findDevContainer,omitempty"`
 
@@ -196,6 +214,59 @@ type ProviderDockerDriverConfig struct {
 	Env map[string]string `json:"env,omitempty"`
 }
 
+type ProviderPodmanDriverConfig struct {
+	// Path where to find the podman binary, defaults to 'podman'
+	Path string `json:"path,omitempty"`
+
+	// Environment variables to set when running podman commands
+	Env map[string]string `json:"env,omitempty"`
+}
+
+type ProviderNerdctlDriverConfig struct {
+	// Path where to find the nerdctl binary, defaults to 'nerdctl'
+	Path string `json:"path,omitempty"`
+
+	// Address of the containerd socket, defaults to the nerdctl default
+	Address string `json:"address,omitempty"`
+
+	// Namespace is the containerd namespace to run the containers in, defaults to 'default'
+	Namespace string `json:"namespace,omitempty"`
+
+	// BuildKitHost is the address of buildkitd that builds the images, defaults to the nerdctl default
+	BuildKitHost string `json:"buildKitHost,omitempty"`
+
+	// Environment variables to set when running nerdctl commands
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// Command returns the nerdctl binary to use
+func (c ProviderNerdctlDriverConfig) Command() string {
+	if c.Path != "" {
+		return c.Path
+	}
+
+	return "nerdctl"
+}
+
+// Environment returns the environment variables nerdctl commands run with
+func (c ProviderNerdctlDriverConfig) Environment() map[string]string {
+	env := map[string]string{}
+	if c.Address != "" {
+		env["CONTAINERD_ADDRESS"] = c.Address
+	}
+	if c.Namespace != "" {
+		env["CONTAINERD_NAMESPACE"] = c.Namespace
+	}
+	if c.BuildKitHost != "" {
+		env["BUILDKIT_HOST"] = c.BuildKitHost
+	}
+	for k, v := range c.Env {
+		env[k] = v
+	}
+
+	return env
+}
+
 type ProviderKubernetesDriverConfig struct {
 	KubernetesContext   string `json:"kubernetesContext,omitempty"`
 	KubernetesConfig    string `json:"kubernetesConfig,omitempty"`
@@ -219,9 +290,32 @@ type ProviderKubernetesDriverConfig struct {
 	WorkspaceVolumeMount string `json:"workspaceVolumeMount,omitempty"`
 
 	PodManifestTemplate string `json:"podManifestTemplate,omitempty"`
+	PodPatches          string `json:"podPatches,omitempty"`
 	Labels              string `json:"labels,omitempty"`
 
 	StrictSecurity string `json:"strictSecurity,omitempty"`
+
+	// BuildRepository is the repository images built in the cluster are pushed to
+	BuildRepository string `json:"buildRepository,omitempty"`
+	// BuildkitImage is the rootless buildkit image used to build in the cluster
+	BuildkitImage string `json:"buildkitImage,omitempty"`
+
+	// ExposePorts creates a service for the forwarded ports of the workspace
+	ExposePorts string `json:"exposePorts,omitempty"`
+	// IngressHost is the host template of the ingress for the forwarded ports, supports {port} and {workspace}
+	IngressHost        string `json:"ingressHost,omitempty"`
+	IngressClassName   string `json:"ingressClassName,omitempty"`
+	IngressAnnotations string `json:"ingressAnnotations,omitempty"`
+	IngressTLSSecret   string `json:"ingressTlsSecret,omitempty"`
+
+	// PvcRetentionPolicy defines what happens to the workspace volume on delete, one of Delete, Retain or Snapshot
+	PvcRetentionPolicy string `json:"pvcRetentionPolicy,omitempty"`
+	// PvcRetentionPeriod is the duration after which retained workspace volumes are deleted
+	PvcRetentionPeriod string `json:"pvcRetentionPeriod,omitempty"`
+	// VolumeSnapshotClass makes snapshots CSI VolumeSnapshots of this class instead of volume clones
+	VolumeSnapshotClass string `json:"volumeSnapshotClass,omitempty"`
+	// GoldenSnapshot is the snapshot new workspace volumes are provisioned from
+	GoldenSnapshot string `json:"goldenSnapshot,omitempty"`
 }
 
 type ProviderAgentConfigExec struct {
@@ -272,6 +366,10 @@ type ProviderCommands struct {
 	// Status retrieves the server status
 	Status types.StrArray `json:"status,omitempty"`
 
+	// Plugin starts a long-lived provider process that serves the create, delete, start, stop, status
+	// and command operations over JSON-RPC. The other commands are used if the plugin can't be started.
+	Plugin types.StrArray `json:"plugin,omitempty"`
+
 	// Proxy proxies commands
 	Proxy *ProxyCommands `json:"proxy,omitempty"`
 
@@ -50,6 +50,9 @@ type Workspace struct {
 	// DevContainerConfig holds the config for the devcontainer.json.
 	DevContainerConfig *devcontainerconfig.DevContainerConfig `json:"devContainerConfig,omitempty"`
 
+	// Labels are user defined key value pairs to group workspaces
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// CreationTimestamp is the timestamp when this workspace was created
 	CreationTimestamp types.Time `json:"creationTimestamp,omitempty"`
 
@@ -148,6 +151,10 @@ type ContainerWorkspaceInfo struct {
 	// to delete the container.
 	ContainerTimeout string `json:"containerInactivityTimeout,omitempty"`
 
+	// ShutdownGracePeriod is the duration to wait after the last session disconnected
+	// before the shutdownAction of the devcontainer.json is applied.
+	ShutdownGracePeriod string `json:"shutdownGracePeriod,omitempty"`
+
 	// Source is a WorkspaceSource to be used inside the container
 	Source WorkspaceSource `json:"source,omitempty"`
 
@@ -195,6 +202,10 @@ type AgentWorkspaceInfo struct {
 
 	// RegistryCache defines the registry to use for caching builds
 	RegistryCache string `json:"registryCache,omitempty"`
+
+	// ShutdownGracePeriod is the duration to wait after the last session disconnected
+	// before the shutdownAction of the devcontainer.json is applied.
+	ShutdownGracePeriod string `json:"shutdownGracePeriod,omitempty"`
 }
 
 type CLIOptions struct {
@@ -222,6 +233,11 @@ type CLIOptions struct {
 	GitSSHSigningKey            string            `json:"gitSshSigningKey,omitempty"`
 	SSHAuthSockID               string            `json:"sshAuthSockID,omitempty"` // ID to use when looking for SSH_AUTH_SOCK, defaults to a new random ID if not set (only used for browser IDEs)
 	StrictHostKeyChecking       bool              `json:"strictHostKeyChecking,omitempty"`
+	FromSnapshot                string            `json:"fromSnapshot,omitempty"`
+
+	// kubernetes options, they take precedence over the provider options
+	PvcRetentionPolicy string `json:"pvcRetentionPolicy,omitempty"`
+	GoldenSnapshot     string `json:"goldenSnapshot,omitempty"`
 
 	// build options
 	Repository string   `json:"repository,omitempty"`
@@ -9,13 +9,18 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/loft-sh/devpod/pkg/command"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/pkg/errors"
 )
 
 type CreateCommand func() (*exec.Cmd, error)
 
 func Single(file string, createCommand CreateCommand) error {
-	file = filepath.Join(os.TempDir(), file)
+	dir := hostdir.Dir()
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	file = filepath.Join(dir, file)
 	fileLock := flock.New(file + ".lock")
 	locked, err := fileLock.TryLock()
 	if err != nil {
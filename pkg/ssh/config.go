@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -171,6 +172,32 @@ func RemoveFromConfig(workspaceID string, sshConfigPath string, log log.Logger)
 	return writeSSHConfig(sshConfigPath, newFile, log)
 }
 
+// GetDevPodHosts returns the hosts of all DevPod managed sections in the ssh config
+func GetDevPodHosts(sshConfigPath string) ([]string, error) {
+	content, err := os.ReadFile(sshConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	hosts := []string{}
+	configScanner := scanner.NewScanner(bytes.NewReader(content))
+	for configScanner.Scan() {
+		text := configScanner.Text()
+		if strings.HasPrefix(text, MarkerStartPrefix) {
+			hosts = append(hosts, strings.TrimSpace(strings.TrimPrefix(text, MarkerStartPrefix)))
+		}
+	}
+	if configScanner.Err() != nil {
+		return nil, errors.Wrap(configScanner.Err(), "parse ssh config")
+	}
+
+	return hosts, nil
+}
+
 func writeSSHConfig(path, content string, log log.Logger) error {
 	err := os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
@@ -8,8 +8,10 @@ import (
 	"github.com/loft-sh/log"
 )
 
-func newConnectionCounter(ctx context.Context, timeout time.Duration, onTimeout func(), address string, log log.Logger) *connectionCounter {
-	return &connectionCounter{
+// NewConnectionCounter returns a counter that calls onTimeout once the last connection was closed
+// and no new connection was added within the timeout
+func NewConnectionCounter(ctx context.Context, timeout time.Duration, onTimeout func(), address string, log log.Logger) *ConnectionCounter {
+	return &ConnectionCounter{
 		ctx:       ctx,
 		address:   address,
 		timeout:   timeout,
@@ -18,7 +20,7 @@ func newConnectionCounter(ctx context.Context, timeout time.Duration, onTimeout
 	}
 }
 
-type connectionCounter struct {
+type ConnectionCounter struct {
 	address string
 
 	ctx       context.Context
@@ -31,7 +33,7 @@ type connectionCounter struct {
 	generation  int
 }
 
-func (c *connectionCounter) Add() {
+func (c *ConnectionCounter) Add() {
 	c.m.Lock()
 	defer c.m.Unlock()
 
@@ -39,7 +41,7 @@ func (c *connectionCounter) Add() {
 	c.log.Debugf("New connection on %s (Total: %d)", c.address, c.connections)
 }
 
-func (c *connectionCounter) Dec() {
+func (c *ConnectionCounter) Dec() {
 	c.m.Lock()
 	defer c.m.Unlock()
 
@@ -39,6 +39,24 @@ func PortForward(
 	)
 }
 
+// PortForwardListener forwards the connections accepted by the given local listener to the remote address
+func PortForwardListener(
+	ctx context.Context,
+	client *ssh.Client,
+	listener net.Listener,
+	remoteNetwork, remoteAddr string,
+	exitAfterTimeout time.Duration,
+	log log.Logger,
+) error {
+	defer listener.Close()
+
+	return portForwarding(
+		ctx, client, listener,
+		listener.Addr().Network(), listener.Addr().String(), remoteNetwork, remoteAddr,
+		exitAfterTimeout, log, forward,
+	)
+}
+
 func ReversePortForward(
 	ctx context.Context,
 	client *ssh.Client,
@@ -79,7 +97,7 @@ func portForwarding(
 		}
 	}()
 
-	counter := newConnectionCounter(ctx, exitAfterTimeout, func() {
+	counter := NewConnectionCounter(ctx, exitAfterTimeout, func() {
 		log.Fatal("Stopping devpod up, because it stayed idle for a while. You can disable this via 'devpod context set-options -o EXIT_AFTER_TIMEOUT=false'")
 	}, srcAddr, log)
 	for {
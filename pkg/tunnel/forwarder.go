@@ -2,9 +2,15 @@ package tunnel
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/netstat"
+	"github.com/loft-sh/devpod/pkg/open"
 	devssh "github.com/loft-sh/devpod/pkg/ssh"
 	"github.com/loft-sh/log"
 	"golang.org/x/crypto/ssh"
@@ -12,13 +18,39 @@ import (
 
 // newForwarder returns a new forwarder using an SSH client and list of ports to forward,
 // for each port a new go routine is used to manage the SSH channel
-func newForwarder(sshClient *ssh.Client, forwardedPorts []string, log log.Logger) netstat.Forwarder {
-	return &forwarder{
+func newForwarder(sshClient *ssh.Client, forwardedPorts []string, mergedConfig *config.MergedDevContainerConfig, log log.Logger) netstat.Forwarder {
+	f := &forwarder{
 		sshClient:      sshClient,
 		forwardedPorts: forwardedPorts,
-		portMap:        map[string]context.CancelFunc{},
+		portMap:        map[string]*forwardedPort{},
+		openedPorts:    map[string]bool{},
 		log:            log,
 	}
+	if mergedConfig != nil {
+		f.portsAttributes = mergedConfig.PortsAttributes
+		f.otherPortsAttributes = mergedConfig.OtherPortsAttributes
+		warnElevateIfNeeded(f.portsAttributes, log)
+	}
+
+	return f
+}
+
+// warnElevateIfNeeded warns about privileged ports that set elevateIfNeeded. DevPod doesn't elevate itself and only
+// detects ports from 1024 on in the container, so these ports are never forwarded automatically.
+func warnElevateIfNeeded(portsAttributes map[string]config.PortAttribute, log log.Logger) {
+	for key, attribute := range portsAttributes {
+		if !attribute.ElevateIfNeeded {
+			continue
+		}
+
+		start, _, _ := strings.Cut(key, "-")
+		startPort, err := strconv.Atoi(strings.TrimSpace(start))
+		if err != nil || startPort >= 1024 {
+			continue
+		}
+
+		log.Warnf("Port %s sets elevateIfNeeded, which is not supported. Ports below 1024 are not forwarded automatically, add the port to forwardPorts and run DevPod with elevated privileges instead", key)
+	}
 }
 
 // forwarder multiplexes a SSH client to forward ports to the remote container
@@ -28,8 +60,17 @@ type forwarder struct {
 	sshClient      *ssh.Client
 	forwardedPorts []string
 
-	portMap map[string]context.CancelFunc
-	log     log.Logger
+	portsAttributes      map[string]config.PortAttribute
+	otherPortsAttributes *config.PortAttribute
+
+	portMap     map[string]*forwardedPort
+	openedPorts map[string]bool
+	log         log.Logger
+}
+
+type forwardedPort struct {
+	cancel    context.CancelFunc
+	attribute config.PortAttribute
 }
 
 // Forward opens an SSH channel in the existing connection with channel type "direct-tcpip" to forward the local port
@@ -41,18 +82,58 @@ func (f *forwarder) Forward(port string) error {
 		return nil
 	}
 
+	attribute := f.portAttribute(port)
+	if attribute.OnAutoForward == config.OnAutoForwardIgnore {
+		f.log.Debugf("Skip port-forwarding on port %s, because onAutoForward is %s", port, config.OnAutoForwardIgnore)
+		return nil
+	}
+
+	listener, err := f.listen(port, attribute)
+	if err != nil {
+		// a failed port is not retried until the port is closed and reopened in the container
+		f.log.Errorf("Error port forwarding %s: %v", port, err)
+		return nil
+	}
+
 	cancelCtx, cancel := context.WithCancel(context.Background())
-	f.portMap[port] = cancel
-	f.log.Infof("Start port-forwarding on port %s", port)
+	f.portMap[port] = &forwardedPort{cancel: cancel, attribute: attribute}
+
+	localPort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	message := fmt.Sprintf("Start port-forwarding on port %s", port)
+	if attribute.Label != "" {
+		message = fmt.Sprintf("Start port-forwarding on port %s (%s)", port, attribute.Label)
+	}
+	if localPort != port {
+		message += " to local port " + localPort
+	}
+	if attribute.OnAutoForward == config.OnAutoForwardSilent {
+		f.log.Debug(message)
+	} else {
+		f.log.Info(message)
+	}
 
 	go func(port string) {
 		// do the forward
-		err := devssh.PortForward(cancelCtx, f.sshClient, "tcp", "localhost:"+port, "tcp", "localhost:"+port, 0, f.log)
-		if err != nil {
+		err := devssh.PortForwardListener(cancelCtx, f.sshClient, listener, "tcp", "localhost:"+port, 0, f.log)
+		if err != nil && cancelCtx.Err() == nil {
 			f.log.Errorf("Error port forwarding %s: %v", port, err)
 		}
 	}(port)
 
+	switch attribute.OnAutoForward {
+	case config.OnAutoForwardOpenBrowserOnce:
+		if f.openedPorts[port] {
+			break
+		}
+		fallthrough
+	case config.OnAutoForwardOpenBrowser, config.OnAutoForwardOpenPreview:
+		// there is no preview outside an IDE, so previews are opened in the browser
+		f.openedPorts[port] = true
+		go func() {
+			_ = open.Open(cancelCtx, portURL(attribute, localPort), f.log)
+		}()
+	}
+
 	return nil
 }
 
@@ -65,8 +146,12 @@ func (f *forwarder) StopForward(port string) error {
 		return nil
 	}
 
-	f.log.Infof("Stop port-forwarding on port %s", port)
-	f.portMap[port]()
+	if f.portMap[port].attribute.OnAutoForward == config.OnAutoForwardSilent {
+		f.log.Debugf("Stop port-forwarding on port %s", port)
+	} else {
+		f.log.Infof("Stop port-forwarding on port %s", port)
+	}
+	f.portMap[port].cancel()
 	delete(f.portMap, port)
 
 	return nil
@@ -81,3 +166,36 @@ func (f *forwarder) isExcluded(port string) bool {
 
 	return false
 }
+
+func (f *forwarder) portAttribute(port string) config.PortAttribute {
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return config.GetPortAttribute(nil, f.otherPortsAttributes, 0)
+	}
+
+	return config.GetPortAttribute(f.portsAttributes, f.otherPortsAttributes, portNumber)
+}
+
+// listen listens on the same local port as the remote port. If the port is not available, a random port is used
+// instead unless the port requires the same local port.
+func (f *forwarder) listen(port string, attribute config.PortAttribute) (net.Listener, error) {
+	listener, err := net.Listen("tcp", "localhost:"+port)
+	if err == nil {
+		return listener, nil
+	}
+
+	if attribute.RequireLocalPort {
+		return nil, fmt.Errorf("local port %s is not available and requireLocalPort is set: %w", port, err)
+	}
+
+	return net.Listen("tcp", "localhost:0")
+}
+
+func portURL(attribute config.PortAttribute, localPort string) string {
+	protocol := "http"
+	if attribute.Protocol == "https" {
+		protocol = "https"
+	}
+
+	return protocol + "://localhost:" + localPort
+}
@@ -0,0 +1,87 @@
+package tunnel
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/log"
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+// usedPort returns a port that is in use until the end of the test
+func usedPort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func newTestForwarder(portsAttributes map[string]config.PortAttribute) (*forwarder, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	f := newForwarder(nil, nil, &config.MergedDevContainerConfig{
+		DevContainerConfigBase: config.DevContainerConfigBase{PortsAttributes: portsAttributes},
+	}, log.NewStreamLogger(out, out, logrus.InfoLevel))
+
+	return f.(*forwarder), out
+}
+
+func TestForwardIgnore(t *testing.T) {
+	port := usedPort(t)
+	f, out := newTestForwarder(map[string]config.PortAttribute{
+		port: {OnAutoForward: config.OnAutoForwardIgnore},
+	})
+
+	assert.NilError(t, f.Forward(port))
+	assert.Assert(t, f.portMap[port] == nil)
+	assert.Equal(t, out.String(), "")
+}
+
+func TestForwardSilent(t *testing.T) {
+	port := usedPort(t)
+	f, out := newTestForwarder(map[string]config.PortAttribute{
+		port: {OnAutoForward: config.OnAutoForwardSilent},
+	})
+
+	assert.NilError(t, f.Forward(port))
+	assert.Assert(t, f.portMap[port] != nil)
+	assert.NilError(t, f.StopForward(port))
+	assert.Equal(t, out.String(), "")
+}
+
+func TestForwardRequireLocalPort(t *testing.T) {
+	port := usedPort(t)
+	f, out := newTestForwarder(map[string]config.PortAttribute{
+		port: {RequireLocalPort: true},
+	})
+
+	assert.NilError(t, f.Forward(port))
+	assert.Assert(t, f.portMap[port] == nil)
+	assert.Assert(t, strings.Contains(out.String(), "requireLocalPort is set"), out.String())
+
+	// without requireLocalPort a random local port is used instead
+	f, out = newTestForwarder(nil)
+	assert.NilError(t, f.Forward(port))
+	assert.Assert(t, f.portMap[port] != nil)
+	assert.NilError(t, f.StopForward(port))
+	assert.Assert(t, strings.Contains(out.String(), "Start port-forwarding on port "+port+" to local port"), out.String())
+}
+
+func TestWarnElevateIfNeeded(t *testing.T) {
+	_, out := newTestForwarder(map[string]config.PortAttribute{
+		"80":        {ElevateIfNeeded: true},
+		"3000":      {ElevateIfNeeded: true},
+		"443-8443":  {ElevateIfNeeded: true},
+		"9000-9100": {ElevateIfNeeded: true},
+		"22":        {},
+	})
+
+	assert.Assert(t, strings.Contains(out.String(), "Port 80 sets elevateIfNeeded"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "Port 443-8443 sets elevateIfNeeded"), out.String())
+	assert.Equal(t, strings.Count(out.String(), "elevateIfNeeded"), 2)
+}
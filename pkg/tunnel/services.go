@@ -20,6 +20,7 @@ import (
 	config2 "github.com/loft-sh/devpod/pkg/devcontainer/config"
 	"github.com/loft-sh/devpod/pkg/devcontainer/setup"
 	"github.com/loft-sh/devpod/pkg/gitsshsigning"
+	"github.com/loft-sh/devpod/pkg/hostdir"
 	"github.com/loft-sh/devpod/pkg/ide/openvscode"
 	"github.com/loft-sh/devpod/pkg/netstat"
 	"github.com/loft-sh/devpod/pkg/provider"
@@ -52,7 +53,7 @@ func RunServices(
 	}
 
 	// forward ports
-	forwardedPorts, err := forwardDevContainerPorts(ctx, containerClient, extraPorts, exitAfterTimeout, log)
+	forwardedPorts, mergedConfig, err := forwardDevContainerPorts(ctx, containerClient, extraPorts, exitAfterTimeout, log)
 	if err != nil {
 		return errors.Wrap(err, "forward ports")
 	}
@@ -84,7 +85,7 @@ func RunServices(
 		// create a port forwarder
 		var forwarder netstat.Forwarder
 		if forwardPorts {
-			forwarder = newForwarder(containerClient, append(forwardedPorts, fmt.Sprintf("%d", openvscode.DefaultVSCodePort)), log)
+			forwarder = newForwarder(containerClient, append(forwardedPorts, fmt.Sprintf("%d", openvscode.DefaultVSCodePort)), mergedConfig, log)
 		}
 
 		errChan := make(chan error, 1)
@@ -147,20 +148,20 @@ func RunServices(
 	})
 }
 
-// forwardDevContainerPorts forwards all the ports defined in the devcontainer.json
-func forwardDevContainerPorts(ctx context.Context, containerClient *ssh.Client, extraPorts []string, exitAfterTimeout time.Duration, log log.Logger) ([]string, error) {
+// forwardDevContainerPorts forwards all the ports defined in the devcontainer.json and returns them together with the merged config
+func forwardDevContainerPorts(ctx context.Context, containerClient *ssh.Client, extraPorts []string, exitAfterTimeout time.Duration, log log.Logger) ([]string, *config2.MergedDevContainerConfig, error) {
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
-	err := devssh.Run(ctx, containerClient, "cat "+setup.ResultLocation, nil, stdout, stderr, nil)
+	err := devssh.Run(ctx, containerClient, "cat "+hostdir.ShellPath(setup.ResultLocation), nil, stdout, stderr, nil)
 	if err != nil {
-		return nil, fmt.Errorf("retrieve container result: %s\n%s%w", stdout.String(), stderr.String(), err)
+		return nil, nil, fmt.Errorf("retrieve container result: %s\n%s%w", stdout.String(), stderr.String(), err)
 	}
 
 	// parse result
 	result := &config2.Result{}
 	err = json.Unmarshal(stdout.Bytes(), result)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing container result %s: %w", stdout.String(), err)
+		return nil, nil, fmt.Errorf("error parsing container result %s: %w", stdout.String(), err)
 	}
 	log.Debugf("Successfully parsed result at %s", setup.ResultLocation)
 
@@ -184,6 +185,9 @@ func forwardDevContainerPorts(ctx context.Context, containerClient *ssh.Client,
 		if err != nil {
 			log.Debugf("Error parsing forwardPort %s: %v", port, err)
 		}
+		if attribute := config2.GetPortAttribute(result.MergedConfig.PortsAttributes, result.MergedConfig.OtherPortsAttributes, int(portNumber)); attribute.Label != "" {
+			log.Infof("Forward port %s (%s)", port, attribute.Label)
+		}
 
 		// try to forward
 		go func(port string) {
@@ -206,7 +210,7 @@ func forwardDevContainerPorts(ctx context.Context, containerClient *ssh.Client,
 		forwardedPorts = append(forwardedPorts, port)
 	}
 
-	return forwardedPorts, nil
+	return forwardedPorts, result.MergedConfig, nil
 }
 
 func forwardPort(ctx context.Context, containerClient *ssh.Client, port string, exitAfterTimeout time.Duration, log log.Logger) []string {
@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/alessio/shellescape"
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/client"
+	provider2 "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"github.com/sirupsen/logrus"
+)
+
+// ExecuteAgentWorkspaceCommand runs `agent workspace <args> --workspace-info <info>` on the machine of the given workspace.
+// Stdout of the agent command is written to stdout, its log output is forwarded to the given logger.
+func ExecuteAgentWorkspaceCommand(
+	ctx context.Context,
+	workspaceClient client.WorkspaceClient,
+	cliOptions provider2.CLIOptions,
+	args []string,
+	stdout io.Writer,
+	log log.Logger,
+) error {
+	workspaceInfo, agentInfo, err := workspaceClient.AgentInfo(cliOptions)
+	if err != nil {
+		return err
+	}
+
+	command := getAgentWorkspaceCommand(workspaceClient.AgentPath(), args, workspaceInfo, log.GetLevel() == logrus.DebugLevel)
+
+	writer := log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	return agent.InjectAgentAndExecute(
+		ctx,
+		func(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+			return workspaceClient.Command(ctx, client.CommandOptions{
+				Command: command,
+				Stdin:   stdin,
+				Stdout:  stdout,
+				Stderr:  stderr,
+			})
+		},
+		workspaceClient.AgentLocal(),
+		workspaceClient.AgentPath(),
+		workspaceClient.AgentURL(),
+		true,
+		command,
+		nil,
+		stdout,
+		writer,
+		log.ErrorStreamOnly(),
+		agentInfo.InjectTimeout,
+	)
+}
+
+func getAgentWorkspaceCommand(agentPath string, args []string, workspaceInfo string, debug bool) string {
+	command := fmt.Sprintf("'%s' agent workspace %s --workspace-info '%s'", agentPath, shellescape.QuoteCommand(args), workspaceInfo)
+	if debug {
+		command += " --debug"
+	}
+
+	return command
+}
@@ -0,0 +1,15 @@
+package workspace
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestGetAgentWorkspaceCommand(t *testing.T) {
+	command := getAgentWorkspaceCommand("/usr/local/bin/devpod", []string{"snapshot", "create", "it's; rm -rf /"}, "info", false)
+	assert.Equal(t, command, `'/usr/local/bin/devpod' agent workspace snapshot create 'it'"'"'s; rm -rf /' --workspace-info 'info'`)
+
+	command = getAgentWorkspaceCommand("/usr/local/bin/devpod", []string{"hooks", "run"}, "info", true)
+	assert.Equal(t, command, `'/usr/local/bin/devpod' agent workspace hooks run --workspace-info 'info' --debug`)
+}
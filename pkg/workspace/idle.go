@@ -0,0 +1,128 @@
+package workspace
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+)
+
+type IdleState string
+
+const (
+	IdleStateActive  IdleState = "Active"
+	IdleStateWarning IdleState = "Warning"
+	IdleStateExpired IdleState = "Expired"
+)
+
+// IdlePolicy specifies what happens with workspaces of a context that weren't used for a while
+type IdlePolicy struct {
+	// TTL is the duration without usage after which a workspace expires
+	TTL time.Duration
+
+	// Warning is the duration before expiry in which DevPod warns about a workspace
+	Warning time.Duration
+
+	// Action is either config.IdleActionStop or config.IdleActionDelete
+	Action string
+}
+
+// IdleWorkspace is a workspace that is about to expire or already expired
+type IdleWorkspace struct {
+	Workspace *providerpkg.Workspace
+	State     IdleState
+	ExpiresAt time.Time
+}
+
+// GetIdlePolicy returns the idle policy of the current context or nil if expiry is disabled
+func GetIdlePolicy(devPodConfig *config.Config) (*IdlePolicy, error) {
+	ttl, err := parseDays(devPodConfig.ContextOption(config.ContextOptionIdleTTL))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", config.ContextOptionIdleTTL, err)
+	} else if ttl == 0 {
+		return nil, nil
+	}
+
+	warning, err := parseDays(devPodConfig.ContextOption(config.ContextOptionIdleWarning))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", config.ContextOptionIdleWarning, err)
+	}
+
+	action := devPodConfig.ContextOption(config.ContextOptionIdleAction)
+	if action != config.IdleActionStop && action != config.IdleActionDelete {
+		return nil, fmt.Errorf("unsupported %s %s, expected %s or %s", config.ContextOptionIdleAction, action, config.IdleActionStop, config.IdleActionDelete)
+	}
+
+	return &IdlePolicy{
+		TTL:     ttl,
+		Warning: warning,
+		Action:  action,
+	}, nil
+}
+
+// State returns the idle state of the workspace at the given time and when it expires
+func (p *IdlePolicy) State(workspace *providerpkg.Workspace, now time.Time) (IdleState, time.Time) {
+	lastUsed := workspace.LastUsedTimestamp.Time
+	if lastUsed.IsZero() {
+		lastUsed = workspace.CreationTimestamp.Time
+	}
+	if lastUsed.IsZero() {
+		return IdleStateActive, time.Time{}
+	}
+
+	expiresAt := lastUsed.Add(p.TTL)
+	if !now.Before(expiresAt) {
+		return IdleStateExpired, expiresAt
+	} else if !now.Before(expiresAt.Add(-p.Warning)) {
+		return IdleStateWarning, expiresAt
+	}
+
+	return IdleStateActive, expiresAt
+}
+
+// ListIdle returns the local workspaces of the current context that are about to expire or already expired.
+// Pro workspaces are skipped, because their lifecycle is managed by the platform.
+func ListIdle(devPodConfig *config.Config, policy *IdlePolicy, now time.Time, log log.Logger) ([]*IdleWorkspace, error) {
+	workspaces, err := ListLocalWorkspaces(devPodConfig.DefaultContext, true, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterIdle(workspaces, policy, now), nil
+}
+
+func filterIdle(workspaces []*providerpkg.Workspace, policy *IdlePolicy, now time.Time) []*IdleWorkspace {
+	retWorkspaces := []*IdleWorkspace{}
+	for _, workspace := range workspaces {
+		state, expiresAt := policy.State(workspace, now)
+		if state == IdleStateActive {
+			continue
+		}
+
+		retWorkspaces = append(retWorkspaces, &IdleWorkspace{
+			Workspace: workspace,
+			State:     state,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	return retWorkspaces
+}
+
+func parseDays(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	} else if days < 0 {
+		return 0, fmt.Errorf("days cannot be negative")
+	}
+
+	return time.Duration(days * float64(24*time.Hour)), nil
+}
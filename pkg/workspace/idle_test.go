@@ -0,0 +1,62 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/devpod/pkg/types"
+	"gotest.tools/assert"
+)
+
+func TestIdlePolicyState(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	policy := &IdlePolicy{
+		TTL:     7 * 24 * time.Hour,
+		Warning: 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name     string
+		lastUsed time.Time
+		created  time.Time
+		want     IdleState
+	}{
+		{
+			name:     "Recently used",
+			lastUsed: now.Add(-2 * 24 * time.Hour),
+			want:     IdleStateActive,
+		},
+		{
+			name:     "Within warning window",
+			lastUsed: now.Add(-6*24*time.Hour - time.Hour),
+			want:     IdleStateWarning,
+		},
+		{
+			name:     "Expired",
+			lastUsed: now.Add(-8 * 24 * time.Hour),
+			want:     IdleStateExpired,
+		},
+		{
+			name:    "Never used falls back to creation",
+			created: now.Add(-10 * 24 * time.Hour),
+			want:    IdleStateExpired,
+		},
+		{
+			name: "Unknown usage",
+			want: IdleStateActive,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workspace := &providerpkg.Workspace{
+				LastUsedTimestamp: types.Time{Time: test.lastUsed},
+				CreationTimestamp: types.Time{Time: test.created},
+			}
+
+			state, _ := policy.State(workspace, now)
+			assert.Equal(t, state, test.want)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/platform"
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ParseLabels parses labels in the form key=value. Keys ending with a dash, e.g. key-, are returned as labels to remove.
+func ParseLabels(raw []string, allowRemove bool) (map[string]string, []string, error) {
+	set := map[string]string{}
+	remove := []string{}
+	for _, label := range raw {
+		if allowRemove && strings.HasSuffix(label, "-") && !strings.Contains(label, "=") {
+			key := strings.TrimSuffix(label, "-")
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return nil, nil, fmt.Errorf("invalid label key %s: %s", key, strings.Join(errs, ", "))
+			}
+
+			remove = append(remove, key)
+			continue
+		}
+
+		key, value, found := strings.Cut(label, "=")
+		if !found {
+			return nil, nil, fmt.Errorf("invalid label %s, expected key=value", label)
+		} else if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("invalid label key %s: %s", key, strings.Join(errs, ", "))
+		} else if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("invalid label value %s: %s", value, strings.Join(errs, ", "))
+		}
+
+		set[key] = value
+	}
+
+	return set, remove, nil
+}
+
+// UpdateLabels sets and removes labels of an existing workspace and saves its config
+func UpdateLabels(devPodConfig *config.Config, workspaceID string, set map[string]string, remove []string) (*providerpkg.Workspace, error) {
+	workspaceConfig, err := providerpkg.LoadWorkspaceConfig(devPodConfig.DefaultContext, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceConfig.Labels == nil {
+		workspaceConfig.Labels = map[string]string{}
+	}
+	for k, v := range set {
+		workspaceConfig.Labels[k] = v
+	}
+	for _, k := range remove {
+		delete(workspaceConfig.Labels, k)
+	}
+
+	err = providerpkg.SaveWorkspaceConfig(workspaceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("save workspace: %w", err)
+	}
+
+	return workspaceConfig, nil
+}
+
+// ListSelected lists all workspaces whose labels match the given selector, e.g. team=a,env!=prod
+func ListSelected(ctx context.Context, devPodConfig *config.Config, selector string, owner platform.OwnerFilter, log log.Logger) ([]*providerpkg.Workspace, error) {
+	parsedSelector, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parse selector: %w", err)
+	}
+
+	workspaces, err := List(ctx, devPodConfig, false, owner, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return MatchSelector(workspaces, parsedSelector), nil
+}
+
+// MatchSelector returns the workspaces whose labels match the given selector
+func MatchSelector(workspaces []*providerpkg.Workspace, selector labels.Selector) []*providerpkg.Workspace {
+	retWorkspaces := []*providerpkg.Workspace{}
+	for _, workspace := range workspaces {
+		if selector.Matches(labels.Set(workspace.Labels)) {
+			retWorkspaces = append(retWorkspaces, workspace)
+		}
+	}
+
+	return retWorkspaces
+}
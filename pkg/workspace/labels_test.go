@@ -0,0 +1,37 @@
+package workspace
+
+import (
+	"testing"
+
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseLabels(t *testing.T) {
+	set, remove, err := ParseLabels([]string{"team=backend", "ticket-"}, true)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, set, map[string]string{"team": "backend"})
+	assert.DeepEqual(t, remove, []string{"ticket"})
+
+	_, _, err = ParseLabels([]string{"ticket-"}, false)
+	assert.ErrorContains(t, err, "expected key=value")
+
+	_, _, err = ParseLabels([]string{"team=back end"}, false)
+	assert.ErrorContains(t, err, "invalid label value")
+}
+
+func TestMatchSelector(t *testing.T) {
+	workspaces := []*providerpkg.Workspace{
+		{ID: "a", Labels: map[string]string{"team": "backend", "env": "dev"}},
+		{ID: "b", Labels: map[string]string{"team": "backend", "env": "prod"}},
+		{ID: "c"},
+	}
+
+	selector, err := labels.Parse("team=backend,env!=prod")
+	assert.NilError(t, err)
+
+	matched := MatchSelector(workspaces, selector)
+	assert.Equal(t, len(matched), 1)
+	assert.Equal(t, matched[0].ID, "a")
+}
@@ -0,0 +1,229 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/loft-sh/devpod/pkg/config"
+	"github.com/loft-sh/devpod/pkg/file"
+	"github.com/loft-sh/devpod/pkg/git"
+	"github.com/loft-sh/devpod/pkg/options"
+	"github.com/loft-sh/devpod/pkg/platform"
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+)
+
+// PlannedWorkspace is a workspace resolved by ResolvePlan. New workspaces and machines only exist in memory.
+type PlannedWorkspace struct {
+	Provider  *providerpkg.ProviderConfig
+	Workspace *providerpkg.Workspace
+	Machine   *providerpkg.Machine
+
+	// Options are the resolved provider options
+	Options map[string]config.OptionValue
+
+	// Exists is true if the workspace already exists
+	Exists bool
+
+	// CreateMachine is true if devpod up would create a new machine
+	CreateMachine bool
+}
+
+// ResolvePlan takes the `devpod up --dry-run` CLI input and resolves the workspace, its provider and provider options
+// the same way Resolve does, but without saving the workspace or creating a machine.
+func ResolvePlan(
+	ctx context.Context,
+	devPodConfig *config.Config,
+	args []string,
+	desiredID,
+	desiredMachine string,
+	providerUserOptions []string,
+	devContainerImage string,
+	devContainerPath string,
+	source *providerpkg.WorkspaceSource,
+	uid string,
+	owner platform.OwnerFilter,
+	log log.Logger,
+) (*PlannedWorkspace, error) {
+	planned, err := resolvePlannedWorkspace(ctx, devPodConfig, args, desiredID, desiredMachine, source, uid, owner, log)
+	if err != nil {
+		return nil, err
+	} else if planned.Provider.IsProxyProvider() || planned.Provider.IsDaemonProvider() {
+		return nil, fmt.Errorf("dry run is not supported for provider %s", planned.Provider.Name)
+	}
+
+	// configure dev container source
+	if devContainerImage != "" {
+		planned.Workspace.DevContainerImage = devContainerImage
+	}
+	if devContainerPath != "" {
+		planned.Workspace.DevContainerPath = devContainerPath
+	}
+
+	// resolve provider options
+	userOptions, err := providerpkg.ParseOptions(providerUserOptions)
+	if err != nil {
+		return nil, fmt.Errorf("parse options: %w", err)
+	}
+	planned.Options, err = options.ResolveOptionsDryRun(ctx, devPodConfig, planned.Provider, planned.Workspace, planned.Machine, userOptions, log)
+	if err != nil {
+		return nil, err
+	}
+	if planned.Machine != nil {
+		planned.Machine.Provider.Options = planned.Options
+	} else {
+		planned.Workspace.Provider.Options = planned.Options
+	}
+
+	return planned, nil
+}
+
+func resolvePlannedWorkspace(
+	ctx context.Context,
+	devPodConfig *config.Config,
+	args []string,
+	desiredID,
+	desiredMachine string,
+	source *providerpkg.WorkspaceSource,
+	uid string,
+	owner platform.OwnerFilter,
+	log log.Logger,
+) (*PlannedWorkspace, error) {
+	err := validateWorkspaceID(desiredID)
+	if err != nil {
+		return nil, err
+	}
+
+	// find existing workspace
+	workspaceID := desiredID
+	isLocalPath, name := false, ""
+	if len(args) == 0 {
+		if desiredID == "" {
+			return nil, errProvideWorkspaceArg
+		} else if findWorkspace(ctx, devPodConfig, nil, desiredID, owner, log) == nil {
+			return nil, fmt.Errorf("workspace %s doesn't exist", desiredID)
+		}
+	} else {
+		isLocalPath, name = file.IsLocalDir(args[0])
+		if workspaceID == "" {
+			workspaceID = ToID(name)
+		}
+	}
+	if Exists(ctx, devPodConfig, nil, workspaceID, owner, log) != "" {
+		provider, workspace, machine, err := loadExistingWorkspace(devPodConfig, workspaceID, false, log)
+		if err != nil {
+			return nil, err
+		}
+
+		return &PlannedWorkspace{
+			Provider:  provider,
+			Workspace: workspace,
+			Machine:   machine,
+			Exists:    true,
+		}, nil
+	}
+
+	// get default provider
+	provider, _, err := LoadProviders(devPodConfig, log)
+	if err != nil {
+		return nil, err
+	} else if provider.State == nil || !provider.State.Initialized {
+		return nil, fmt.Errorf("provider '%s' is not initialized, please make sure to run 'devpod provider use %s' at least once before using this provider", provider.Config.Name, provider.Config.Name)
+	}
+
+	// resolve workspace
+	workspace, err := resolveWorkspaceConfig(ctx, provider, devPodConfig, name, workspaceID, source, isLocalPath, "", uid)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := &PlannedWorkspace{
+		Provider:  provider.Config,
+		Workspace: workspace,
+	}
+	if !provider.Config.IsMachineProvider() {
+		return planned, nil
+	}
+
+	// resolve machine
+	if desiredMachine != "" {
+		if !providerpkg.MachineExists(workspace.Context, desiredMachine) {
+			return nil, fmt.Errorf("server %s doesn't exist and cannot be used", desiredMachine)
+		}
+
+		workspace.Machine.ID = desiredMachine
+	} else if provider.State != nil && provider.State.SingleMachine {
+		workspace.Machine.ID = SingleMachineName(devPodConfig, provider.Config.Name, log)
+	}
+	if workspace.Machine.ID != "" && providerpkg.MachineExists(workspace.Context, workspace.Machine.ID) {
+		planned.Machine, err = providerpkg.LoadMachineConfig(workspace.Context, workspace.Machine.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load machine config: %w", err)
+		}
+
+		return planned, nil
+	}
+
+	planned.CreateMachine = true
+	planned.Machine = &providerpkg.Machine{
+		ID:      workspace.Machine.ID,
+		Context: workspace.Context,
+		Provider: providerpkg.MachineProviderConfig{
+			Name: provider.Config.Name,
+		},
+	}
+	return planned, nil
+}
+
+// PrepareContentFolder makes the source of a planned workspace available locally. Git repositories are cloned
+// and images are wrapped in a devcontainer.json within a temporary folder that is removed by the returned function.
+func PrepareContentFolder(ctx context.Context, workspace *providerpkg.Workspace, strictHostKeyChecking bool, log log.Logger) (string, func(), error) {
+	if workspace.Source.LocalFolder != "" {
+		return workspace.Source.LocalFolder, func() {}, nil
+	} else if workspace.Source.Container != "" {
+		return "", func() {}, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "devpod-plan-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tempDir) }
+
+	if workspace.Source.GitRepository != "" {
+		// only fetch history if we need to checkout a specific revision
+		cloneStrategy := git.ShallowCloneStrategy
+		if workspace.Source.GitCommit != "" || workspace.Source.GitPRReference != "" {
+			cloneStrategy = git.BloblessCloneStrategy
+		}
+
+		gitInfo := git.NewGitInfo(workspace.Source.GitRepository, workspace.Source.GitBranch, workspace.Source.GitCommit, workspace.Source.GitPRReference, workspace.Source.GitSubPath)
+		err = git.CloneRepository(ctx, gitInfo, tempDir, "", strictHostKeyChecking, log, git.WithCloneStrategy(cloneStrategy))
+		if err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("clone repository: %w", err)
+		}
+
+		return tempDir, cleanup, nil
+	} else if workspace.Source.Image != "" {
+		out, err := json.MarshalIndent(map[string]string{"image": workspace.Source.Image}, "", "  ")
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+
+		err = os.WriteFile(filepath.Join(tempDir, ".devcontainer.json"), out, 0o600)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+
+		return tempDir, cleanup, nil
+	}
+
+	cleanup()
+	return "", nil, fmt.Errorf("either workspace repository, image, container or local-folder is required")
+}
@@ -0,0 +1,51 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	providerpkg "github.com/loft-sh/devpod/pkg/provider"
+	"github.com/loft-sh/log"
+	"gotest.tools/assert"
+)
+
+func TestPrepareContentFolderImage(t *testing.T) {
+	image := `my"registry/image:latest`
+	contentFolder, cleanup, err := PrepareContentFolder(context.Background(), &providerpkg.Workspace{
+		Source: providerpkg.WorkspaceSource{Image: image},
+	}, false, log.Discard)
+	assert.NilError(t, err)
+	defer cleanup()
+
+	out, err := os.ReadFile(filepath.Join(contentFolder, ".devcontainer.json"))
+	assert.NilError(t, err)
+
+	devContainerConfig := map[string]string{}
+	err = json.Unmarshal(out, &devContainerConfig)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devContainerConfig, map[string]string{"image": image})
+
+	cleanup()
+	_, err = os.Stat(contentFolder)
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestPrepareContentFolder(t *testing.T) {
+	contentFolder, _, err := PrepareContentFolder(context.Background(), &providerpkg.Workspace{
+		Source: providerpkg.WorkspaceSource{LocalFolder: "/my/project"},
+	}, false, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, contentFolder, "/my/project")
+
+	contentFolder, _, err = PrepareContentFolder(context.Background(), &providerpkg.Workspace{
+		Source: providerpkg.WorkspaceSource{Container: "my-container"},
+	}, false, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, contentFolder, "")
+
+	_, _, err = PrepareContentFolder(context.Background(), &providerpkg.Workspace{}, false, log.Discard)
+	assert.ErrorContains(t, err, "is required")
+}
@@ -0,0 +1,353 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/devpod/pkg/agent"
+	"github.com/loft-sh/devpod/pkg/config"
+	devcontainerconfig "github.com/loft-sh/devpod/pkg/devcontainer/config"
+	"github.com/loft-sh/devpod/pkg/docker"
+	"github.com/loft-sh/devpod/pkg/ssh"
+	"github.com/loft-sh/log"
+)
+
+const (
+	PruneTypeContainer      = "container"
+	PruneTypeVolume         = "volume"
+	PruneTypeImage          = "image"
+	PruneTypeAgentWorkspace = "agent workspace"
+	PruneTypeSSHHost        = "ssh host"
+)
+
+var prebuildHashTagRegEx = regexp.MustCompile(`^devpod-[0-9a-f]{32}$`)
+
+// workspace volumes are named after the workspace uid or id, see the dockerless build and jetbrains backend
+var pruneVolumePrefixes = []string{"dockerless-", "devpod-"}
+
+// PruneCandidate is a resource created by DevPod that doesn't belong to any workspace anymore
+type PruneCandidate struct {
+	Type string
+	Name string
+
+	remove func(ctx context.Context) error
+}
+
+// Remove removes the resource
+func (p *PruneCandidate) Remove(ctx context.Context) error {
+	return p.remove(ctx)
+}
+
+type knownWorkspaces struct {
+	// ids holds the workspace ids per context
+	ids map[string]map[string]bool
+
+	// all holds all workspace ids and uids
+	all map[string]bool
+
+	sshConfigPaths []string
+}
+
+// FindPruneCandidates finds DevPod containers, volumes and images on the local docker host as well as agent
+// workspace folders and ssh config entries that don't correspond to a workspace in any context anymore
+func FindPruneCandidates(ctx context.Context, devPodConfig *config.Config, dockerHelper *docker.DockerHelper, log log.Logger) ([]*PruneCandidate, error) {
+	known, err := findKnownWorkspaces(devPodConfig, log)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*PruneCandidate{}
+	dockerCandidates, err := findDockerPruneCandidates(ctx, dockerHelper, known)
+	if err != nil {
+		log.Warnf("Skip pruning docker resources: %v", err)
+	} else {
+		candidates = append(candidates, dockerCandidates...)
+	}
+
+	agentCandidates, err := findAgentPruneCandidates(known)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, agentCandidates...)
+
+	sshCandidates, err := findSSHPruneCandidates(known)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, sshCandidates...)
+
+	return candidates, nil
+}
+
+func findKnownWorkspaces(devPodConfig *config.Config, log log.Logger) (*knownWorkspaces, error) {
+	known := &knownWorkspaces{
+		ids: map[string]map[string]bool{},
+		all: map[string]bool{},
+	}
+
+	sshConfigPaths := map[string]bool{"": true}
+	for contextName, contextConfig := range devPodConfig.Contexts {
+		if contextConfig.Options != nil {
+			sshConfigPaths[contextConfig.Options[config.ContextOptionSSHConfigPath].Value] = true
+		}
+
+		workspaces, err := ListLocalWorkspaces(contextName, false, log)
+		if err != nil {
+			return nil, fmt.Errorf("list workspaces in context %s: %w", contextName, err)
+		}
+
+		known.ids[contextName] = map[string]bool{}
+		for _, workspace := range workspaces {
+			known.ids[contextName][workspace.ID] = true
+			known.all[workspace.ID] = true
+			if workspace.UID != "" {
+				known.all[workspace.UID] = true
+			}
+			sshConfigPaths[workspace.SSHConfigPath] = true
+		}
+	}
+
+	resolvedPaths := map[string]bool{}
+	for sshConfigPath := range sshConfigPaths {
+		resolvedPath, err := ssh.ResolveSSHConfigPath(sshConfigPath)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvedPaths[resolvedPath] = true
+	}
+	for resolvedPath := range resolvedPaths {
+		known.sshConfigPaths = append(known.sshConfigPaths, resolvedPath)
+	}
+	sort.Strings(known.sshConfigPaths)
+
+	return known, nil
+}
+
+func findDockerPruneCandidates(ctx context.Context, dockerHelper *docker.DockerHelper, known *knownWorkspaces) ([]*PruneCandidate, error) {
+	candidates := []*PruneCandidate{}
+
+	// containers
+	containerIDs, err := listDockerIDs(ctx, dockerHelper, "ps", "-q", "-a")
+	if err != nil {
+		return nil, err
+	}
+	usedImages := map[string]bool{}
+	if len(containerIDs) > 0 {
+		containers, err := dockerHelper.InspectContainers(ctx, containerIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range containers {
+			workspaceID, ok := container.Config.Labels[devcontainerconfig.DockerIDLabel]
+			if !ok || known.all[workspaceID] {
+				usedImages[container.Image] = true
+				continue
+			}
+
+			containerID := container.ID
+			running := strings.ToLower(container.State.Status) == "running"
+			candidates = append(candidates, &PruneCandidate{
+				Type: PruneTypeContainer,
+				Name: containerID[:min(12, len(containerID))] + " (" + workspaceID + ")",
+				remove: func(ctx context.Context) error {
+					if running {
+						err := dockerHelper.Stop(ctx, containerID)
+						if err != nil {
+							return err
+						}
+					}
+
+					return dockerHelper.Remove(ctx, containerID)
+				},
+			})
+		}
+	}
+
+	// volumes that aren't mounted by any container anymore
+	volumes, err := listDockerIDs(ctx, dockerHelper, "volume", "ls", "-q", "--filter", "dangling=true")
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes {
+		if !isOrphanedVolume(volume, known) {
+			continue
+		}
+
+		volumeName := volume
+		candidates = append(candidates, &PruneCandidate{
+			Type: PruneTypeVolume,
+			Name: volumeName,
+			remove: func(ctx context.Context) error {
+				return dockerHelper.DeleteVolume(ctx, volumeName)
+			},
+		})
+	}
+
+	// images built by DevPod that are not used by any container
+	out, err := dockerOutput(ctx, dockerHelper, "image", "ls", "--no-trunc", "--format", "{{.ID}} {{.Repository}}:{{.Tag}}")
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range parseDevPodImages(string(out)) {
+		if usedImages[image.id] {
+			continue
+		}
+
+		reference := image.reference
+		candidates = append(candidates, &PruneCandidate{
+			Type: PruneTypeImage,
+			Name: reference,
+			remove: func(ctx context.Context) error {
+				_, err := dockerOutput(ctx, dockerHelper, "rmi", reference)
+				return err
+			},
+		})
+	}
+
+	return candidates, nil
+}
+
+type devPodImage struct {
+	id        string
+	reference string
+}
+
+// parseDevPodImages returns the images of docker image ls that were built by DevPod. These are tagged with
+// their prebuild hash, see config.CalculatePrebuildHash.
+func parseDevPodImages(out string) []devPodImage {
+	images := []devPodImage{}
+	for _, line := range strings.Split(out, "\n") {
+		id, reference, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+
+		tag := reference[strings.LastIndex(reference, ":")+1:]
+		if prebuildHashTagRegEx.MatchString(tag) {
+			images = append(images, devPodImage{id: id, reference: reference})
+		}
+	}
+
+	return images
+}
+
+func isOrphanedVolume(volume string, known *knownWorkspaces) bool {
+	for _, prefix := range pruneVolumePrefixes {
+		if strings.HasPrefix(volume, prefix) {
+			return !known.all[strings.TrimPrefix(volume, prefix)]
+		}
+	}
+
+	return false
+}
+
+func findAgentPruneCandidates(known *knownWorkspaces) ([]*PruneCandidate, error) {
+	agentHome, err := agent.FindAgentHomeFolder("")
+	if err != nil {
+		// no local agent folder, so there is nothing to prune
+		return nil, nil
+	}
+
+	contextsDir := filepath.Join(agentHome, "contexts")
+	contexts, err := os.ReadDir(contextsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	candidates := []*PruneCandidate{}
+	for _, contextEntry := range contexts {
+		workspacesDir := filepath.Join(contextsDir, contextEntry.Name(), "workspaces")
+		workspaces, err := os.ReadDir(workspacesDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, workspaceEntry := range workspaces {
+			if !workspaceEntry.IsDir() || known.ids[contextEntry.Name()][workspaceEntry.Name()] {
+				continue
+			}
+
+			workspaceDir := filepath.Join(workspacesDir, workspaceEntry.Name())
+			candidates = append(candidates, &PruneCandidate{
+				Type: PruneTypeAgentWorkspace,
+				Name: workspaceDir,
+				remove: func(ctx context.Context) error {
+					return os.RemoveAll(workspaceDir)
+				},
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func findSSHPruneCandidates(known *knownWorkspaces) ([]*PruneCandidate, error) {
+	candidates := []*PruneCandidate{}
+	for _, sshConfigPath := range known.sshConfigPaths {
+		hosts, err := ssh.GetDevPodHosts(sshConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("read ssh config %s: %w", sshConfigPath, err)
+		}
+
+		for _, host := range hosts {
+			workspaceID, found := strings.CutSuffix(host, ".devpod")
+			if !found || known.all[workspaceID] {
+				continue
+			}
+
+			path := sshConfigPath
+			candidates = append(candidates, &PruneCandidate{
+				Type: PruneTypeSSHHost,
+				Name: host + " (" + path + ")",
+				remove: func(ctx context.Context) error {
+					return ssh.RemoveFromConfig(workspaceID, path, log.Discard)
+				},
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func listDockerIDs(ctx context.Context, dockerHelper *docker.DockerHelper, args ...string) ([]string, error) {
+	out, err := dockerOutput(ctx, dockerHelper, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		id := strings.TrimSpace(string(line))
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func dockerOutput(ctx context.Context, dockerHelper *docker.DockerHelper, args ...string) ([]byte, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := dockerHelper.Run(ctx, args, nil, stdout, stderr)
+	if err != nil {
+		return nil, fmt.Errorf("docker %s: %s: %w", strings.Join(args[:min(2, len(args))], " "), strings.TrimSpace(stderr.String()), err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+package workspace
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseDevPodImages(t *testing.T) {
+	out := `sha256:aaa my-project-1a2b3:devpod-0123456789abcdef0123456789abcdef
+sha256:bbb alpine:3.19
+sha256:ccc localhost:5000/prebuilds:devpod-fedcba9876543210fedcba9876543210
+sha256:ddd <none>:<none>
+sha256:eee my-project-1a2b3:devpod-latest
+`
+
+	images := parseDevPodImages(out)
+	assert.Equal(t, len(images), 2)
+	assert.Equal(t, images[0].id, "sha256:aaa")
+	assert.Equal(t, images[0].reference, "my-project-1a2b3:devpod-0123456789abcdef0123456789abcdef")
+	assert.Equal(t, images[1].id, "sha256:ccc")
+	assert.Equal(t, images[1].reference, "localhost:5000/prebuilds:devpod-fedcba9876543210fedcba9876543210")
+}
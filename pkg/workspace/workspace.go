@@ -46,12 +46,9 @@ func Resolve(
 	log log.Logger,
 ) (client.BaseWorkspaceClient, error) {
 	// verify desired id
-	if desiredID != "" {
-		if providerpkg.ProviderNameRegEx.MatchString(desiredID) {
-			return nil, fmt.Errorf("workspace name can only include smaller case letters, numbers or dashes")
-		} else if len(desiredID) > 48 {
-			return nil, fmt.Errorf("workspace name cannot be longer than 48 characters")
-		}
+	err := validateWorkspaceID(desiredID)
+	if err != nil {
+		return nil, err
 	}
 
 	// resolve workspace
@@ -122,6 +119,18 @@ func Resolve(
 	return client, nil
 }
 
+func validateWorkspaceID(desiredID string) error {
+	if desiredID != "" {
+		if providerpkg.ProviderNameRegEx.MatchString(desiredID) {
+			return fmt.Errorf("workspace name can only include smaller case letters, numbers or dashes")
+		} else if len(desiredID) > 48 {
+			return fmt.Errorf("workspace name cannot be longer than 48 characters")
+		}
+	}
+
+	return nil
+}
+
 func getWorkspaceClient(devPodConfig *config.Config, provider *providerpkg.ProviderConfig, workspace *providerpkg.Workspace, machine *providerpkg.Machine, log log.Logger) (client.BaseWorkspaceClient, error) {
 	if provider.IsProxyProvider() {
 		return clientimplementation.NewProxyClient(devPodConfig, provider, workspace, log)